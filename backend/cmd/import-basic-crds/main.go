@@ -1,11 +1,14 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"regexp"
 	"strings"
 	"time"
@@ -16,8 +19,8 @@ import (
 )
 
 var (
-	slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
-	sources   = []string{
+	slugRegex      = regexp.MustCompile(`[^a-z0-9]+`)
+	defaultSources = []string{
 		"https://raw.githubusercontent.com/argoproj/argo-cd/stable/manifests/crds/application-crd.yaml",
 		"https://raw.githubusercontent.com/argoproj/argo-cd/stable/manifests/crds/appproject-crd.yaml",
 		"https://raw.githubusercontent.com/argoproj/argo-cd/stable/manifests/crds/applicationset-crd.yaml",
@@ -31,7 +34,15 @@ var (
 )
 
 func main() {
+	sourcesFile := flag.String("sources-file", "", "file of CRD source URLs, one per line; overrides IMPORT_SOURCES")
+	flag.Parse()
+
 	cfg := config.Load()
+	sources, err := resolveSources(*sourcesFile, cfg.ImportSources)
+	if err != nil {
+		panic(fmt.Errorf("load sources: %w", err))
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
@@ -41,45 +52,49 @@ func main() {
 	}
 	defer templateService.Close(context.Background())
 
-	crdService := services.NewCRDService()
+	crdService := services.NewCRDService(cfg)
 	client := &http.Client{Timeout: 20 * time.Second}
+	retry := retryPolicy{attempts: cfg.ImporterRetryAttempts, baseDelay: cfg.ImporterRetryBaseDelay}
 
 	imported := make([]string, 0, 32)
 	for _, source := range sources {
-		raw, err := fetchSource(ctx, client, source)
+		body, err := openSource(ctx, client, source, retry)
 		if err != nil {
 			fmt.Printf("[WARN] fetch failed: %s (%v)\n", source, err)
 			continue
 		}
 
-		crdDocs, err := extractCRDDocuments(raw)
-		if err != nil {
-			fmt.Printf("[WARN] decode failed: %s (%v)\n", source, err)
-			continue
-		}
-		if len(crdDocs) == 0 {
-			fmt.Printf("[WARN] no CRDs found in: %s\n", source)
-			continue
-		}
-
-		for _, doc := range crdDocs {
+		found, err := streamCRDDocuments(body, func(doc string) {
 			template, err := crdService.ParseCRD(doc)
 			if err != nil {
 				fmt.Printf("[WARN] parse failed from %s: %v\n", source, err)
-				continue
+				return
 			}
 
 			group := strings.SplitN(template.APIVersion, "/", 2)[0]
 			template.ID = normalizeTemplateID(template.Kind, group)
+			template.ID, err = services.UniqueTemplateID(ctx, templateService, template)
+			if err != nil {
+				fmt.Printf("[WARN] id lookup failed for %s from %s: %v\n", template.Kind, source, err)
+				return
+			}
 			template.Title = fmt.Sprintf("%s (%s)", template.Kind, group)
 			template.Note = "Imported from official upstream CRD source."
 
 			if err := templateService.Upsert(ctx, template); err != nil {
 				fmt.Printf("[WARN] upsert failed for %s from %s: %v\n", template.ID, source, err)
-				continue
+				return
 			}
 
 			imported = append(imported, template.ID)
+		})
+		body.Close()
+		if err != nil {
+			fmt.Printf("[WARN] decode failed: %s after %d CRDs (%v)\n", source, found, err)
+			continue
+		}
+		if found == 0 {
+			fmt.Printf("[WARN] no CRDs found in: %s\n", source)
 		}
 	}
 
@@ -95,42 +110,148 @@ func main() {
 	}
 }
 
-func fetchSource(ctx context.Context, client *http.Client, url string) (string, error) {
+// resolveSources picks the URLs to import: a sources file wins over the
+// IMPORT_SOURCES list, which wins over the built-in defaults.
+func resolveSources(path string, configured []string) ([]string, error) {
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return readSources(file)
+	}
+	if len(configured) > 0 {
+		return configured, nil
+	}
+	return defaultSources, nil
+}
+
+// readSources reads one URL per line, skipping blank lines and # comments.
+func readSources(r io.Reader) ([]string, error) {
+	sources := make([]string, 0, 16)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sources = append(sources, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("sources file lists no URLs")
+	}
+	return sources, nil
+}
+
+// retryPolicy controls how often a source is fetched before giving up. The
+// delay before each retry doubles, starting from baseDelay.
+type retryPolicy struct {
+	attempts  int
+	baseDelay time.Duration
+}
+
+// statusError reports a non-2xx response. Only server errors and rate limits
+// are worth retrying; other client errors will not change on a second try.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("http %d", e.code)
+}
+
+func (e *statusError) retryable() bool {
+	return e.code >= 500 || e.code == http.StatusTooManyRequests
+}
+
+// openSource fetches url with retries, backing off between attempts on network
+// errors and 5xx/429 responses.
+func openSource(ctx context.Context, client *http.Client, url string, policy retryPolicy) (io.ReadCloser, error) {
+	attempts := policy.attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := policy.baseDelay
+	for attempt := 1; ; attempt++ {
+		body, err := fetchSource(ctx, client, url)
+		if err == nil || attempt >= attempts || !shouldRetry(ctx, err) {
+			return body, err
+		}
+
+		fmt.Printf("[RETRY] attempt %d/%d for %s failed (%v), retrying in %s\n", attempt, attempts, url, err, delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+func shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.retryable()
+	}
+	return true
+}
+
+// fetchSource starts the download and hands back the response body capped at
+// 5MB, so callers can decode it incrementally instead of buffering it whole.
+func fetchSource(ctx context.Context, client *http.Client, url string) (io.ReadCloser, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	req.Header.Set("User-Agent", "kubetools-basic-crd-importer/1.0")
 	req.Header.Set("Accept", "application/yaml, text/plain, */*")
+	req.Header.Set("Accept-Encoding", "gzip")
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("http %d", resp.StatusCode)
+		resp.Body.Close()
+		return nil, &statusError{code: resp.StatusCode}
 	}
 
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 5*1024*1024))
+	body, err := services.DecodedBody(resp)
 	if err != nil {
-		return "", err
+		resp.Body.Close()
+		return nil, err
 	}
-	return string(body), nil
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(body, 5*1024*1024), body}, nil
 }
 
-func extractCRDDocuments(raw string) ([]string, error) {
-	decoder := yaml.NewDecoder(strings.NewReader(raw))
-	out := make([]string, 0, 8)
+// streamCRDDocuments decodes one YAML document at a time and hands each CRD to
+// handle as soon as it is read, so a failure while handling one document never
+// discards the others. The returned count covers every CRD seen before any
+// decode error.
+func streamCRDDocuments(r io.Reader, handle func(doc string)) (int, error) {
+	decoder := yaml.NewDecoder(r)
+	found := 0
 	for {
 		doc := map[string]any{}
 		err := decoder.Decode(&doc)
 		if errors.Is(err, io.EOF) {
-			break
+			return found, nil
 		}
 		if err != nil {
-			return nil, err
+			return found, err
 		}
 		if len(doc) == 0 {
 			continue
@@ -140,11 +261,11 @@ func extractCRDDocuments(raw string) ([]string, error) {
 		}
 		encoded, err := yaml.Marshal(doc)
 		if err != nil {
-			return nil, err
+			return found, err
 		}
-		out = append(out, string(encoded))
+		found++
+		handle(string(encoded))
 	}
-	return out, nil
 }
 
 func asString(value any) string {
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamCRDDocumentsHandlesEachDocumentIndependently(t *testing.T) {
+	stream := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: certificates.cert-manager.io
+spec:
+  group: cert-manager.io
+  names:
+    kind: Certificate
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: not-a-crd
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: issuers.cert-manager.io
+spec:
+  group: cert-manager.io
+  names:
+    kind: Issuer
+`
+
+	upserted := make([]string, 0, 2)
+	calls := 0
+	found, err := streamCRDDocuments(strings.NewReader(stream), func(doc string) {
+		calls++
+		if strings.Contains(doc, "kind: Certificate") {
+			// Simulate an upsert failure for this document only.
+			return
+		}
+		upserted = append(upserted, doc)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if found != 2 || calls != 2 {
+		t.Fatalf("expected 2 CRDs handled one at a time, got found=%d calls=%d", found, calls)
+	}
+	if len(upserted) != 1 || !strings.Contains(upserted[0], "kind: Issuer") {
+		t.Fatalf("expected Issuer to be upserted despite the earlier failure, got %v", upserted)
+	}
+}
+
+func TestStreamCRDDocumentsKeepsProgressBeforeDecodeError(t *testing.T) {
+	stream := `
+kind: CustomResourceDefinition
+spec:
+  names:
+    kind: Good
+---
+kind: CustomResourceDefinition
+spec: [unclosed
+`
+
+	handled := 0
+	found, err := streamCRDDocuments(strings.NewReader(stream), func(doc string) {
+		handled++
+	})
+	if err == nil {
+		t.Fatalf("expected decode error for malformed document")
+	}
+	if found != 1 || handled != 1 {
+		t.Fatalf("expected first CRD to be handled before the error, got found=%d handled=%d", found, handled)
+	}
+}
+
+func TestOpenSourceRetriesOnlyTransientFailures(t *testing.T) {
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		switch {
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case calls[r.URL.Path] < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte("kind: CustomResourceDefinition\n"))
+		}
+	}))
+	defer server.Close()
+
+	policy := retryPolicy{attempts: 3, baseDelay: time.Millisecond}
+	body, err := openSource(context.Background(), server.Client(), server.URL+"/flaky", policy)
+	if err != nil {
+		t.Fatalf("expected flaky source to succeed on the third attempt, got %v", err)
+	}
+	content, _ := io.ReadAll(body)
+	body.Close()
+	if calls["/flaky"] != 3 || !strings.Contains(string(content), "CustomResourceDefinition") {
+		t.Fatalf("expected 3 attempts and the CRD body, got %d attempts and %q", calls["/flaky"], content)
+	}
+
+	if _, err := openSource(context.Background(), server.Client(), server.URL+"/missing", policy); err == nil {
+		t.Fatalf("expected 404 to fail")
+	}
+	if calls["/missing"] != 1 {
+		t.Fatalf("expected 404 not to be retried, got %d attempts", calls["/missing"])
+	}
+}
+
+func TestReadSourcesSkipsBlankLinesAndComments(t *testing.T) {
+	file := `
+# argo
+https://example.com/a.yaml
+
+  https://example.com/b.yaml  
+# disabled: https://example.com/c.yaml
+`
+	sources, err := readSources(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(sources) != 2 || sources[0] != "https://example.com/a.yaml" || sources[1] != "https://example.com/b.yaml" {
+		t.Fatalf("unexpected sources: %v", sources)
+	}
+
+	configured, err := resolveSources("", []string{"https://example.com/env.yaml"})
+	if err != nil || len(configured) != 1 || configured[0] != "https://example.com/env.yaml" {
+		t.Fatalf("expected IMPORT_SOURCES to replace the defaults, got %v (%v)", configured, err)
+	}
+}
+
+func TestFetchSourceDecompressesGzipBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write([]byte("kind: CustomResourceDefinition\n"))
+		_ = zw.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	body, err := fetchSource(context.Background(), server.Client(), server.URL+"/bundle.yaml")
+	if err != nil {
+		t.Fatalf("expected gzipped source to be fetched, got %v", err)
+	}
+	content, _ := io.ReadAll(body)
+	body.Close()
+	if string(content) != "kind: CustomResourceDefinition\n" {
+		t.Fatalf("expected decompressed YAML, got %q", content)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"log"
 	"net/http"
 	"os"
@@ -10,57 +11,108 @@ import (
 	"time"
 
 	"github.com/aneeshchawla/kubetools/backend/internal/api"
+	"github.com/aneeshchawla/kubetools/backend/internal/api/middleware"
 	"github.com/aneeshchawla/kubetools/backend/internal/config"
 	"github.com/aneeshchawla/kubetools/backend/internal/services"
 )
 
 func main() {
 	cfg := config.Load()
+	if err := cfg.ValidateTLS(); err != nil {
+		log.Fatalf("invalid TLS configuration: %v", err)
+	}
+
+	templateStore, manifestStore := openStores(context.Background(), cfg)
+	crdService := services.NewCRDService(cfg)
+	yamlService := services.NewYAMLService()
+
+	router := api.NewRouter(api.Dependencies{
+		CORSOrigins:       cfg.CORSOrigins,
+		Debug:             cfg.Debug,
+		Templates:         templateStore,
+		CRD:               crdService,
+		YAML:              yamlService,
+		Manifests:         manifestStore,
+		Cluster:           services.NewClusterImporter(cfg.KubeconfigPath, cfg.KubeContext, cfg.StripPaths),
+		AdminToken:        cfg.AdminToken,
+		TrustedProxies:    cfg.TrustedProxies,
+		ImportRateLimiter: middleware.NewRateLimiter(cfg.ImportURLRateLimit, cfg.ImportURLRateBurst),
+	})
+
+	server := newHTTPServer(cfg, router)
+
+	go func() {
+		var err error
+		if cfg.TLSEnabled() {
+			log.Printf("backend listening on https://%s", server.Addr)
+			err = server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			log.Printf("backend listening on http://%s", server.Addr)
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatalf("server failed: %v", err)
+		}
+	}()
+
+	waitForShutdown(server, templateStore, manifestStore)
+}
 
-	templateService, err := services.NewTemplateService(context.Background(), cfg)
+func openStores(ctx context.Context, cfg config.Config) (services.TemplateStore, services.ManifestStore) {
+	switch cfg.StorageBackend {
+	case "sqlite":
+		templateStore, err := services.NewSQLiteTemplateStore(ctx, cfg.SQLitePath, cfg.TemplateSeedFile)
+		if err != nil {
+			log.Fatalf("initialize template store: %v", err)
+		}
+		manifestStore, err := services.NewSQLiteManifestStore(ctx, cfg.SQLitePath)
+		if err != nil {
+			log.Fatalf("initialize manifest store: %v", err)
+		}
+		log.Printf("using sqlite storage at %s", cfg.SQLitePath)
+		return templateStore, manifestStore
+	case "", "mongo":
+	default:
+		log.Fatalf("unsupported STORAGE_BACKEND %q (expected mongo or sqlite)", cfg.StorageBackend)
+	}
+
+	templateService, err := services.NewTemplateService(ctx, cfg)
 	if err != nil {
 		log.Printf("initialize template service: %v (falling back to in-memory templates)", err)
 	}
 	if templateService == nil {
 		log.Fatalf("initialize template service: no service available")
 	}
-	crdService := services.NewCRDService()
-	yamlService := services.NewYAMLService()
-	manifestService, err := services.NewManifestService(context.Background(), cfg)
+	manifestService, err := services.NewManifestService(ctx, cfg)
 	if err != nil {
 		log.Printf("initialize manifest service: %v (falling back to in-memory history)", err)
 	}
 	if manifestService == nil {
 		log.Fatalf("initialize manifest service: no service available")
 	}
+	return templateService, manifestService
+}
 
-	router := api.NewRouter(api.Dependencies{
-		CORSOrigins: cfg.CORSOrigins,
-		Templates:   templateService,
-		CRD:         crdService,
-		YAML:        yamlService,
-		Manifests:   manifestService,
-	})
-
+func newHTTPServer(cfg config.Config, handler http.Handler) *http.Server {
 	server := &http.Server{
-		Addr:         cfg.Host + ":" + cfg.Port,
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  30 * time.Second,
+		Addr:              cfg.Host + ":" + cfg.Port,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
 	}
-
-	go func() {
-		log.Printf("backend listening on http://%s", server.Addr)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("server failed: %v", err)
+	if cfg.TLSEnabled() {
+		// ListenAndServeTLS negotiates HTTP/2 via ALPN when h2 is advertised.
+		server.TLSConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			NextProtos: []string{"h2", "http/1.1"},
 		}
-	}()
-
-	waitForShutdown(server, templateService, manifestService)
+	}
+	return server
 }
 
-func waitForShutdown(server *http.Server, templateService *services.TemplateService, manifestService *services.ManifestService) {
+func waitForShutdown(server *http.Server, templateStore services.TemplateStore, manifestStore services.ManifestStore) {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	<-sigCh
@@ -72,11 +124,11 @@ func waitForShutdown(server *http.Server, templateService *services.TemplateServ
 		log.Printf("graceful shutdown error: %v", err)
 		return
 	}
-	if err := templateService.Close(ctx); err != nil {
-		log.Printf("template mongodb disconnect error: %v", err)
+	if err := templateStore.Close(ctx); err != nil {
+		log.Printf("template store close error: %v", err)
 	}
-	if err := manifestService.Close(ctx); err != nil {
-		log.Printf("manifest mongodb disconnect error: %v", err)
+	if err := manifestStore.Close(ctx); err != nil {
+		log.Printf("manifest store close error: %v", err)
 	}
 	log.Print("server shutdown complete")
 }
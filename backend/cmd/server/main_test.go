@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+)
+
+func TestNewHTTPServerUsesConfiguredTimeouts(t *testing.T) {
+	cfg := config.Config{
+		Host:              "127.0.0.1",
+		Port:              "9090",
+		ReadTimeout:       11 * time.Second,
+		WriteTimeout:      45 * time.Second,
+		IdleTimeout:       90 * time.Second,
+		ReadHeaderTimeout: 3 * time.Second,
+	}
+
+	server := newHTTPServer(cfg, http.NewServeMux())
+
+	if server.Addr != "127.0.0.1:9090" {
+		t.Fatalf("expected addr 127.0.0.1:9090, got %s", server.Addr)
+	}
+	if server.ReadTimeout != cfg.ReadTimeout || server.WriteTimeout != cfg.WriteTimeout ||
+		server.IdleTimeout != cfg.IdleTimeout || server.ReadHeaderTimeout != cfg.ReadHeaderTimeout {
+		t.Fatalf("expected configured timeouts, got read=%s write=%s idle=%s header=%s",
+			server.ReadTimeout, server.WriteTimeout, server.IdleTimeout, server.ReadHeaderTimeout)
+	}
+	if server.TLSConfig != nil {
+		t.Fatalf("expected no TLS config without cert and key")
+	}
+
+	cfg.TLSCertFile = "cert.pem"
+	cfg.TLSKeyFile = "key.pem"
+	server = newHTTPServer(cfg, http.NewServeMux())
+	if server.TLSConfig == nil || !slices.Contains(server.TLSConfig.NextProtos, "h2") {
+		t.Fatalf("expected HTTP/2 to be advertised when TLS is enabled")
+	}
+}
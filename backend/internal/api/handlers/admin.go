@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"github.com/aneeshchawla/kubetools/backend/internal/services"
+)
+
+// AdminHandler serves operator endpoints. They require the configured bearer
+// token and are disabled entirely when no token is set.
+type AdminHandler struct {
+	token     string
+	manifests services.ManifestStore
+}
+
+func NewAdminHandler(token string, manifestStore services.ManifestStore) *AdminHandler {
+	return &AdminHandler{token: token, manifests: manifestStore}
+}
+
+func (h *AdminHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if h.token == "" {
+		WriteError(w, http.StatusForbidden, "ADMIN_DISABLED", "admin endpoints are disabled; set ADMIN_TOKEN to enable them")
+		return false
+	}
+	if !hasAdminToken(r, h.token) {
+		WriteError(w, http.StatusUnauthorized, "UNAUTHORIZED", "a valid admin bearer token is required")
+		return false
+	}
+	return true
+}
+
+// hasAdminToken reports whether r carries token as its bearer token. An
+// empty token never matches.
+func hasAdminToken(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
+
+func (h *AdminHandler) CompactManifests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+
+	var payload models.CompactManifestsRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	compactor, ok := h.manifests.(services.ManifestCompactor)
+	if !ok {
+		WriteError(w, http.StatusNotImplemented, "NOT_SUPPORTED", "the configured manifest store does not support compaction")
+		return
+	}
+	result, err := compactor.Compact(r.Context(), payload)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "COMPACT_FAILED", err.Error())
+		return
+	}
+	WriteSuccess(w, http.StatusOK, result)
+}
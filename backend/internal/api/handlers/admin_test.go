@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"github.com/aneeshchawla/kubetools/backend/internal/services"
+)
+
+func TestAdminCompactRequiresToken(t *testing.T) {
+	cases := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{name: "disabled", token: "", header: "Bearer anything", want: http.StatusForbidden},
+		{name: "missing", token: "s3cret", header: "", want: http.StatusUnauthorized},
+		{name: "wrong", token: "s3cret", header: "Bearer nope", want: http.StatusUnauthorized},
+		{name: "valid", token: "s3cret", header: "Bearer s3cret", want: http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewAdminHandler(tc.token, &services.ManifestService{})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/compact", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.CompactManifests(rec, req)
+			if rec.Code != tc.want {
+				t.Fatalf("expected status %d, got %d with body: %s", tc.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminCompactCollapsesDuplicatesInMemoryMode(t *testing.T) {
+	manifests := &services.ManifestService{}
+	saved := make([]models.ManifestRecord, 0, 2)
+	for range 2 {
+		record, err := manifests.SaveManifest(context.Background(), models.SaveManifestRequest{
+			Title: "web",
+			YAML:  "apiVersion: v1\nkind: ConfigMap\n",
+		})
+		if err != nil {
+			t.Fatalf("save manifest: %v", err)
+		}
+		saved = append(saved, record)
+	}
+	handler := NewAdminHandler("s3cret", manifests)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/compact", strings.NewReader(`{"collapseDuplicates":true}`))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	handler.CompactManifests(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.CompactManifestsResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	result := envelope.Data
+	if result.Mode != "memory" || result.Duplicates != 1 || result.Expired != 0 || result.Trimmed != 0 || result.Remaining != 1 {
+		t.Fatalf("unexpected compact result: %+v", result)
+	}
+	if len(result.RemovedIDs) != 1 || result.RemovedIDs[0] != saved[0].ID {
+		t.Fatalf("expected the older copy %s to be removed, got %v", saved[0].ID, result.RemovedIDs)
+	}
+}
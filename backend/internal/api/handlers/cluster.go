@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"github.com/aneeshchawla/kubetools/backend/internal/services"
+)
+
+// ClusterHandler imports CRDs from a live cluster. Requests without an
+// uploaded kubeconfig use the server's own credentials, so they require the
+// admin bearer token.
+type ClusterHandler struct {
+	importer   *services.ClusterImporter
+	crd        *services.CRDService
+	adminToken string
+}
+
+func NewClusterHandler(importer *services.ClusterImporter, crdService *services.CRDService, adminToken string) *ClusterHandler {
+	return &ClusterHandler{importer: importer, crd: crdService, adminToken: adminToken}
+}
+
+func (h *ClusterHandler) ImportCRD(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.ImportClusterCRDRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+	if strings.TrimSpace(payload.Kubeconfig) == "" && !hasAdminToken(r, h.adminToken) {
+		WriteError(w, http.StatusUnauthorized, "UNAUTHORIZED", "upload a kubeconfig; the server kubeconfig requires a valid admin bearer token")
+		return
+	}
+
+	sourceURL, raw, err := h.importer.FetchCRD(r.Context(), payload)
+	switch {
+	case errors.Is(err, services.ErrClusterCRDNotFound):
+		WriteError(w, http.StatusNotFound, "CRD_NOT_FOUND", err.Error())
+		return
+	case err != nil:
+		WriteError(w, http.StatusBadRequest, "CRD_IMPORT_FAILED", err.Error())
+		return
+	}
+
+	response := models.ImportClusterCRDResponse{
+		SourceURL:  sourceURL,
+		Raw:        raw,
+		Validation: h.crd.ValidateCRD(raw),
+	}
+	if response.Validation.Valid {
+		template, err := h.crd.ParseCRD(raw)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+			return
+		}
+		response.Template = &template
+	}
+	WriteSuccess(w, http.StatusOK, response)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"github.com/aneeshchawla/kubetools/backend/internal/services"
+)
+
+const clusterWidgetCRDJSON = `{
+  "apiVersion": "apiextensions.k8s.io/v1",
+  "kind": "CustomResourceDefinition",
+  "metadata": {"name": "widgets.example.io", "resourceVersion": "12345", "uid": "abc"},
+  "spec": {
+    "group": "example.io",
+    "names": {"kind": "Widget", "plural": "widgets"},
+    "scope": "Namespaced",
+    "versions": [{
+      "name": "v1", "served": true, "storage": true,
+      "schema": {"openAPIV3Schema": {"type": "object", "properties": {
+        "spec": {"type": "object", "required": ["size"], "properties": {
+          "size": {"type": "string"},
+          "budget": {"type": "integer", "default": 1000000, "maximum": 2147483647}
+        }}
+      }}}
+    }]
+  },
+  "status": {"acceptedNames": {"kind": "Widget"}}
+}`
+
+func TestImportCRDFromClusterUsesServerKubeconfigForAdmins(t *testing.T) {
+	apiServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/apis/apiextensions.k8s.io/v1/customresourcedefinitions/widgets.example.io":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(clusterWidgetCRDJSON))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer apiServer.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: apiServer.Certificate().Raw})
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+  - name: fake
+    cluster:
+      server: %s
+      certificate-authority-data: %s
+users:
+  - name: tester
+    user:
+      token: test-token
+contexts:
+  - name: test
+    context:
+      cluster: fake
+      user: tester
+`, apiServer.URL, base64.StdEncoding.EncodeToString(caPEM))
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(path, []byte(kubeconfig), 0o600); err != nil {
+		t.Fatalf("write kubeconfig: %v", err)
+	}
+
+	handler := NewClusterHandler(services.NewClusterImporter(path, "", nil), services.NewCRDService(config.Config{}), "admin-secret")
+	importCRD := func(name string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.ImportClusterCRDRequest{Name: name})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/crd/import-cluster", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer admin-secret")
+		rec := httptest.NewRecorder()
+		handler.ImportCRD(rec, req)
+		return rec
+	}
+
+	rec := importCRD("widgets.example.io")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.ImportClusterCRDResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if envelope.Data.Template == nil || envelope.Data.Template.Kind != "Widget" || envelope.Data.Template.APIVersion != "example.io/v1" {
+		t.Fatalf("expected parsed Widget template, got %+v", envelope.Data.Template)
+	}
+	for _, kept := range []string{"default: 1000000", "maximum: 2147483647"} {
+		if !strings.Contains(envelope.Data.Raw, kept) {
+			t.Fatalf("expected large integer %q to survive the import, got %s", kept, envelope.Data.Raw)
+		}
+	}
+	for _, dropped := range []string{"resourceVersion", "acceptedNames"} {
+		if strings.Contains(envelope.Data.Raw, dropped) {
+			t.Fatalf("expected server-managed field %q to be stripped, got %s", dropped, envelope.Data.Raw)
+		}
+	}
+
+	if rec = importCRD("gadgets.example.io"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d for a missing CRD, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestImportCRDFromClusterRequiresKubeconfig(t *testing.T) {
+	handler := NewClusterHandler(services.NewClusterImporter("", "", nil), services.NewCRDService(config.Config{}), "admin-secret")
+	rec := httptest.NewRecorder()
+	handler.ImportCRD(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/import-cluster", strings.NewReader(`{"name":"widgets.example.io"}`)))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d for the server kubeconfig without a token, got %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/crd/import-cluster", strings.NewReader(`{"name":"widgets.example.io"}`))
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	rec = httptest.NewRecorder()
+	handler.ImportCRD(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d without a configured kubeconfig, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestImportCRDFromClusterRejectsInternalUploadedServers(t *testing.T) {
+	handler := NewClusterHandler(services.NewClusterImporter("", "", nil), services.NewCRDService(config.Config{}), "")
+	for _, server := range []string{
+		"http://kubernetes.example.com",
+		"https://127.0.0.1:6443",
+		"https://localhost:6443",
+		"https://10.0.0.1",
+		"https://169.254.169.254",
+		"https://[::1]:6443",
+	} {
+		kubeconfig := fmt.Sprintf(`clusters:
+  - name: c
+    cluster:
+      server: %s
+users:
+  - name: u
+    user:
+      token: t
+contexts:
+  - name: x
+    context:
+      cluster: c
+      user: u
+`, server)
+		body, _ := json.Marshal(models.ImportClusterCRDRequest{Name: "widgets.example.io", Kubeconfig: kubeconfig})
+		rec := httptest.NewRecorder()
+		handler.ImportCRD(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/import-cluster", bytes.NewReader(body)))
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "public https API server") {
+			t.Fatalf("expected %s to be rejected, got %d: %s", server, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/aneeshchawla/kubetools/backend/internal/models"
@@ -30,3 +31,38 @@ func WriteError(w http.ResponseWriter, status int, code, message string) {
 		Timestamp: time.Now().UTC(),
 	})
 }
+
+// requestTeam identifies the caller's team from the X-Team header, falling
+// back to the team query parameter. An empty result means no team.
+func requestTeam(r *http.Request) string {
+	if team := strings.TrimSpace(r.Header.Get("X-Team")); team != "" {
+		return team
+	}
+	return strings.TrimSpace(r.URL.Query().Get("team"))
+}
+
+// wantsMarkdown reports whether the caller asked for format=markdown.
+func wantsMarkdown(r *http.Request) bool {
+	return strings.EqualFold(r.URL.Query().Get("format"), "markdown")
+}
+
+// invalidStatus is the status used to report a document that failed
+// validation. Callers opt into 422 Unprocessable Entity with
+// ?invalidStatus=422 or an X-Invalid-Status: 422 header; everyone else keeps
+// getting 200 with Valid set to false.
+func invalidStatus(r *http.Request) int {
+	value := strings.TrimSpace(r.Header.Get("X-Invalid-Status"))
+	if value == "" {
+		value = strings.TrimSpace(r.URL.Query().Get("invalidStatus"))
+	}
+	if value == "422" {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusOK
+}
+
+func writeMarkdown(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body))
+}
@@ -2,7 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	neturl "net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -11,23 +17,23 @@ import (
 )
 
 type CRDHandler struct {
-	templates *services.TemplateService
+	templates services.TemplateStore
 	crd       *services.CRDService
 	yaml      *services.YAMLService
-	manifests *services.ManifestService
+	manifests services.ManifestStore
 }
 
 func NewCRDHandler(
-	templateService *services.TemplateService,
+	templateStore services.TemplateStore,
 	crdService *services.CRDService,
 	yamlService *services.YAMLService,
-	manifestService *services.ManifestService,
+	manifestStore services.ManifestStore,
 ) *CRDHandler {
 	return &CRDHandler{
-		templates: templateService,
+		templates: templateStore,
 		crd:       crdService,
 		yaml:      yamlService,
-		manifests: manifestService,
+		manifests: manifestStore,
 	}
 }
 
@@ -41,7 +47,151 @@ func (h *CRDHandler) Templates(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, http.StatusInternalServerError, "TEMPLATE_LIST_FAILED", err.Error())
 		return
 	}
-	WriteSuccess(w, http.StatusOK, templates)
+	WriteSuccess(w, http.StatusOK, services.VisibleTemplates(templates, requestTeam(r)))
+}
+
+// ImportTemplateBundle upserts a JSON or YAML array of templates, reporting
+// how many were created, updated and rejected.
+func (h *CRDHandler) ImportTemplateBundle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+	templates, err := services.DecodeTemplateBundle(body)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, services.ImportTemplateBundle(r.Context(), h.templates, templates, requestTeam(r)))
+}
+
+// ExportTemplates downloads the visible templates as a bundle that
+// ImportTemplateBundle accepts. Built-ins are included with
+// ?includeBuiltins=true and YAML is selected with ?format=yaml.
+func (h *CRDHandler) ExportTemplates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is supported")
+		return
+	}
+
+	templates, err := h.templates.List(r.Context())
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "TEMPLATE_LIST_FAILED", err.Error())
+		return
+	}
+	includeBuiltins, _ := strconv.ParseBool(r.URL.Query().Get("includeBuiltins"))
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	output, err := services.ExportTemplateBundle(services.VisibleTemplates(templates, requestTeam(r)), includeBuiltins, format)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	contentType, filename := "application/json", "templates.json"
+	if format == "yaml" || format == "yml" {
+		contentType, filename = "application/yaml", "templates.yaml"
+	}
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(output)
+}
+
+func (h *CRDHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is supported")
+		return
+	}
+
+	template, ok := h.visibleTemplate(w, r, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, template)
+}
+
+// visibleTemplate loads a template the caller's team may see, writing the
+// error response otherwise. Other teams' templates are reported as not
+// found so their IDs are not disclosed.
+func (h *CRDHandler) visibleTemplate(w http.ResponseWriter, r *http.Request, id string) (models.TemplateDefinition, bool) {
+	template, err := h.templates.Get(r.Context(), id)
+	if err == nil && !services.TemplateVisible(template, requestTeam(r)) {
+		err = services.ErrTemplateNotFound
+	}
+	if errors.Is(err, services.ErrTemplateNotFound) {
+		WriteError(w, http.StatusNotFound, "TEMPLATE_NOT_FOUND", err.Error())
+		return models.TemplateDefinition{}, false
+	}
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "TEMPLATE_GET_FAILED", err.Error())
+		return models.TemplateDefinition{}, false
+	}
+	return template, true
+}
+
+func (h *CRDHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only DELETE is supported")
+		return
+	}
+
+	id := r.PathValue("id")
+	if _, ok := h.visibleTemplate(w, r, id); !ok {
+		return
+	}
+	err := h.templates.Delete(r.Context(), id)
+	if errors.Is(err, services.ErrTemplateNotFound) {
+		WriteError(w, http.StatusNotFound, "TEMPLATE_NOT_FOUND", err.Error())
+		return
+	}
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "TEMPLATE_DELETE_FAILED", err.Error())
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, map[string]string{"id": id, "status": "deleted"})
+}
+
+func (h *CRDHandler) PatchTemplate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only PATCH is supported")
+		return
+	}
+
+	var payload models.PatchTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+	if _, ok := h.visibleTemplate(w, r, r.PathValue("id")); !ok {
+		return
+	}
+
+	template, err := h.templates.Patch(r.Context(), r.PathValue("id"), payload)
+	switch {
+	case errors.Is(err, services.ErrTemplateNotFound):
+		WriteError(w, http.StatusNotFound, "TEMPLATE_NOT_FOUND", err.Error())
+		return
+	case errors.Is(err, services.ErrBuiltinTemplate):
+		WriteError(w, http.StatusConflict, "TEMPLATE_READ_ONLY", err.Error())
+		return
+	case errors.Is(err, services.ErrTemplateConflict):
+		WriteError(w, http.StatusConflict, "TEMPLATE_CONFLICT", err.Error())
+		return
+	case err != nil:
+		WriteError(w, http.StatusInternalServerError, "TEMPLATE_PATCH_FAILED", err.Error())
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, template)
 }
 
 func (h *CRDHandler) ParseCRD(w http.ResponseWriter, r *http.Request) {
@@ -56,76 +206,527 @@ func (h *CRDHandler) ParseCRD(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	template, err := h.crd.ParseCRD(payload.Raw)
+	strategy, err := services.ParsePrioritizationStrategy(payload.Prioritization)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	template, warnings, err := h.crd.ParseCRDWithOptions(payload.Raw, services.ParseOptions{
+		Prioritization:           strategy,
+		OmitInferredDescriptions: payload.OmitInferredDescriptions,
+		StripPaths:               payload.StripPaths,
+	})
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+		return
+	}
+
+	if strings.EqualFold(r.URL.Query().Get("format"), "csv") {
+		fields := append(append([]models.FieldDefinition(nil), template.DefaultFields...), template.OptionalFields...)
+		output, err := services.FieldsToCSV(fields)
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "CSV_EXPORT_FAILED", err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+template.ID+`.csv"`)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(output))
+		return
+	}
+
+	response := models.ParseCRDResponse{Template: template, Warnings: warnings}
+	if payload.IncludeAllFields {
+		allFields, err := h.crd.AllFields(payload.Raw)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+			return
+		}
+		response.AllFields = allFields
+	}
+	if payload.IncludeSkippedFields {
+		skippedFields, err := h.crd.SkippedFields(payload.Raw, template)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+			return
+		}
+		response.SkippedFields = skippedFields
+	}
+
+	WriteSuccess(w, http.StatusOK, response)
+}
+
+func (h *CRDHandler) JSONPaths(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.JSONPathsRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	paths, err := h.crd.JSONPaths(payload.Raw)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+		return
+	}
+	WriteSuccess(w, http.StatusOK, models.JSONPathsResponse{Paths: paths})
+}
+
+// VersionDiff compares the schemas of two versions declared by a CRD and
+// flags removed fields and type changes as breaking.
+func (h *CRDHandler) VersionDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.VersionDiffRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	result, err := h.crd.DiffCRDVersions(payload.Raw, payload.From, payload.To)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+		return
+	}
+	WriteSuccess(w, http.StatusOK, result)
+}
+
+// GenerateRBAC renders a Role and RoleBinding (or their cluster-wide
+// equivalents) granting edit access to the resources a CRD defines.
+func (h *CRDHandler) GenerateRBAC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.GenerateRBACRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	result, err := h.crd.GenerateRBAC(payload)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+		return
+	}
+	WriteSuccess(w, http.StatusOK, result)
+}
+
+func (h *CRDHandler) ValidateCRD(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.ValidateCRDRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	result := h.crd.ValidateCRD(payload.Raw)
+	if payload.Strict {
+		result = services.StrictValidation(result)
+	}
+	if !result.Valid {
+		WriteSuccess(w, invalidStatus(r), result)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, result)
+}
+
+func (h *CRDHandler) ValidateInstance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.ValidateInstanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, h.crd.ValidateInstance(payload.CRD, payload.Instance))
+}
+
+func (h *CRDHandler) StripYAML(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.StripYAMLRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	stripped, removed, err := h.yaml.StripFields(payload.Raw, payload.Paths)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "STRIP_FAILED", err.Error())
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, models.StripYAMLResponse{YAML: stripped, Removed: removed})
+}
+
+func (h *CRDHandler) CompareYAML(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.CompareYAMLRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	result, err := h.yaml.CompareYAML(payload.Left, payload.Right)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "COMPARE_FAILED", err.Error())
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, result)
+}
+
+// FieldDiff compares a base and an edited field set and returns the changed
+// paths with a generate-yaml request limited to the edits.
+func (h *CRDHandler) FieldDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.FieldDiffRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, h.yaml.DiffFields(payload))
+}
+
+func (h *CRDHandler) GenerateYAML(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.GenerateYAMLRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	yamlOutput, object, err := h.yaml.GenerateYAMLWithObject(payload)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "GENERATION_FAILED", err.Error())
+		return
+	}
+
+	if wantsMarkdown(r) {
+		writeMarkdown(w, http.StatusOK, services.YAMLToMarkdown(payload.Kind+" ("+payload.APIVersion+")", yamlOutput))
+		return
+	}
+
+	response := models.GenerateYAMLResponse{YAML: yamlOutput}
+	if payload.IncludeObject {
+		response.Object = object
+	}
+	WriteSuccess(w, http.StatusOK, response)
+}
+
+// ExportHelmValues renders a starter values.yaml from a CRD's spec schema.
+func (h *CRDHandler) ExportHelmValues(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.ExportHelmValuesRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	values, err := h.crd.ExportHelmValues(payload.Raw)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+		return
+	}
+	WriteSuccess(w, http.StatusOK, models.ExportHelmValuesResponse{Values: values})
+}
+
+// SchemaAt returns the raw schema subtree of a CRD at a field path.
+func (h *CRDHandler) SchemaAt(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.SchemaAtRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	result, err := h.crd.SchemaAt(payload.Raw, payload.Path)
+	if errors.Is(err, services.ErrSchemaPathNotFound) {
+		WriteError(w, http.StatusNotFound, "SCHEMA_PATH_NOT_FOUND", err.Error())
+		return
+	}
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+		return
+	}
+	WriteSuccess(w, http.StatusOK, result)
+}
+
+// GeneratePreview renders best-effort YAML for a live editor, reporting what
+// is missing instead of failing like GenerateYAML does.
+func (h *CRDHandler) GeneratePreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.GenerateYAMLRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	preview, err := h.yaml.PreviewYAML(payload)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "GENERATION_FAILED", err.Error())
+		return
+	}
+	WriteSuccess(w, http.StatusOK, preview)
+}
+
+// PodSpec builds the containers array of a workload from a plain container
+// list and returns both the indexed fields and the rendered YAML.
+func (h *CRDHandler) PodSpec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.PodSpecRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	result, err := h.yaml.BuildPodSpec(payload)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "GENERATION_FAILED", err.Error())
+		return
+	}
+	WriteSuccess(w, http.StatusOK, result)
+}
+
+func (h *CRDHandler) GenerateMultiYAML(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.GenerateMultiYAMLRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	yamlOutput, err := h.yaml.GenerateMultiYAML(payload.Documents)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "GENERATION_FAILED", err.Error())
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, models.GenerateYAMLResponse{YAML: yamlOutput})
+}
+
+// GenerateFromSet renders a resource from kubectl-style key=value arguments.
+func (h *CRDHandler) GenerateFromSet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.GenerateFromSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	fields, err := services.FieldsFromSet(payload.Set)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+	yamlOutput, err := h.yaml.GenerateYAML(payload.APIVersion, payload.Kind, fields)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "GENERATION_FAILED", err.Error())
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, models.GenerateFromSetResponse{Fields: fields, YAML: yamlOutput})
+}
+
+// GenerateKustomize renders the documents as separate files plus a
+// kustomization.yaml that lists them.
+func (h *CRDHandler) GenerateKustomize(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.GenerateMultiYAMLRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	files, err := h.yaml.GenerateKustomize(payload.Documents)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "GENERATION_FAILED", err.Error())
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, models.GenerateKustomizeResponse{Files: files})
+}
+
+func (h *CRDHandler) SaveManifest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.SaveManifestRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	record, err := h.manifests.SaveManifest(r.Context(), payload)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "MANIFEST_SAVE_FAILED", err.Error())
+		return
+	}
+	record.Warnings = services.ScanManifestSecrets(payload.YAML)
+
+	WriteSuccess(w, http.StatusCreated, record)
+}
+
+func (h *CRDHandler) GetManifest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is supported")
+		return
+	}
+
+	record, err := h.manifests.GetManifest(r.Context(), r.PathValue("id"))
+	if errors.Is(err, services.ErrManifestNotFound) {
+		WriteError(w, http.StatusNotFound, "MANIFEST_NOT_FOUND", err.Error())
+		return
+	}
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+		WriteError(w, http.StatusInternalServerError, "MANIFEST_GET_FAILED", err.Error())
 		return
 	}
 
-	WriteSuccess(w, http.StatusOK, models.ParseCRDResponse{Template: template})
+	WriteSuccess(w, http.StatusOK, record)
 }
 
-func (h *CRDHandler) ValidateCRD(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+func (h *CRDHandler) UpdateManifest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only PUT is supported")
 		return
 	}
 
-	var payload models.ValidateCRDRequest
+	var payload models.SaveManifestRequest
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
 		return
 	}
+	if strings.TrimSpace(payload.YAML) == "" {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "yaml is required")
+		return
+	}
 
-	result := h.crd.ValidateCRD(payload.Raw)
-	if !result.Valid {
-		WriteSuccess(w, http.StatusOK, result)
+	record, err := h.manifests.UpdateManifest(r.Context(), r.PathValue("id"), payload)
+	if errors.Is(err, services.ErrManifestNotFound) {
+		WriteError(w, http.StatusNotFound, "MANIFEST_NOT_FOUND", err.Error())
 		return
 	}
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "MANIFEST_UPDATE_FAILED", err.Error())
+		return
+	}
+	record.Warnings = services.ScanManifestSecrets(payload.YAML)
 
-	WriteSuccess(w, http.StatusOK, result)
+	WriteSuccess(w, http.StatusOK, record)
 }
 
-func (h *CRDHandler) GenerateYAML(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+func (h *CRDHandler) UpdateManifestNote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only PATCH is supported")
 		return
 	}
 
-	var payload models.GenerateYAMLRequest
+	var payload models.UpdateManifestNoteRequest
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
 		return
 	}
 
-	yamlOutput, err := h.yaml.GenerateYAML(payload.APIVersion, payload.Kind, payload.Fields)
+	record, err := h.manifests.UpdateNote(r.Context(), r.PathValue("id"), payload.Note)
+	if errors.Is(err, services.ErrManifestNotFound) {
+		WriteError(w, http.StatusNotFound, "MANIFEST_NOT_FOUND", err.Error())
+		return
+	}
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "GENERATION_FAILED", err.Error())
+		WriteError(w, http.StatusInternalServerError, "MANIFEST_UPDATE_FAILED", err.Error())
 		return
 	}
 
-	WriteSuccess(w, http.StatusOK, models.GenerateYAMLResponse{YAML: yamlOutput})
+	WriteSuccess(w, http.StatusOK, record)
 }
 
-func (h *CRDHandler) SaveManifest(w http.ResponseWriter, r *http.Request) {
+// BulkTagManifests adds and removes tags on several manifests at once,
+// selected by id or by a search query.
+func (h *CRDHandler) BulkTagManifests(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
 		return
 	}
 
-	var payload models.SaveManifestRequest
+	var payload models.BulkTagManifestsRequest
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
 		return
 	}
 
-	record, err := h.manifests.SaveManifest(r.Context(), payload)
+	updated, err := h.manifests.BulkTag(r.Context(), payload)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "MANIFEST_SAVE_FAILED", err.Error())
+		WriteError(w, http.StatusBadRequest, "MANIFEST_TAG_FAILED", err.Error())
 		return
 	}
 
-	WriteSuccess(w, http.StatusCreated, record)
+	WriteSuccess(w, http.StatusOK, models.BulkTagManifestsResponse{Updated: updated})
 }
 
 func (h *CRDHandler) SubmitCRD(w http.ResponseWriter, r *http.Request) {
@@ -140,19 +741,34 @@ func (h *CRDHandler) SubmitCRD(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := validateSubmitEnvironments(payload.Environments); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
 	validation := h.crd.ValidateCRD(payload.Raw)
 	if !validation.Valid {
-		WriteSuccess(w, http.StatusOK, models.SubmitCRDResponse{
+		WriteSuccess(w, invalidStatus(r), models.SubmitCRDResponse{
 			Validation: validation,
 		})
 		return
 	}
 
-	template, err := h.crd.ParseCRD(payload.Raw)
+	template, warnings, err := h.crd.ParseCRDWithWarnings(payload.Raw)
 	if err != nil {
 		WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
 		return
 	}
+	if slices.Contains(warnings, services.PlaceholderAPIVersionWarning) {
+		WriteError(w, http.StatusBadRequest, "INVALID_CRD", services.PlaceholderAPIVersionWarning)
+		return
+	}
+	template.Scope = requestTeam(r)
+	template.ID, err = services.UniqueTemplateID(r.Context(), h.templates, template)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "TEMPLATE_PERSIST_FAILED", err.Error())
+		return
+	}
 	if err := h.templates.Upsert(r.Context(), template); err != nil {
 		WriteError(w, http.StatusInternalServerError, "TEMPLATE_PERSIST_FAILED", err.Error())
 		return
@@ -163,6 +779,11 @@ func (h *CRDHandler) SubmitCRD(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, http.StatusBadRequest, "GENERATION_FAILED", err.Error())
 		return
 	}
+	missingRequired, err := h.yaml.MissingRequiredFields(generatedYAML, template.DefaultFields)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "GENERATION_FAILED", err.Error())
+		return
+	}
 
 	record := models.ManifestRecord{
 		Title:      fallbackTitle(payload.Title, template.Kind),
@@ -172,13 +793,160 @@ func (h *CRDHandler) SubmitCRD(w http.ResponseWriter, r *http.Request) {
 		YAML:       generatedYAML,
 	}
 
+	var saved []models.ManifestRecord
+	for _, environment := range payload.Environments {
+		fields := services.MergeFieldOverrides(template.DefaultFields, environment.Overrides)
+		environmentYAML, err := h.yaml.GenerateYAML(template.APIVersion, template.Kind, fields)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "GENERATION_FAILED", environment.Name+": "+err.Error())
+			return
+		}
+		item, err := h.manifests.SaveManifest(r.Context(), models.SaveManifestRequest{
+			Title:      record.Title + " (" + environment.Name + ")",
+			Resource:   record.Resource,
+			APIVersion: record.APIVersion,
+			Kind:       record.Kind,
+			YAML:       environmentYAML,
+			Note:       "Environment: " + environment.Name,
+			SourceRaw:  payload.Raw,
+			Overrides:  environment.Overrides,
+		})
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "MANIFEST_SAVE_FAILED", err.Error())
+			return
+		}
+		saved = append(saved, item)
+	}
+	if len(saved) > 0 {
+		record = saved[0]
+	}
+
+	if wantsMarkdown(r) {
+		records := saved
+		if len(records) == 0 {
+			records = []models.ManifestRecord{record}
+		}
+		blocks := make([]string, 0, len(records))
+		for _, item := range records {
+			blocks = append(blocks, services.YAMLToMarkdown(item.Title, item.YAML))
+		}
+		writeMarkdown(w, http.StatusCreated, strings.Join(blocks, "\n"))
+		return
+	}
+
 	WriteSuccess(w, http.StatusCreated, models.SubmitCRDResponse{
-		Template:   template,
-		Manifest:   record,
-		Validation: validation,
+		Template:              template,
+		Manifest:              record,
+		Manifests:             saved,
+		Validation:            validation,
+		MissingRequiredFields: missingRequired,
 	})
 }
 
+// SubmitCRDBulk submits every CustomResourceDefinition in a multi-document
+// bundle. Each CRD is validated, stored and rendered on its own, so one
+// invalid document is reported in its result without aborting the rest.
+func (h *CRDHandler) SubmitCRDBulk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.SubmitCRDBulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	docs, err := services.SplitYAMLDocuments(payload.Raw)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+		return
+	}
+
+	response := models.SubmitCRDBulkResponse{Results: make([]models.SubmitCRDBulkResult, 0, len(docs))}
+	for _, doc := range docs {
+		if !strings.EqualFold(doc.Kind, "CustomResourceDefinition") {
+			response.Skipped = append(response.Skipped, doc.Index)
+			continue
+		}
+		result := h.submitBundleDocument(r, doc)
+		if result.Error != "" {
+			response.Failed++
+		} else {
+			response.Submitted++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	WriteSuccess(w, http.StatusOK, response)
+}
+
+func (h *CRDHandler) submitBundleDocument(r *http.Request, doc services.BundleDocument) models.SubmitCRDBulkResult {
+	result := models.SubmitCRDBulkResult{Index: doc.Index}
+	result.Validation = h.crd.ValidateCRD(doc.Raw)
+	if !result.Validation.Valid {
+		result.Error = "validation failed"
+		return result
+	}
+
+	template, warnings, err := h.crd.ParseCRDWithWarnings(doc.Raw)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if slices.Contains(warnings, services.PlaceholderAPIVersionWarning) {
+		result.Error = services.PlaceholderAPIVersionWarning
+		return result
+	}
+	template.Scope = requestTeam(r)
+	template.ID, err = services.UniqueTemplateID(r.Context(), h.templates, template)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if err := h.templates.Upsert(r.Context(), template); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Template = &template
+
+	generatedYAML, err := h.yaml.GenerateYAML(template.APIVersion, template.Kind, template.DefaultFields)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.MissingRequiredFields, err = h.yaml.MissingRequiredFields(generatedYAML, template.DefaultFields)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Manifest = &models.ManifestRecord{
+		Title:      fallbackTitle("", template.Kind),
+		Resource:   template.Kind + " (" + template.APIVersion + ")",
+		APIVersion: template.APIVersion,
+		Kind:       template.Kind,
+		YAML:       generatedYAML,
+	}
+	return result
+}
+
+func validateSubmitEnvironments(environments []models.SubmitCRDEnvironment) error {
+	seen := make(map[string]struct{}, len(environments))
+	for i := range environments {
+		name := strings.TrimSpace(environments[i].Name)
+		if name == "" {
+			return fmt.Errorf("environments[%d].name is required", i)
+		}
+		if _, exists := seen[name]; exists {
+			return fmt.Errorf("duplicate environment %q", name)
+		}
+		seen[name] = struct{}{}
+		environments[i].Name = name
+	}
+	return nil
+}
+
 func (h *CRDHandler) ImportCRDFromURL(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
@@ -191,7 +959,7 @@ func (h *CRDHandler) ImportCRDFromURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sourceURL, raw, err := h.crd.FetchCRDFromURL(payload.URL)
+	sourceURL, raw, err := h.crd.FetchCRDFromURL(payload.URL, payload.Token)
 	if err != nil {
 		WriteError(w, http.StatusBadRequest, "CRD_IMPORT_FAILED", err.Error())
 		return
@@ -205,6 +973,119 @@ func (h *CRDHandler) ImportCRDFromURL(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ImportCSV reads the CRDs an OLM ClusterServiceVersion owns and parses each
+// one from the accompanying bundle, falling back to a stored template with
+// the same kind and group. Entries that can't be resolved carry an error.
+func (h *CRDHandler) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.ImportCSVRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	raw := payload.Raw
+	if strings.TrimSpace(payload.Bundle) != "" {
+		raw += "\n---\n" + payload.Bundle
+	}
+	docs, err := services.SplitYAMLDocuments(raw)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_CSV", err.Error())
+		return
+	}
+	owned, err := services.OwnedCRDs(docs)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_CSV", err.Error())
+		return
+	}
+
+	var stored []models.TemplateDefinition
+	results := make([]models.ImportCSVResult, 0, len(owned))
+	for _, entry := range owned {
+		result := models.ImportCSVResult{Owned: entry}
+		if doc, ok := services.FindBundleCRD(docs, entry.Name); ok {
+			template, err := h.crd.ParseCRD(doc.Raw)
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Template = &template
+				result.Source = "bundle"
+			}
+			results = append(results, result)
+			continue
+		}
+
+		if stored == nil {
+			all, err := h.templates.List(r.Context())
+			if err != nil {
+				WriteError(w, http.StatusInternalServerError, "TEMPLATE_LIST_FAILED", err.Error())
+				return
+			}
+			stored = services.VisibleTemplates(all, requestTeam(r))
+		}
+		group := services.OwnedCRDGroup(entry)
+		for i := range stored {
+			storedGroup, storedVersion, _ := strings.Cut(stored[i].APIVersion, "/")
+			if stored[i].Kind == entry.Kind && storedGroup == group && (entry.Version == "" || storedVersion == entry.Version) {
+				result.Template = &stored[i]
+				result.Source = "stored"
+				break
+			}
+		}
+		if result.Template == nil {
+			result.Error = "CRD " + entry.Name + " not found in the bundle or stored templates"
+		}
+		results = append(results, result)
+	}
+
+	WriteSuccess(w, http.StatusOK, models.ImportCSVResponse{Results: results})
+}
+
+// ImportAndSample fetches a CRD from a URL, parses it and renders a sample
+// custom resource from the template's default fields in a single call.
+func (h *CRDHandler) ImportAndSample(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.ImportCRDURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	sourceURL, raw, err := h.crd.FetchCRDFromURL(payload.URL, payload.Token)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "CRD_IMPORT_FAILED", err.Error())
+		return
+	}
+
+	template, warnings, err := h.crd.ParseCRDWithWarnings(raw)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+		return
+	}
+
+	sample, err := h.yaml.GenerateYAML(template.APIVersion, template.Kind, template.DefaultFields)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "GENERATION_FAILED", err.Error())
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, models.ImportCRDSampleResponse{
+		SourceURL:  sourceURL,
+		Template:   template,
+		YAML:       sample,
+		Validation: h.crd.ValidateCRD(raw),
+		Warnings:   warnings,
+	})
+}
+
 func (h *CRDHandler) ListManifests(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is supported")
@@ -218,14 +1099,207 @@ func (h *CRDHandler) ListManifests(w http.ResponseWriter, r *http.Request) {
 			limit = parsed
 		}
 	}
+	var offset int64
+	if value := r.URL.Query().Get("offset"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "offset must be a non-negative integer")
+			return
+		}
+		offset = parsed
+	}
+
+	page, err := h.manifests.ListManifests(r.Context(), query, r.URL.Query()["tag"], limit, offset)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "MANIFEST_LIST_FAILED", err.Error())
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, page)
+}
+
+// DiffManifests lists the paths added, removed or changed between two
+// manifest versions, with the old and new value at each path.
+func (h *CRDHandler) DiffManifests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.CompareYAMLRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+
+	result, err := h.yaml.DiffYAML(payload.Left, payload.Right)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "DIFF_FAILED", err.Error())
+		return
+	}
+	WriteSuccess(w, http.StatusOK, result)
+}
+
+// ManifestDuplicates groups the most recent manifests that are structurally
+// identical, ignoring key order and formatting.
+func (h *CRDHandler) ManifestDuplicates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is supported")
+		return
+	}
 
-	items, err := h.manifests.ListManifests(r.Context(), query, limit)
+	page, err := h.manifests.ListManifests(r.Context(), "", nil, 200, 0)
 	if err != nil {
 		WriteError(w, http.StatusInternalServerError, "MANIFEST_LIST_FAILED", err.Error())
 		return
 	}
 
-	WriteSuccess(w, http.StatusOK, items)
+	WriteSuccess(w, http.StatusOK, h.yaml.GroupDuplicateManifests(page.Items))
+}
+
+func (h *CRDHandler) ManifestApplyCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is supported")
+		return
+	}
+
+	record, err := h.manifests.GetManifest(r.Context(), r.PathValue("id"))
+	if errors.Is(err, services.ErrManifestNotFound) {
+		WriteError(w, http.StatusNotFound, "MANIFEST_NOT_FOUND", err.Error())
+		return
+	}
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "MANIFEST_GET_FAILED", err.Error())
+		return
+	}
+
+	downloadURL := requestBaseURL(r) + "/api/v1/manifests/" + neturl.PathEscape(record.ID) + "/download"
+	WriteSuccess(w, http.StatusOK, services.BuildApplyCommand(record, downloadURL))
+}
+
+// DownloadManifest writes a stored manifest's YAML as a file attachment. The
+// body is the raw YAML, not the usual JSON envelope.
+func (h *CRDHandler) DownloadManifest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is supported")
+		return
+	}
+
+	record, err := h.manifests.GetManifest(r.Context(), r.PathValue("id"))
+	if errors.Is(err, services.ErrManifestNotFound) {
+		WriteError(w, http.StatusNotFound, "MANIFEST_NOT_FOUND", err.Error())
+		return
+	}
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "MANIFEST_GET_FAILED", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+services.ManifestFilename(record.Title)+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(record.YAML))
+}
+
+// RegenerateManifest re-parses a stored manifest's CRD source and returns
+// freshly generated YAML, re-applying the field overrides saved with it
+// (such as an environment's). The stored record is only overwritten when the
+// request asks for it with save=true. Manifests saved without their source
+// can have it supplied in the body, which save=true then persists; failing
+// that, the stored YAML itself is re-parsed.
+func (h *CRDHandler) RegenerateManifest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is supported")
+		return
+	}
+
+	var payload models.RegenerateManifestRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request payload")
+		return
+	}
+	save, _ := strconv.ParseBool(r.URL.Query().Get("save"))
+
+	record, err := h.manifests.GetManifest(r.Context(), r.PathValue("id"))
+	if errors.Is(err, services.ErrManifestNotFound) {
+		WriteError(w, http.StatusNotFound, "MANIFEST_NOT_FOUND", err.Error())
+		return
+	}
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "MANIFEST_GET_FAILED", err.Error())
+		return
+	}
+
+	source := record.SourceRaw
+	if strings.TrimSpace(source) == "" {
+		source = payload.SourceRaw
+	}
+	input := source
+	var notes []string
+	if strings.TrimSpace(input) == "" {
+		input = record.YAML
+		notes = append(notes, "Manifest was saved without its CRD source; regenerated from the stored YAML.")
+	}
+
+	template, warnings, err := h.crd.ParseCRDWithWarnings(input)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_CRD", err.Error())
+		return
+	}
+	fields := services.MergeFieldOverrides(template.DefaultFields, record.Overrides)
+	generatedYAML, err := h.yaml.GenerateYAML(template.APIVersion, template.Kind, fields)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "GENERATION_FAILED", err.Error())
+		return
+	}
+
+	response := models.RegenerateManifestResponse{
+		Template:  template,
+		YAML:      generatedYAML,
+		SourceRaw: source,
+		Warnings:  append(notes, warnings...),
+		Manifest:  record,
+	}
+	if save {
+		response.Manifest, err = h.manifests.UpdateManifest(r.Context(), record.ID, models.SaveManifestRequest{
+			Title:      record.Title,
+			Resource:   record.Resource,
+			APIVersion: template.APIVersion,
+			Kind:       template.Kind,
+			YAML:       generatedYAML,
+			SourceRaw:  source,
+		})
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "MANIFEST_UPDATE_FAILED", err.Error())
+			return
+		}
+		response.Saved = true
+	}
+
+	WriteSuccess(w, http.StatusOK, response)
+}
+
+// hostHeaderRegex accepts a host name or bracketed IPv6 address with an
+// optional port, which is all a forwarded host may contain.
+var hostHeaderRegex = regexp.MustCompile(`^([A-Za-z0-9.-]+|\[[0-9A-Fa-f:.]+\])(:[0-9]{1,5})?$`)
+
+// requestBaseURL reconstructs the externally visible scheme and host, honoring
+// the forwarding headers set by a reverse proxy. Only trusted proxies get
+// their headers this far (see middleware.TrustForwardedHeaders), and values
+// that are not a plain scheme or host are ignored.
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := strings.ToLower(strings.TrimSpace(r.Header.Get("X-Forwarded-Proto"))); forwarded == "http" || forwarded == "https" {
+		scheme = forwarded
+	}
+	host := r.Host
+	if forwarded := strings.TrimSpace(r.Header.Get("X-Forwarded-Host")); hostHeaderRegex.MatchString(forwarded) {
+		host = forwarded
+	}
+	return scheme + "://" + host
 }
 
 func fallbackTitle(title string, kind string) string {
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"github.com/aneeshchawla/kubetools/backend/internal/services"
+	"gopkg.in/yaml.v3"
+)
+
+func TestImportAndSampleReturnsTemplateAndSampleCR(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write([]byte(widgetCRD))
+	}))
+	defer upstream.Close()
+
+	handler := NewCRDHandler(nil, services.NewCRDService(config.Config{}), services.NewYAMLService(), nil)
+	body, err := json.Marshal(models.ImportCRDURLRequest{URL: upstream.URL + "/widgets.yaml"})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ImportAndSample(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/import-and-sample", bytes.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.ImportCRDSampleResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if envelope.Data.Template.Kind != "Widget" || !envelope.Data.Validation.Valid {
+		t.Fatalf("expected a valid Widget template, got %+v", envelope.Data)
+	}
+
+	var sample struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+		Metadata   struct {
+			Name string `yaml:"name"`
+		} `yaml:"metadata"`
+		Spec map[string]any `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal([]byte(envelope.Data.YAML), &sample); err != nil {
+		t.Fatalf("decode sample: %v", err)
+	}
+	if sample.APIVersion != "example.io/v1" || sample.Kind != "Widget" || sample.Metadata.Name == "" {
+		t.Fatalf("expected an applyable Widget sample, got:\n%s", envelope.Data.YAML)
+	}
+	if _, ok := sample.Spec["size"]; !ok {
+		t.Fatalf("expected required spec.size in sample, got:\n%s", envelope.Data.YAML)
+	}
+}
+
+func TestImportCRDFromURLOnlySendsTokenToGitHub(t *testing.T) {
+	var authorization string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(widgetCRD))
+	}))
+	defer upstream.Close()
+
+	handler := NewCRDHandler(nil, services.NewCRDService(config.Config{}), services.NewYAMLService(), nil)
+	body, err := json.Marshal(models.ImportCRDURLRequest{URL: upstream.URL + "/widgets.yaml", Token: "ghp_secret"})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ImportCRDFromURL(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/import-url", bytes.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if authorization != "" {
+		t.Fatalf("expected the token to be withheld from non-GitHub hosts, got %q", authorization)
+	}
+}
+
+func TestImportCSVParsesOwnedCRDsFromBundle(t *testing.T) {
+	handler := NewCRDHandler(
+		&services.TemplateService{},
+		services.NewCRDService(config.Config{}),
+		services.NewYAMLService(),
+		&services.ManifestService{},
+	)
+
+	csv := `apiVersion: operators.coreos.com/v1alpha1
+kind: ClusterServiceVersion
+metadata:
+  name: widget-operator.v1.0.0
+spec:
+  customresourcedefinitions:
+    owned:
+      - name: widgets.example.io
+        kind: Widget
+        version: v1
+      - name: gadgets.example.io
+        kind: Gadget
+        version: v1
+`
+	bundle := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.io
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                size:
+                  type: integer
+`
+
+	body, err := json.Marshal(models.ImportCSVRequest{Raw: csv, Bundle: bundle})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ImportCSV(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/import-csv", bytes.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.ImportCSVResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	results := envelope.Data.Results
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", results)
+	}
+	widget := results[0]
+	if widget.Owned.Name != "widgets.example.io" || widget.Source != "bundle" || widget.Template == nil || widget.Template.APIVersion != "example.io/v1" {
+		t.Fatalf("expected Widget to be parsed from the bundle, got %+v", widget)
+	}
+	gadget := results[1]
+	if gadget.Owned.Name != "gadgets.example.io" || gadget.Template != nil || gadget.Error == "" {
+		t.Fatalf("expected Gadget to be reported as not found, got %+v", gadget)
+	}
+}
+
+func TestImportCSVFallsBackOnlyToVisibleTemplatesOfTheOwnedVersion(t *testing.T) {
+	templates := &services.TemplateService{}
+	for _, template := range []models.TemplateDefinition{
+		{ID: "gadget-team-b", Title: "Gadget", APIVersion: "example.io/v1", Kind: "Gadget", Scope: "team-b"},
+		{ID: "gadget-v1beta1", Title: "Gadget", APIVersion: "example.io/v1beta1", Kind: "Gadget"},
+	} {
+		if err := templates.Upsert(context.Background(), template); err != nil {
+			t.Fatalf("upsert template: %v", err)
+		}
+	}
+	handler := NewCRDHandler(templates, services.NewCRDService(config.Config{}), services.NewYAMLService(), &services.ManifestService{})
+
+	csv := `apiVersion: operators.coreos.com/v1alpha1
+kind: ClusterServiceVersion
+metadata:
+  name: gadget-operator.v1.0.0
+spec:
+  customresourcedefinitions:
+    owned:
+      - name: gadgets.example.io
+        kind: Gadget
+        version: v1
+`
+	importFor := func(team string) models.ImportCSVResult {
+		t.Helper()
+		body, _ := json.Marshal(models.ImportCSVRequest{Raw: csv})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/crd/import-csv", bytes.NewReader(body))
+		req.Header.Set("X-Team", team)
+		rec := httptest.NewRecorder()
+		handler.ImportCSV(rec, req)
+		var envelope struct {
+			Data models.ImportCSVResponse `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil || len(envelope.Data.Results) != 1 {
+			t.Fatalf("decode response %s: %v", rec.Body.String(), err)
+		}
+		return envelope.Data.Results[0]
+	}
+
+	if result := importFor("team-a"); result.Template != nil {
+		t.Fatalf("expected neither team-b's template nor the v1beta1 one to be used, got %+v", result.Template)
+	}
+	if result := importFor("team-b"); result.Template == nil || result.Template.ID != "gadget-team-b" {
+		t.Fatalf("expected team-b to get its own v1 template, got %+v", result)
+	}
+}
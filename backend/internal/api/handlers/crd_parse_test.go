@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"github.com/aneeshchawla/kubetools/backend/internal/services"
+)
+
+const widgetCRD = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required: [size]
+              properties:
+                size:
+                  type: string
+                settings:
+                  type: object
+                  properties:
+                    region:
+                      type: string
+                    zone:
+                      type: string
+`
+
+func TestParseCRDIncludesAllFieldsWhenRequested(t *testing.T) {
+	handler := NewCRDHandler(nil, services.NewCRDService(config.Config{}), services.NewYAMLService(), nil)
+
+	body, err := json.Marshal(models.ParseCRDRequest{Raw: widgetCRD, IncludeAllFields: true})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/crd/parse", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	handler.ParseCRD(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var envelope struct {
+		Data models.ParseCRDResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(envelope.Data.Template.DefaultFields) == 0 {
+		t.Fatalf("expected curated template fields in response")
+	}
+
+	paths := map[string]bool{}
+	for _, field := range envelope.Data.AllFields {
+		paths[field.Path] = true
+	}
+	for _, expected := range []string{"metadata.name", "spec.size", "spec.settings.region", "spec.settings.zone"} {
+		if !paths[expected] {
+			t.Fatalf("expected %s in allFields, got %+v", expected, envelope.Data.AllFields)
+		}
+	}
+}
+
+func TestParseCRDReportsSkippedFieldsWhenRequested(t *testing.T) {
+	handler := NewCRDHandler(nil, services.NewCRDService(config.Config{}), services.NewYAMLService(), nil)
+
+	var properties strings.Builder
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&properties, "                field%03d:\n                  type: string\n", i)
+	}
+	raw := strings.Replace(widgetCRD, "                size:\n", properties.String()+"                size:\n", 1)
+
+	for _, include := range []bool{false, true} {
+		body, err := json.Marshal(models.ParseCRDRequest{Raw: raw, IncludeSkippedFields: include})
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		rec := httptest.NewRecorder()
+		handler.ParseCRD(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/parse", bytes.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		var envelope struct {
+			Data models.ParseCRDResponse `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if !include {
+			if len(envelope.Data.SkippedFields) != 0 {
+				t.Fatalf("expected skipped fields to be omitted unless requested")
+			}
+			continue
+		}
+
+		if len(envelope.Data.SkippedFields) == 0 {
+			t.Fatalf("expected fields beyond the candidate cap to be reported as skipped")
+		}
+		kept := map[string]bool{}
+		for _, field := range append(envelope.Data.Template.DefaultFields, envelope.Data.Template.OptionalFields...) {
+			kept[field.Path] = true
+		}
+		for _, field := range envelope.Data.SkippedFields {
+			if kept[field.Path] {
+				t.Fatalf("skipped field %s is also in the template", field.Path)
+			}
+		}
+	}
+}
+
+func TestValidateCRDStrictModeTreatsWarningsAsErrors(t *testing.T) {
+	handler := NewCRDHandler(nil, services.NewCRDService(config.Config{}), nil, nil)
+	// A plain Deployment only produces the "not a CustomResourceDefinition" warning.
+	raw := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n"
+
+	for _, strict := range []bool{false, true} {
+		body, err := json.Marshal(models.ValidateCRDRequest{Raw: raw, Strict: strict})
+		if err != nil {
+			t.Fatalf("marshal request: %v", err)
+		}
+		rec := httptest.NewRecorder()
+		handler.ValidateCRD(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/validate", bytes.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		var envelope struct {
+			Data models.ValidateCRDResponse `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+
+		if !strict && (!envelope.Data.Valid || len(envelope.Data.Warnings) == 0 || len(envelope.Data.Errors) != 0) {
+			t.Fatalf("expected lenient mode to be valid with warnings, got %+v", envelope.Data)
+		}
+		if strict && (envelope.Data.Valid || len(envelope.Data.Errors) == 0 || len(envelope.Data.Warnings) != 0) {
+			t.Fatalf("expected strict mode to fold warnings into errors, got %+v", envelope.Data)
+		}
+	}
+}
+
+func TestGenerateYAMLReturnsObjectWhenRequested(t *testing.T) {
+	handler := NewCRDHandler(nil, services.NewCRDService(config.Config{}), services.NewYAMLService(), nil)
+
+	for _, includeObject := range []bool{false, true} {
+		body, err := json.Marshal(models.GenerateYAMLRequest{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Fields: []models.FieldDefinition{
+				{Path: "metadata.name", Value: "web"},
+				{Path: "spec.replicas", Value: "3", Type: "number"},
+			},
+			IncludeObject: includeObject,
+		})
+		if err != nil {
+			t.Fatalf("marshal request: %v", err)
+		}
+		rec := httptest.NewRecorder()
+		handler.GenerateYAML(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/generate-yaml", bytes.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		var envelope struct {
+			Data struct {
+				YAML   string         `json:"yaml"`
+				Object map[string]any `json:"object"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if !strings.Contains(envelope.Data.YAML, "replicas: 3") {
+			t.Fatalf("expected yaml to be returned, got %q", envelope.Data.YAML)
+		}
+
+		if !includeObject {
+			if envelope.Data.Object != nil {
+				t.Fatalf("expected no object unless requested, got %v", envelope.Data.Object)
+			}
+			continue
+		}
+		spec, _ := envelope.Data.Object["spec"].(map[string]any)
+		if replicas, ok := spec["replicas"].(float64); !ok || replicas != 3 {
+			t.Fatalf("expected spec.replicas to be the number 3, got %#v", spec["replicas"])
+		}
+	}
+}
+
+func TestGenerateYAMLRendersMarkdownCodeBlock(t *testing.T) {
+	handler := NewCRDHandler(nil, services.NewCRDService(config.Config{}), services.NewYAMLService(), nil)
+	body, err := json.Marshal(models.GenerateYAMLRequest{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Fields:     []models.FieldDefinition{{Path: "metadata.name", Value: "web"}},
+	})
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	handler.GenerateYAML(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/generate-yaml?format=markdown", bytes.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if contentType := rec.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/markdown") {
+		t.Fatalf("expected markdown content type, got %q", contentType)
+	}
+	output := rec.Body.String()
+	if !strings.HasPrefix(output, "### Deployment (apps/v1)\n\n```yaml\n") || !strings.HasSuffix(output, "```\n") {
+		t.Fatalf("expected a headed yaml code fence, got:\n%s", output)
+	}
+	if !strings.Contains(output, "name: web\n") {
+		t.Fatalf("expected generated yaml inside the fence, got:\n%s", output)
+	}
+}
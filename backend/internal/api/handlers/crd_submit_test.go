@@ -22,7 +22,7 @@ func TestSubmitCRDStoresGeneratedCustomResource(t *testing.T) {
 
 	handler := NewCRDHandler(
 		templateService,
-		services.NewCRDService(),
+		services.NewCRDService(config.Config{}),
 		services.NewYAMLService(),
 		&services.ManifestService{},
 	)
@@ -89,3 +89,417 @@ spec:
 		t.Fatalf("expected generated custom resource YAML to include kind Widget: %s", envelope.Data.Manifest.YAML)
 	}
 }
+
+func TestSubmitCRDReportsRequiredFieldsWithoutValues(t *testing.T) {
+	handler := NewCRDHandler(
+		&services.TemplateService{},
+		services.NewCRDService(config.Config{}),
+		services.NewYAMLService(),
+		&services.ManifestService{},
+	)
+
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Gadget
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required: [endpoint, mode]
+              properties:
+                endpoint:
+                  type: string
+                mode:
+                  type: string
+                  default: fast
+`
+
+	body, err := json.Marshal(models.SubmitCRDRequest{Raw: raw})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/crd/submit", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	handler.SubmitCRD(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.SubmitCRDResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	missing := envelope.Data.MissingRequiredFields
+	if len(missing) != 1 || missing[0] != "spec.endpoint" {
+		t.Fatalf("expected only spec.endpoint to be reported missing, got %v", missing)
+	}
+}
+
+func TestSubmitCRDSavesOneManifestPerEnvironment(t *testing.T) {
+	manifests := &services.ManifestService{}
+	handler := NewCRDHandler(
+		&services.TemplateService{},
+		services.NewCRDService(config.Config{}),
+		services.NewYAMLService(),
+		manifests,
+	)
+
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                replicas:
+                  type: integer
+                  default: 1
+`
+
+	body, err := json.Marshal(models.SubmitCRDRequest{
+		Title: "Widget",
+		Raw:   raw,
+		Environments: []models.SubmitCRDEnvironment{
+			{Name: "dev", Overrides: []models.FieldDefinition{{Path: "spec.replicas", Value: "1"}}},
+			{Name: "prod", Overrides: []models.FieldDefinition{
+				{Path: "spec.replicas", Value: "5"},
+				{Path: "metadata.namespace", Value: "production"},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	handler.SubmitCRD(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/submit", bytes.NewReader(body)))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.SubmitCRDResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	saved := envelope.Data.Manifests
+	if len(saved) != 2 || saved[0].ID == saved[1].ID {
+		t.Fatalf("expected two distinct saved manifests, got %+v", saved)
+	}
+	if saved[0].Title != "Widget (dev)" || !strings.Contains(saved[0].YAML, "replicas: 1") {
+		t.Fatalf("unexpected dev manifest: %+v", saved[0])
+	}
+	if saved[1].Title != "Widget (prod)" || !strings.Contains(saved[1].YAML, "replicas: 5") || !strings.Contains(saved[1].YAML, "namespace: production") {
+		t.Fatalf("unexpected prod manifest: %+v", saved[1])
+	}
+
+	stored, err := manifests.ListManifests(context.Background(), "", nil, 10, 0)
+	if err != nil {
+		t.Fatalf("list manifests: %v", err)
+	}
+	if len(stored.Items) != 2 {
+		t.Fatalf("expected both environments to be persisted, got %d", len(stored.Items))
+	}
+}
+
+func TestSubmittedTemplatesAreScopedToTheImportingTeam(t *testing.T) {
+	templateService, err := services.NewTemplateService(context.Background(), config.Config{})
+	if err != nil {
+		t.Logf("template service fallback: %v", err)
+	}
+	handler := NewCRDHandler(templateService, services.NewCRDService(config.Config{}), services.NewYAMLService(), &services.ManifestService{})
+
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Gizmo
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                size:
+                  type: string
+`
+	body, err := json.Marshal(models.SubmitCRDRequest{Raw: raw})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	submit := httptest.NewRequest(http.MethodPost, "/api/v1/crd/submit", bytes.NewReader(body))
+	submit.Header.Set("X-Team", "team-a")
+	rec := httptest.NewRecorder()
+	handler.SubmitCRD(rec, submit)
+	if rec.Code != http.StatusCreated && rec.Code != http.StatusOK {
+		t.Fatalf("submit failed with %d: %s", rec.Code, rec.Body.String())
+	}
+
+	listFor := func(team string) map[string]bool {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/crd/templates?team="+team, nil)
+		rec := httptest.NewRecorder()
+		handler.Templates(rec, req)
+		var envelope struct {
+			Data []models.TemplateDefinition `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("decode templates: %v", err)
+		}
+		ids := make(map[string]bool, len(envelope.Data))
+		for _, template := range envelope.Data {
+			ids[template.ID] = true
+		}
+		return ids
+	}
+
+	own, other := listFor("team-a"), listFor("team-b")
+	if !own["parsed-gizmo"] {
+		t.Fatalf("expected team-a to see its imported template, got %v", own)
+	}
+	if other["parsed-gizmo"] {
+		t.Fatalf("expected team-b not to see team-a's template, got %v", other)
+	}
+	if !own["deployment"] || !other["deployment"] {
+		t.Fatalf("expected built-ins to be visible to both teams, got %v and %v", own, other)
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodPatch, http.MethodDelete} {
+		req := httptest.NewRequest(method, "/api/v1/crd/templates/parsed-gizmo", strings.NewReader(`{"title":"stolen"}`))
+		req.SetPathValue("id", "parsed-gizmo")
+		req.Header.Set("X-Team", "team-b")
+		rec := httptest.NewRecorder()
+		switch method {
+		case http.MethodGet:
+			handler.GetTemplate(rec, req)
+		case http.MethodPatch:
+			handler.PatchTemplate(rec, req)
+		case http.MethodDelete:
+			handler.DeleteTemplate(rec, req)
+		}
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected %s of team-a's template by team-b to be not found, got %d", method, rec.Code)
+		}
+	}
+
+	submit = httptest.NewRequest(http.MethodPost, "/api/v1/crd/submit", bytes.NewReader(body))
+	submit.Header.Set("X-Team", "team-b")
+	rec = httptest.NewRecorder()
+	handler.SubmitCRD(rec, submit)
+	if rec.Code != http.StatusCreated && rec.Code != http.StatusOK {
+		t.Fatalf("submit failed with %d: %s", rec.Code, rec.Body.String())
+	}
+	if owned, _ := templateService.Get(context.Background(), "parsed-gizmo"); owned.Scope != "team-a" || owned.Title == "stolen" {
+		t.Fatalf("expected team-a's template to be left alone, got %+v", owned)
+	}
+	if !listFor("team-b")["parsed-gizmo-2"] {
+		t.Fatalf("expected team-b's import to get its own id, got %v", listFor("team-b"))
+	}
+}
+
+func TestSubmitCRDKeepsCaseDistinctKindsUnderSeparateIDs(t *testing.T) {
+	templateService, err := services.NewTemplateService(context.Background(), config.Config{})
+	if err != nil {
+		t.Logf("template service fallback: %v", err)
+	}
+	handler := NewCRDHandler(templateService, services.NewCRDService(config.Config{}), services.NewYAMLService(), &services.ManifestService{})
+
+	submit := func(kind string) string {
+		raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: ` + kind + `
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                size:
+                  type: string
+`
+		body, err := json.Marshal(models.SubmitCRDRequest{Raw: raw})
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		rec := httptest.NewRecorder()
+		handler.SubmitCRD(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/submit", bytes.NewReader(body)))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("submit %s failed with %d: %s", kind, rec.Code, rec.Body.String())
+		}
+		var envelope struct {
+			Data models.SubmitCRDResponse `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return envelope.Data.Template.ID
+	}
+
+	first, second, again := submit("FooBar"), submit("Foobar"), submit("FooBar")
+	if first != "parsed-foobar" || second != "parsed-foobar-2" {
+		t.Fatalf("expected distinct ids for case-distinct kinds, got %q and %q", first, second)
+	}
+	if again != first {
+		t.Fatalf("expected re-importing FooBar to reuse %q, got %q", first, again)
+	}
+	stored, err := templateService.Get(context.Background(), second)
+	if err != nil || stored.Kind != "Foobar" {
+		t.Fatalf("expected Foobar stored under %q, got %+v (%v)", second, stored, err)
+	}
+}
+
+func TestSubmitCRDReturns422ForInvalidDocumentWhenRequested(t *testing.T) {
+	handler := NewCRDHandler(
+		&services.TemplateService{},
+		services.NewCRDService(config.Config{}),
+		services.NewYAMLService(),
+		&services.ManifestService{},
+	)
+
+	body, err := json.Marshal(models.SubmitCRDRequest{Raw: "kind: CustomResourceDefinition\n"})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.SubmitCRD(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/submit", bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected default status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/crd/submit", bytes.NewReader(body))
+	req.Header.Set("X-Invalid-Status", "422")
+	rec = httptest.NewRecorder()
+	handler.SubmitCRD(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusUnprocessableEntity, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Success bool                     `json:"success"`
+		Data    models.SubmitCRDResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !envelope.Success || envelope.Data.Validation.Valid || len(envelope.Data.Validation.Errors) == 0 {
+		t.Fatalf("expected validation errors in the envelope, got %+v", envelope)
+	}
+}
+
+func TestSubmitCRDBulkSubmitsEachCRDAndContinuesPastInvalidOnes(t *testing.T) {
+	handler := NewCRDHandler(
+		&services.TemplateService{},
+		services.NewCRDService(config.Config{}),
+		services.NewYAMLService(),
+		&services.ManifestService{},
+	)
+
+	crd := func(kind string) string {
+		return `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: ` + kind + `
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                replicas:
+                  type: integer
+                  default: 1
+`
+	}
+	raw := crd("Widget") + "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: extra\n---\n" +
+		"apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nspec: {}\n---\n" + crd("Gadget")
+
+	body, err := json.Marshal(models.SubmitCRDBulkRequest{Raw: raw})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	handler.SubmitCRDBulk(rec, httptest.NewRequest(http.MethodPost, "/api/v1/crd/submit-bulk", bytes.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.SubmitCRDBulkResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	response := envelope.Data
+	if response.Submitted != 2 || response.Failed != 1 || len(response.Results) != 3 {
+		t.Fatalf("expected 2 submitted and 1 failed, got %+v", response)
+	}
+	if len(response.Skipped) != 1 || response.Skipped[0] != 1 {
+		t.Fatalf("expected the ConfigMap at index 1 to be skipped, got %v", response.Skipped)
+	}
+
+	invalid := response.Results[1]
+	if invalid.Index != 2 || invalid.Error == "" || invalid.Validation.Valid {
+		t.Fatalf("expected document 2 to fail validation, got %+v", invalid)
+	}
+	for _, result := range []models.SubmitCRDBulkResult{response.Results[0], response.Results[2]} {
+		if result.Error != "" || result.Template == nil || result.Manifest == nil {
+			t.Fatalf("expected document %d to be submitted, got %+v", result.Index, result)
+		}
+		if !strings.Contains(result.Manifest.YAML, "kind: "+result.Template.Kind) {
+			t.Fatalf("expected manifest for %s, got:\n%s", result.Template.Kind, result.Manifest.YAML)
+		}
+	}
+	if response.Results[0].Template.Kind != "Widget" || response.Results[2].Template.Kind != "Gadget" {
+		t.Fatalf("expected results in bundle order, got %s and %s", response.Results[0].Template.Kind, response.Results[2].Template.Kind)
+	}
+}
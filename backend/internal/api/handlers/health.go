@@ -1,6 +1,16 @@
 package handlers
 
-import "net/http"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"github.com/aneeshchawla/kubetools/backend/internal/services"
+)
 
 func Health(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -9,3 +19,75 @@ func Health(w http.ResponseWriter, r *http.Request) {
 	}
 	WriteSuccess(w, http.StatusOK, map[string]string{"status": "ok"})
 }
+
+// Health behaves like the plain probe unless ?verbose=1 is set, in which case
+// it also reports template and manifest counts from the backing stores.
+func (h *CRDHandler) Health(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("verbose") != "1" {
+		Health(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is supported")
+		return
+	}
+
+	templates, err := h.templates.Count(r.Context())
+	if err != nil {
+		WriteError(w, http.StatusServiceUnavailable, "HEALTH_CHECK_FAILED", err.Error())
+		return
+	}
+	manifests, err := h.manifests.Count(r.Context())
+	if err != nil {
+		WriteError(w, http.StatusServiceUnavailable, "HEALTH_CHECK_FAILED", err.Error())
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, models.HealthResponse{
+		Status:    "ok",
+		Templates: templates,
+		Manifests: manifests,
+	})
+}
+
+// Ready reports whether the backing stores are reachable. A store that can't
+// be reached fails the probe with 503; stores serving from the in-memory
+// fallback still pass, but the response is marked degraded.
+func (h *CRDHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is supported")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	response := models.ReadinessResponse{Status: "ready", Checks: make(map[string]string, 2)}
+	failed := make([]string, 0)
+	for name, store := range map[string]any{"manifests": h.manifests, "templates": h.templates} {
+		pinger, ok := store.(services.Pinger)
+		if !ok {
+			response.Checks[name] = "ok"
+			continue
+		}
+		err := pinger.Ping(ctx)
+		switch {
+		case err == nil:
+			response.Checks[name] = "ok"
+		case errors.Is(err, services.ErrMongoUnavailable):
+			response.Checks[name] = "memory"
+			response.Status = "degraded"
+			response.Mongo = "unavailable"
+		default:
+			response.Checks[name] = err.Error()
+			failed = append(failed, name+": "+err.Error())
+		}
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		WriteError(w, http.StatusServiceUnavailable, "NOT_READY", strings.Join(failed, "; "))
+		return
+	}
+	WriteSuccess(w, http.StatusOK, response)
+}
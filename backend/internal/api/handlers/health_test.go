@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"github.com/aneeshchawla/kubetools/backend/internal/services"
+)
+
+func TestHealthVerboseIncludesCounts(t *testing.T) {
+	templateService, err := services.NewTemplateService(context.Background(), config.Config{})
+	if err != nil {
+		t.Logf("template service fallback: %v", err)
+	}
+	manifests := &services.ManifestService{}
+	if _, err := manifests.SaveManifest(context.Background(), models.SaveManifestRequest{YAML: "kind: ConfigMap\n"}); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	handler := NewCRDHandler(templateService, nil, nil, manifests)
+
+	rec := httptest.NewRecorder()
+	handler.Health(rec, httptest.NewRequest(http.MethodGet, "/api/v1/health?verbose=1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.HealthResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if envelope.Data.Status != "ok" || envelope.Data.Manifests != 1 || envelope.Data.Templates == 0 {
+		t.Fatalf("expected verbose health to report counts, got %+v", envelope.Data)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.Health(rec, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if body := rec.Body.String(); strings.Contains(body, "templates") || strings.Contains(body, "manifests") {
+		t.Fatalf("expected plain health to omit counts, got %s", body)
+	}
+}
+
+type unreachableManifestStore struct {
+	services.ManifestStore
+}
+
+func (unreachableManifestStore) Ping(context.Context) error {
+	return errors.New("server selection timeout")
+}
+
+func TestReadyReportsDegradedAndFailedDependencies(t *testing.T) {
+	templateService, err := services.NewTemplateService(context.Background(), config.Config{})
+	if err != nil {
+		t.Logf("template service fallback: %v", err)
+	}
+
+	handler := NewCRDHandler(templateService, nil, nil, &services.ManifestService{})
+	rec := httptest.NewRecorder()
+	handler.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected in-memory fallback to stay ready, got %d with body: %s", rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.ReadinessResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if envelope.Data.Status != "degraded" || envelope.Data.Mongo != "unavailable" || envelope.Data.Checks["manifests"] != "memory" {
+		t.Fatalf("expected degraded status with a mongo note, got %+v", envelope.Data)
+	}
+
+	handler = NewCRDHandler(templateService, nil, nil, unreachableManifestStore{})
+	rec = httptest.NewRecorder()
+	handler.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 when a store is unreachable, got %d", rec.Code)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "manifests: server selection timeout") || strings.Contains(body, "templates:") {
+		t.Fatalf("expected only the failing dependency to be listed, got %s", body)
+	}
+}
@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"github.com/aneeshchawla/kubetools/backend/internal/services"
+)
+
+func TestManifestApplyCommandReferencesDownloadURL(t *testing.T) {
+	manifests := &services.ManifestService{}
+	record, err := manifests.SaveManifest(context.Background(), models.SaveManifestRequest{
+		Title: "web",
+		YAML:  "apiVersion: v1\nkind: ConfigMap\n",
+	})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	handler := NewCRDHandler(nil, nil, nil, manifests)
+
+	req := httptest.NewRequest(http.MethodGet, "http://kubetools.local/api/v1/manifests/"+record.ID+"/apply-command", nil)
+	req.SetPathValue("id", record.ID)
+	rec := httptest.NewRecorder()
+
+	handler.ManifestApplyCommand(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.ManifestApplyCommandResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	expectedURL := "http://kubetools.local/api/v1/manifests/" + record.ID + "/download"
+	if envelope.Data.DownloadURL != expectedURL {
+		t.Fatalf("expected download url %s, got %s", expectedURL, envelope.Data.DownloadURL)
+	}
+	if !strings.Contains(envelope.Data.Command, "kubectl apply -f") || !strings.Contains(envelope.Data.Command, expectedURL) {
+		t.Fatalf("expected command to apply the download url, got %s", envelope.Data.Command)
+	}
+	if !strings.Contains(envelope.Data.Heredoc, "kind: ConfigMap") {
+		t.Fatalf("expected heredoc to embed the manifest YAML, got %s", envelope.Data.Heredoc)
+	}
+}
+
+func TestManifestApplyCommandIgnoresMalformedForwardedHost(t *testing.T) {
+	manifests := &services.ManifestService{}
+	record, err := manifests.SaveManifest(context.Background(), models.SaveManifestRequest{YAML: "kind: ConfigMap\n"})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	handler := NewCRDHandler(nil, nil, nil, manifests)
+
+	req := httptest.NewRequest(http.MethodGet, "http://kubetools.local/api/v1/manifests/"+record.ID+"/apply-command", nil)
+	req.SetPathValue("id", record.ID)
+	req.Header.Set("X-Forwarded-Host", "x'; rm -rf ~; '")
+	req.Header.Set("X-Forwarded-Proto", "javascript")
+	rec := httptest.NewRecorder()
+	handler.ManifestApplyCommand(rec, req)
+
+	var envelope struct {
+		Data models.ManifestApplyCommandResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if want := "http://kubetools.local/api/v1/manifests/" + record.ID + "/download"; envelope.Data.DownloadURL != want {
+		t.Fatalf("expected malformed forwarding headers to be ignored, got %s", envelope.Data.DownloadURL)
+	}
+}
+
+func TestManifestApplyCommandUnknownID(t *testing.T) {
+	handler := NewCRDHandler(nil, nil, nil, &services.ManifestService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/manifests/missing/apply-command", nil)
+	req.SetPathValue("id", "missing")
+	rec := httptest.NewRecorder()
+
+	handler.ManifestApplyCommand(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+type stubManifestStore struct {
+	services.ManifestStore
+	records    []models.ManifestRecord
+	lastQuery  string
+	lastTags   []string
+	lastLimit  int64
+	lastOffset int64
+}
+
+func (s *stubManifestStore) ListManifests(_ context.Context, query string, tags []string, limit, offset int64) (models.ManifestPage, error) {
+	s.lastQuery, s.lastTags, s.lastLimit, s.lastOffset = query, tags, limit, offset
+	return models.ManifestPage{Items: s.records, Total: int64(len(s.records)), Limit: limit, Offset: offset}, nil
+}
+
+func (s *stubManifestStore) GetManifest(_ context.Context, id string) (models.ManifestRecord, error) {
+	for _, record := range s.records {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return models.ManifestRecord{}, services.ErrManifestNotFound
+}
+
+func TestManifestHandlersUseStoreInterface(t *testing.T) {
+	store := &stubManifestStore{records: []models.ManifestRecord{{ID: "m-1", Title: "web", YAML: "kind: ConfigMap\n"}}}
+	handler := NewCRDHandler(nil, nil, nil, store)
+
+	rec := httptest.NewRecorder()
+	handler.ListManifests(rec, httptest.NewRequest(http.MethodGet, "/api/v1/manifests?query=web&limit=5&offset=10&tag=prod&tag=team-a", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.ManifestPage `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(envelope.Data.Items) != 1 || envelope.Data.Items[0].ID != "m-1" || envelope.Data.Total != 1 {
+		t.Fatalf("expected records from the stub store, got %+v", envelope.Data)
+	}
+	if store.lastQuery != "web" || store.lastLimit != 5 || store.lastOffset != 10 {
+		t.Fatalf("expected query, limit and offset to reach the store, got %q/%d/%d", store.lastQuery, store.lastLimit, store.lastOffset)
+	}
+	if len(store.lastTags) != 2 || store.lastTags[0] != "prod" || store.lastTags[1] != "team-a" {
+		t.Fatalf("expected repeated tag params to reach the store, got %v", store.lastTags)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ListManifests(rec, httptest.NewRequest(http.MethodGet, "/api/v1/manifests?offset=-1", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a negative offset to be rejected, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/manifests/missing/apply-command", nil)
+	req.SetPathValue("id", "missing")
+	rec = httptest.NewRecorder()
+	handler.ManifestApplyCommand(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d for a missing manifest, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestManifestDuplicatesGroupsByFingerprint(t *testing.T) {
+	manifests := &services.ManifestService{}
+	ctx := context.Background()
+	for _, yaml := range []string{
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n",
+		"kind: ConfigMap\nmetadata: {name: a}\napiVersion: v1\n",
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n",
+	} {
+		if _, err := manifests.SaveManifest(ctx, models.SaveManifestRequest{YAML: yaml}); err != nil {
+			t.Fatalf("save manifest: %v", err)
+		}
+	}
+	handler := NewCRDHandler(nil, nil, services.NewYAMLService(), manifests)
+
+	rec := httptest.NewRecorder()
+	handler.ManifestDuplicates(rec, httptest.NewRequest(http.MethodGet, "/api/v1/manifests/duplicates", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data []models.ManifestDuplicateGroup `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(envelope.Data) != 1 || len(envelope.Data[0].Manifests) != 2 {
+		t.Fatalf("expected one group of two reordered duplicates, got %+v", envelope.Data)
+	}
+}
+
+func TestGetManifestReturnsRecordOrNotFound(t *testing.T) {
+	manifests := &services.ManifestService{}
+	record, err := manifests.SaveManifest(context.Background(), models.SaveManifestRequest{
+		Title: "web",
+		YAML:  "apiVersion: v1\nkind: ConfigMap\n",
+	})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	handler := NewCRDHandler(nil, nil, nil, manifests)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/manifests/"+record.ID, nil)
+	req.SetPathValue("id", record.ID)
+	rec := httptest.NewRecorder()
+	handler.GetManifest(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.ManifestRecord `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if envelope.Data.ID != record.ID || envelope.Data.YAML != record.YAML {
+		t.Fatalf("expected full manifest record, got %+v", envelope.Data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/manifests/missing", nil)
+	req.SetPathValue("id", "missing")
+	rec = httptest.NewRecorder()
+	handler.GetManifest(rec, req)
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "MANIFEST_NOT_FOUND") {
+		t.Fatalf("expected 404 MANIFEST_NOT_FOUND, got %d with body: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDownloadManifestWritesRawYAMLAttachment(t *testing.T) {
+	manifests := &services.ManifestService{}
+	record, err := manifests.SaveManifest(context.Background(), models.SaveManifestRequest{
+		Title: "My Web App (prod)",
+		YAML:  "apiVersion: v1\nkind: ConfigMap\n",
+	})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	handler := NewCRDHandler(nil, nil, nil, manifests)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/manifests/"+record.ID+"/download", nil)
+	req.SetPathValue("id", record.ID)
+	rec := httptest.NewRecorder()
+	handler.DownloadManifest(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/yaml" {
+		t.Fatalf("expected application/yaml, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="my-web-app-prod.yaml"` {
+		t.Fatalf("unexpected Content-Disposition %q", got)
+	}
+	if rec.Body.String() != "apiVersion: v1\nkind: ConfigMap\n" {
+		t.Fatalf("expected the raw YAML body, got %q", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/manifests/missing/download", nil)
+	req.SetPathValue("id", "missing")
+	rec = httptest.NewRecorder()
+	handler.DownloadManifest(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d for unknown id, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestRegenerateManifestOnlyOverwritesWhenSaving(t *testing.T) {
+	const crd = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required: [size]
+              properties:
+                size:
+                  type: string
+                  default: large
+`
+	manifests := &services.ManifestService{}
+	record, err := manifests.SaveManifest(context.Background(), models.SaveManifestRequest{
+		Title:     "widget (prod)",
+		YAML:      "apiVersion: example.io/v1\nkind: Widget\n",
+		SourceRaw: crd,
+		Overrides: []models.FieldDefinition{{Path: "spec.size", Value: "small"}},
+	})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	handler := NewCRDHandler(nil, services.NewCRDService(config.Config{}), services.NewYAMLService(), manifests)
+
+	regenerate := func(target string) models.RegenerateManifestResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		req.SetPathValue("id", record.ID)
+		rec := httptest.NewRecorder()
+		handler.RegenerateManifest(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		var envelope struct {
+			Data models.RegenerateManifestResponse `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return envelope.Data
+	}
+
+	preview := regenerate("/api/v1/manifests/" + record.ID + "/regenerate")
+	if preview.Saved || !strings.Contains(preview.YAML, "size: small") || preview.SourceRaw != crd {
+		t.Fatalf("expected unsaved YAML regenerated from the CRD with its overrides, got %+v", preview)
+	}
+	if stored, _ := manifests.GetManifest(context.Background(), record.ID); stored.YAML != record.YAML {
+		t.Fatalf("expected the stored YAML to be untouched, got %q", stored.YAML)
+	}
+
+	saved := regenerate("/api/v1/manifests/" + record.ID + "/regenerate?save=true")
+	if !saved.Saved || saved.Manifest.YAML != saved.YAML {
+		t.Fatalf("expected the regenerated YAML to be saved, got %+v", saved.Manifest)
+	}
+	stored, _ := manifests.GetManifest(context.Background(), record.ID)
+	if stored.SourceRaw != crd || len(stored.Overrides) != 1 {
+		t.Fatalf("expected the source and overrides to survive saving, got %+v", stored)
+	}
+
+	encoded, err := json.Marshal(stored)
+	if err != nil {
+		t.Fatalf("encode record: %v", err)
+	}
+	if strings.Contains(string(encoded), "sourceRaw") || strings.Contains(string(encoded), "overrides") {
+		t.Fatalf("expected records to omit the CRD source and overrides, got %s", encoded)
+	}
+}
+
+func TestRegenerateManifestFallsBackToStoredYAMLWithoutSource(t *testing.T) {
+	manifests := &services.ManifestService{}
+	record, err := manifests.SaveManifest(context.Background(), models.SaveManifestRequest{
+		Title: "widget",
+		YAML:  "apiVersion: example.io/v1\nkind: Widget\nmetadata:\n  name: app\nspec:\n  mode: fast\n",
+	})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	handler := NewCRDHandler(nil, services.NewCRDService(config.Config{}), services.NewYAMLService(), manifests)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/manifests/"+record.ID+"/regenerate", nil)
+	req.SetPathValue("id", record.ID)
+	rec := httptest.NewRecorder()
+	handler.RegenerateManifest(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.RegenerateManifestResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if envelope.Data.Template.Kind != "Widget" || !strings.Contains(envelope.Data.YAML, "mode: fast") {
+		t.Fatalf("expected the stored YAML to be re-parsed, got %+v", envelope.Data)
+	}
+	if len(envelope.Data.Warnings) == 0 || !strings.Contains(envelope.Data.Warnings[0], "without its CRD source") {
+		t.Fatalf("expected a warning about the missing source, got %v", envelope.Data.Warnings)
+	}
+}
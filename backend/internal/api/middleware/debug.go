@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	debugBodyLimit = 64 * 1024
+	redacted       = "[REDACTED]"
+)
+
+var (
+	sensitiveKeyRegex   = regexp.MustCompile(`(?i)(password|passwd|secret|token|authorization|api[-_]?key|private[-_]?key|credential|kubeconfig)`)
+	secretManifestRegex = regexp.MustCompile(`(?m)^\s*kind:\s*Secret\s*$`)
+)
+
+// DebugLogger logs each request body and response envelope when enabled.
+// Header values are never logged, JSON keys that look like credentials are
+// masked, and embedded manifests are replaced by a size and kind summary so
+// credentials inside them, such as env values, never reach the log.
+func DebugLogger(enabled bool, logger *log.Logger, next http.Handler) http.Handler {
+	if !enabled {
+		return next
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody []byte
+		if r.Body != nil {
+			requestBody, _ = io.ReadAll(io.LimitReader(r.Body, debugBodyLimit))
+			r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(requestBody), r.Body))
+		}
+
+		recorder := &debugResponseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		logger.Printf("[DEBUG] %s %s -> %d request=%s response=%s",
+			r.Method, r.URL.Path, recorder.status, redactBody(requestBody), redactBody(recorder.body.Bytes()))
+	})
+}
+
+type debugResponseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *debugResponseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *debugResponseRecorder) Write(data []byte) (int, error) {
+	if remaining := debugBodyLimit - r.body.Len(); remaining > 0 {
+		r.body.Write(data[:min(len(data), remaining)])
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+func redactBody(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return "<empty>"
+	}
+
+	var decoded any
+	if err := json.Unmarshal(trimmed, &decoded); err != nil {
+		return "<non-JSON body omitted>"
+	}
+	encoded, err := json.Marshal(redactValue(decoded))
+	if err != nil {
+		return "<unencodable body omitted>"
+	}
+	return string(encoded)
+}
+
+func redactValue(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, child := range typed {
+			if sensitiveKeyRegex.MatchString(key) {
+				typed[key] = redacted
+				continue
+			}
+			typed[key] = redactValue(child)
+		}
+		return typed
+	case []any:
+		for i := range typed {
+			typed[i] = redactValue(typed[i])
+		}
+		return typed
+	case string:
+		if secretManifestRegex.MatchString(typed) || strings.Contains(typed, "stringData:") {
+			return redacted
+		}
+		if kinds, ok := manifestKinds(typed); ok {
+			summary := fmt.Sprintf("[OMITTED %d-byte manifest", len(typed))
+			if len(kinds) > 0 {
+				summary += " kind=" + strings.Join(kinds, ",")
+			}
+			return summary + "]"
+		}
+		return typed
+	default:
+		return typed
+	}
+}
+
+// manifestKinds reports whether text is a YAML document with a map or list
+// at its root, along with the kind of each document that declares one.
+// Multi-line text that fails to parse is treated as a manifest too, since a
+// half-edited one can still carry credentials.
+func manifestKinds(text string) ([]string, bool) {
+	decoder := yaml.NewDecoder(strings.NewReader(text))
+	kinds := make([]string, 0)
+	isManifest := false
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return kinds, isManifest || strings.Contains(text, "\n")
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		root := doc.Content[0]
+		switch root.Kind {
+		case yaml.SequenceNode:
+			isManifest = true
+		case yaml.MappingNode:
+			isManifest = true
+			for i := 0; i+1 < len(root.Content); i += 2 {
+				if root.Content[i].Value == "kind" && root.Content[i+1].Kind == yaml.ScalarNode {
+					kinds = append(kinds, root.Content[i+1].Value)
+				}
+			}
+		}
+	}
+	return kinds, isManifest
+}
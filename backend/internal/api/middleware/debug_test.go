@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugLoggerCapturesRequestWhenEnabled(t *testing.T) {
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+
+	var seenBody string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		seenBody = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"success":true,"data":{"template":{"kind":"Widget"}}}`))
+	})
+
+	payload := `{"raw":"kind: CustomResourceDefinition","token":"ghp_supersecretvalue"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/crd/parse", strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer should-not-appear")
+	rec := httptest.NewRecorder()
+
+	DebugLogger(true, logger, next).ServeHTTP(rec, req)
+
+	if seenBody != payload {
+		t.Fatalf("expected handler to receive the original body, got %q", seenBody)
+	}
+	output := logs.String()
+	for _, expected := range []string{"POST /api/v1/crd/parse", "CustomResourceDefinition", `"kind":"Widget"`} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("expected debug log to contain %q, got %s", expected, output)
+		}
+	}
+	for _, secret := range []string{"ghp_supersecretvalue", "should-not-appear"} {
+		if strings.Contains(output, secret) {
+			t.Fatalf("expected %q to be redacted, got %s", secret, output)
+		}
+	}
+}
+
+func TestDebugLoggerSilentWhenDisabled(t *testing.T) {
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/crd/parse", strings.NewReader(`{"raw":"x"}`))
+	DebugLogger(false, logger, next).ServeHTTP(httptest.NewRecorder(), req)
+
+	if logs.Len() != 0 {
+		t.Fatalf("expected no debug output when disabled, got %s", logs.String())
+	}
+}
+
+func TestRedactBodyDropsSecretManifests(t *testing.T) {
+	output := redactBody([]byte(`{"raw":"apiVersion: v1\nkind: Secret\ndata:\n  password: aHVudGVyMg==\n"}`))
+	if strings.Contains(output, "aHVudGVyMg==") {
+		t.Fatalf("expected secret data to be redacted, got %s", output)
+	}
+}
+
+func TestRedactBodyOmitsManifestBodies(t *testing.T) {
+	output := redactBody([]byte(`{"yaml":"apiVersion: apps/v1\nkind: Deployment\nspec:\n  template:\n    spec:\n      containers:\n        - name: app\n          env:\n            - name: GH_PAT\n              value: ghp_leakedvalue\n            - name: DB_PASSWORD\n              value: hunter2\n","note":"plain text"}`))
+	for _, secret := range []string{"ghp_leakedvalue", "hunter2"} {
+		if strings.Contains(output, secret) {
+			t.Fatalf("expected %q to be omitted, got %s", secret, output)
+		}
+	}
+	for _, expected := range []string{"kind=Deployment", `"note":"plain text"`} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("expected %q to be logged, got %s", expected, output)
+		}
+	}
+
+	output = redactBody([]byte(`{"raw":"password: hunter2\n  broken: [yaml"}`))
+	if strings.Contains(output, "hunter2") {
+		t.Fatalf("expected unparseable multi-line YAML to be omitted, got %s", output)
+	}
+}
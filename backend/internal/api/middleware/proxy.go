@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// forwardedHeaders are the proxy headers handlers use to rebuild the public
+// URL of a request.
+var forwardedHeaders = []string{"X-Forwarded-Proto", "X-Forwarded-Host", "X-Forwarded-For"}
+
+// TrustForwardedHeaders drops X-Forwarded-* headers unless the connection
+// comes from one of the trusted proxies, given as IPs or CIDR ranges, so
+// clients cannot choose the host or scheme the server believes it serves.
+// Entries that are neither are ignored.
+func TrustForwardedHeaders(proxies []string, next http.Handler) http.Handler {
+	trusted := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			trusted = append(trusted, network)
+			continue
+		}
+		if ip := net.ParseIP(proxy); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			trusted = append(trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !fromTrustedProxy(r, trusted) {
+			for _, header := range forwardedHeaders {
+				r.Header.Del(header)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func fromTrustedProxy(r *http.Request, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(clientIP(r))
+	if ip == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
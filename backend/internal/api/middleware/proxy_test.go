@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrustForwardedHeadersOnlyKeepsHeadersFromTrustedProxies(t *testing.T) {
+	var seen string
+	handler := TrustForwardedHeaders([]string{"10.0.0.0/8", "192.0.2.7"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.Header.Get("X-Forwarded-Host")
+	}))
+
+	for remote, want := range map[string]string{
+		"10.1.2.3:5000":    "kubetools.example.com",
+		"192.0.2.7:5000":   "kubetools.example.com",
+		"203.0.113.9:5000": "",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remote
+		req.Header.Set("X-Forwarded-Host", "kubetools.example.com")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if seen != want {
+			t.Fatalf("from %s: expected forwarded host %q, got %q", remote, want, seen)
+		}
+	}
+}
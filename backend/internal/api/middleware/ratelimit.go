@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// RateLimiter is an in-memory token bucket per client key. Buckets idle for
+// longer than it takes to refill completely are evicted on a later call, so
+// memory stays bounded by the number of recently active clients.
+type RateLimiter struct {
+	mu        sync.Mutex
+	rate      float64 // tokens per second
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter allows perMinute requests per client on average with bursts
+// of up to burst requests. A non-positive perMinute disables limiting.
+func NewRateLimiter(perMinute, burst int) *RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:    float64(perMinute) / 60,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token for key. When none is left it reports how long until
+// the next one is available.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.evictIdle(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+func (l *RateLimiter) evictIdle(now time.Time) {
+	idle := time.Duration(l.burst / l.rate * float64(time.Second))
+	if now.Sub(l.lastSweep) < idle {
+		return
+	}
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.last) >= idle {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// RateLimit rejects requests over the limiter's budget for the client IP with
+// 429 RATE_LIMITED and a Retry-After header. A nil limiter passes everything
+// through. The IP comes from the connection, not X-Forwarded-For, so clients
+// cannot pick their own bucket.
+func RateLimit(limiter *RateLimiter, next http.Handler) http.Handler {
+	if limiter == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, wait := limiter.Allow(clientIP(r))
+		if allowed {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_ = json.NewEncoder(w).Encode(models.APIResponse{
+			Success:   false,
+			Error:     &models.APIError{Code: "RATE_LIMITED", Message: "too many requests; retry later"},
+			Timestamp: time.Now().UTC(),
+		})
+	})
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimitReturns429PerClientIP(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(6, 2)
+	limiter.now = func() time.Time { return now }
+
+	handler := RateLimit(limiter, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	call := func(remote string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/crd/import-url", nil)
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := call("10.0.0.1:5000"); rec.Code != http.StatusOK {
+			t.Fatalf("expected burst request %d to pass, got %d", i, rec.Code)
+		}
+	}
+	limited := call("10.0.0.1:5001")
+	if limited.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 once the burst is spent, got %d", limited.Code)
+	}
+	if limited.Header().Get("Retry-After") != "10" {
+		t.Fatalf("expected Retry-After of 10 seconds at 6 requests/minute, got %q", limited.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(limited.Body.String(), `"code":"RATE_LIMITED"`) {
+		t.Fatalf("expected RATE_LIMITED error code, got %s", limited.Body.String())
+	}
+	if rec := call("10.0.0.2:5000"); rec.Code != http.StatusOK {
+		t.Fatalf("expected another client to have its own bucket, got %d", rec.Code)
+	}
+
+	now = now.Add(10 * time.Second)
+	if rec := call("10.0.0.1:5000"); rec.Code != http.StatusOK {
+		t.Fatalf("expected a token to refill after 10s, got %d", rec.Code)
+	}
+}
+
+func TestRateLimiterEvictsIdleBuckets(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(60, 5)
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("10.0.0.1")
+	limiter.Allow("10.0.0.2")
+	now = now.Add(time.Minute)
+	limiter.Allow("10.0.0.3")
+
+	if len(limiter.buckets) != 1 {
+		t.Fatalf("expected idle buckets to be evicted, got %d buckets", len(limiter.buckets))
+	}
+	if NewRateLimiter(0, 5) != nil {
+		t.Fatalf("expected a zero rate to disable limiting")
+	}
+}
@@ -10,24 +10,68 @@ import (
 
 type Dependencies struct {
 	CORSOrigins []string
-	Templates   *services.TemplateService
+	Debug       bool
+	Templates   services.TemplateStore
 	CRD         *services.CRDService
 	YAML        *services.YAMLService
-	Manifests   *services.ManifestService
+	Manifests   services.ManifestStore
+	Cluster     *services.ClusterImporter
+	AdminToken  string
+	// TrustedProxies are the proxy IPs or CIDR ranges whose X-Forwarded-*
+	// headers are honored.
+	TrustedProxies []string
+	// ImportRateLimiter throttles endpoints that fetch remote URLs; nil
+	// leaves them unthrottled.
+	ImportRateLimiter *middleware.RateLimiter
 }
 
 func NewRouter(deps Dependencies) http.Handler {
 	mux := http.NewServeMux()
 	crdHandler := handlers.NewCRDHandler(deps.Templates, deps.CRD, deps.YAML, deps.Manifests)
+	clusterHandler := handlers.NewClusterHandler(deps.Cluster, deps.CRD, deps.AdminToken)
+	adminHandler := handlers.NewAdminHandler(deps.AdminToken, deps.Manifests)
 
 	mux.HandleFunc("/healthz", handlers.Health)
-	mux.HandleFunc("/api/v1/health", handlers.Health)
+	mux.HandleFunc("/readyz", crdHandler.Ready)
+	mux.HandleFunc("/api/v1/health", crdHandler.Health)
 	mux.HandleFunc("/api/v1/crd/templates", crdHandler.Templates)
+	mux.HandleFunc("/api/v1/crd/templates/import-bundle", crdHandler.ImportTemplateBundle)
+	mux.HandleFunc("/api/v1/crd/templates/export", crdHandler.ExportTemplates)
+	mux.HandleFunc("/api/v1/crd/templates/{id}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			crdHandler.GetTemplate(w, r)
+		case http.MethodPatch:
+			crdHandler.PatchTemplate(w, r)
+		case http.MethodDelete:
+			crdHandler.DeleteTemplate(w, r)
+		default:
+			handlers.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET, PATCH and DELETE are supported")
+		}
+	})
 	mux.HandleFunc("/api/v1/crd/parse", crdHandler.ParseCRD)
+	mux.HandleFunc("/api/v1/crd/jsonpaths", crdHandler.JSONPaths)
+	mux.HandleFunc("/api/v1/crd/rbac", crdHandler.GenerateRBAC)
+	mux.HandleFunc("/api/v1/crd/version-diff", crdHandler.VersionDiff)
 	mux.HandleFunc("/api/v1/crd/validate", crdHandler.ValidateCRD)
-	mux.HandleFunc("/api/v1/crd/import-url", crdHandler.ImportCRDFromURL)
+	mux.HandleFunc("/api/v1/crd/validate-instance", crdHandler.ValidateInstance)
+	mux.Handle("/api/v1/crd/import-url", middleware.RateLimit(deps.ImportRateLimiter, http.HandlerFunc(crdHandler.ImportCRDFromURL)))
+	mux.Handle("/api/v1/crd/import-and-sample", middleware.RateLimit(deps.ImportRateLimiter, http.HandlerFunc(crdHandler.ImportAndSample)))
+	mux.Handle("/api/v1/crd/import-cluster", middleware.RateLimit(deps.ImportRateLimiter, http.HandlerFunc(clusterHandler.ImportCRD)))
 	mux.HandleFunc("/api/v1/crd/submit", crdHandler.SubmitCRD)
+	mux.HandleFunc("/api/v1/crd/submit-bulk", crdHandler.SubmitCRDBulk)
+	mux.HandleFunc("/api/v1/crd/import-csv", crdHandler.ImportCSV)
+	mux.HandleFunc("/api/v1/crd/schema-at", crdHandler.SchemaAt)
+	mux.HandleFunc("/api/v1/crd/export-helm-values", crdHandler.ExportHelmValues)
 	mux.HandleFunc("/api/v1/crd/generate-yaml", crdHandler.GenerateYAML)
+	mux.HandleFunc("/api/v1/crd/generate-yaml-multi", crdHandler.GenerateMultiYAML)
+	mux.HandleFunc("/api/v1/crd/generate-kustomize", crdHandler.GenerateKustomize)
+	mux.HandleFunc("/api/v1/crd/generate-from-set", crdHandler.GenerateFromSet)
+	mux.HandleFunc("/api/v1/crd/generate-preview", crdHandler.GeneratePreview)
+	mux.HandleFunc("/api/v1/crd/field-diff", crdHandler.FieldDiff)
+	mux.HandleFunc("/api/v1/crd/pod-spec", crdHandler.PodSpec)
+	mux.HandleFunc("/api/v1/crd/strip", crdHandler.StripYAML)
+	mux.HandleFunc("/api/v1/compare", crdHandler.CompareYAML)
 	mux.HandleFunc("/api/v1/manifests", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
@@ -38,6 +82,25 @@ func NewRouter(deps Dependencies) http.Handler {
 			handlers.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET and POST are supported")
 		}
 	})
+	mux.HandleFunc("/api/v1/admin/compact", adminHandler.CompactManifests)
+	mux.HandleFunc("/api/v1/manifests/duplicates", crdHandler.ManifestDuplicates)
+	mux.HandleFunc("/api/v1/manifests/bulk-tag", crdHandler.BulkTagManifests)
+	mux.HandleFunc("/api/v1/manifests/diff", crdHandler.DiffManifests)
+	mux.HandleFunc("/api/v1/manifests/{id}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			crdHandler.GetManifest(w, r)
+		case http.MethodPut:
+			crdHandler.UpdateManifest(w, r)
+		default:
+			handlers.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET and PUT are supported")
+		}
+	})
+	mux.HandleFunc("/api/v1/manifests/{id}/note", crdHandler.UpdateManifestNote)
+	mux.HandleFunc("/api/v1/manifests/{id}/apply-command", crdHandler.ManifestApplyCommand)
+	mux.HandleFunc("/api/v1/manifests/{id}/download", crdHandler.DownloadManifest)
+	mux.HandleFunc("/api/v1/manifests/{id}/regenerate", crdHandler.RegenerateManifest)
 
-	return middleware.CORS(deps.CORSOrigins, mux)
+	return middleware.TrustForwardedHeaders(deps.TrustedProxies,
+		middleware.CORS(deps.CORSOrigins, middleware.DebugLogger(deps.Debug, nil, mux)))
 }
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"github.com/aneeshchawla/kubetools/backend/internal/services"
+)
+
+func TestApplyCommandDownloadURLIsServed(t *testing.T) {
+	manifests := &services.ManifestService{}
+	record, err := manifests.SaveManifest(context.Background(), models.SaveManifestRequest{
+		Title: "web",
+		YAML:  "apiVersion: v1\nkind: ConfigMap\n",
+	})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	router := NewRouter(Dependencies{Manifests: manifests, YAML: services.NewYAMLService()})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://kubetools.local/api/v1/manifests/"+record.ID+"/apply-command", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d with body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data models.ManifestApplyCommandResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	downloadURL, err := neturl.Parse(envelope.Data.DownloadURL)
+	if err != nil {
+		t.Fatalf("parse download url %q: %v", envelope.Data.DownloadURL, err)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, downloadURL.String(), nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != record.YAML {
+		t.Fatalf("expected the download url to serve the manifest, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
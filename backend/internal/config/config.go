@@ -1,8 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -13,6 +16,51 @@ type Config struct {
 	MongoDatabase     string
 	MongoManifestColl string
 	MongoTemplateColl string
+	// Zero values leave the MongoDB driver defaults in place.
+	MongoMaxPoolSize            uint64
+	MongoConnectTimeout         time.Duration
+	MongoServerSelectionTimeout time.Duration
+	MongoMaxConcurrentOps       int
+	TemplateSeedFile            string
+	TLSCertFile                 string
+	TLSKeyFile                  string
+	ReadTimeout                 time.Duration
+	WriteTimeout                time.Duration
+	IdleTimeout                 time.Duration
+	ReadHeaderTimeout           time.Duration
+	Debug                       bool
+	ManifestTTL                 time.Duration
+	StorageBackend              string
+	SQLitePath                  string
+	KubeconfigPath              string
+	KubeContext                 string
+	AdminToken                  string
+	// ImporterRetryAttempts bounds how many times the basic CRD importer
+	// fetches a source before giving up; the delay doubles after each try.
+	ImporterRetryAttempts  int
+	ImporterRetryBaseDelay time.Duration
+	// ImportURLRateLimit caps URL imports per client IP per minute, with
+	// bursts up to ImportURLRateBurst. Zero disables the limit.
+	ImportURLRateLimit int
+	ImportURLRateBurst int
+	// ImportSources replaces the importer's built-in upstream list when set.
+	ImportSources []string
+	// CRDMaxDepth, CRDMaxFields and CRDMaxDefaults bound how deep the CRD
+	// parser walks a schema, how many fields it collects and how many of
+	// those become default fields.
+	CRDMaxDepth    int
+	CRDMaxFields   int
+	CRDMaxDefaults int
+	// CRDServiceKeywords and CRDServiceMarkers replace the built-in lists
+	// used to spot service-like components in a CRD spec when set.
+	CRDServiceKeywords []string
+	CRDServiceMarkers  []string
+	// StripPaths replaces DefaultStripPaths as the fields removed from pasted
+	// and cluster-imported resources when set.
+	StripPaths []string
+	// TrustedProxies lists the proxy IPs or CIDR ranges whose X-Forwarded-*
+	// headers are honored. Headers from any other client are dropped.
+	TrustedProxies []string
 }
 
 func Load() Config {
@@ -23,22 +71,110 @@ func Load() Config {
 	mongoDatabase := getenv("MONGODB_DATABASE", "kubebuilder")
 	mongoManifestColl := getenv("MONGODB_MANIFEST_COLLECTION", "manifests")
 	mongoTemplateColl := getenv("MONGODB_TEMPLATE_COLLECTION", "templates")
+	mongoMaxPoolSize := getenvUint("MONGO_MAX_POOL_SIZE", 0)
+	mongoConnectTimeout := getenvDuration("MONGO_CONNECT_TIMEOUT", 0)
+	mongoServerSelectionTimeout := getenvDuration("MONGO_SERVER_SELECTION_TIMEOUT", 0)
+	mongoMaxConcurrentOps := int(getenvUint("MONGO_MAX_CONCURRENT_OPS", 0))
+	templateSeedFile := strings.TrimSpace(os.Getenv("TEMPLATE_SEED_FILE"))
+	tlsCertFile := strings.TrimSpace(os.Getenv("TLS_CERT_FILE"))
+	tlsKeyFile := strings.TrimSpace(os.Getenv("TLS_KEY_FILE"))
+	// The write timeout must outlast the 12s import-url fetch plus the time to
+	// stream a large response back, so it defaults well above the read timeout.
+	readTimeout := getenvDuration("SERVER_READ_TIMEOUT", 15*time.Second)
+	writeTimeout := getenvDuration("SERVER_WRITE_TIMEOUT", 30*time.Second)
+	idleTimeout := getenvDuration("SERVER_IDLE_TIMEOUT", 60*time.Second)
+	readHeaderTimeout := getenvDuration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second)
+	debug := getenvBool("DEBUG", false)
+	manifestTTL := getenvDuration("MANIFEST_TTL", 0)
+	storageBackend := strings.ToLower(strings.TrimSpace(getenv("STORAGE_BACKEND", "mongo")))
+	sqlitePath := getenv("SQLITE_PATH", "kubetools.db")
+	kubeconfigPath := strings.TrimSpace(os.Getenv("KUBECONFIG"))
+	kubeContext := strings.TrimSpace(os.Getenv("K8S_CONTEXT"))
+	adminToken := strings.TrimSpace(os.Getenv("ADMIN_TOKEN"))
+	importerRetryAttempts := int(getenvUint("IMPORTER_RETRY_ATTEMPTS", 3))
+	importerRetryBaseDelay := getenvDuration("IMPORTER_RETRY_BASE_DELAY", time.Second)
+	importSources := getenvList("IMPORT_SOURCES")
+	importURLRateLimit := int(getenvUint("IMPORT_URL_RATE_LIMIT", 10))
+	importURLRateBurst := int(getenvUint("IMPORT_URL_RATE_BURST", 5))
+	crdMaxDepth := int(getenvUint("CRD_MAX_DEPTH", 4))
+	crdMaxFields := int(getenvUint("CRD_MAX_FIELDS", 420))
+	crdMaxDefaults := int(getenvUint("CRD_MAX_DEFAULTS", 64))
+	crdServiceKeywords := getenvList("CRD_SERVICE_KEYWORDS")
+	crdServiceMarkers := getenvList("CRD_SERVICE_MARKERS")
+	stripPaths := getenvList("STRIP_PATHS")
+	trustedProxies := getenvList("TRUSTED_PROXIES")
 	origins := strings.Split(originsRaw, ",")
 	for i := range origins {
 		origins[i] = strings.TrimSpace(origins[i])
 	}
 
 	return Config{
-		Host:              host,
-		Port:              port,
-		CORSOrigins:       origins,
-		MongoURI:          mongoURI,
-		MongoDatabase:     mongoDatabase,
-		MongoManifestColl: mongoManifestColl,
-		MongoTemplateColl: mongoTemplateColl,
+		Host:                        host,
+		Port:                        port,
+		CORSOrigins:                 origins,
+		MongoURI:                    mongoURI,
+		MongoDatabase:               mongoDatabase,
+		MongoManifestColl:           mongoManifestColl,
+		MongoTemplateColl:           mongoTemplateColl,
+		MongoMaxPoolSize:            mongoMaxPoolSize,
+		MongoConnectTimeout:         mongoConnectTimeout,
+		MongoServerSelectionTimeout: mongoServerSelectionTimeout,
+		MongoMaxConcurrentOps:       mongoMaxConcurrentOps,
+		TemplateSeedFile:            templateSeedFile,
+		TLSCertFile:                 tlsCertFile,
+		TLSKeyFile:                  tlsKeyFile,
+		ReadTimeout:                 readTimeout,
+		WriteTimeout:                writeTimeout,
+		IdleTimeout:                 idleTimeout,
+		ReadHeaderTimeout:           readHeaderTimeout,
+		Debug:                       debug,
+		ManifestTTL:                 manifestTTL,
+		StorageBackend:              storageBackend,
+		SQLitePath:                  sqlitePath,
+		KubeconfigPath:              kubeconfigPath,
+		KubeContext:                 kubeContext,
+		AdminToken:                  adminToken,
+		ImporterRetryAttempts:       importerRetryAttempts,
+		ImporterRetryBaseDelay:      importerRetryBaseDelay,
+		ImportSources:               importSources,
+		ImportURLRateLimit:          importURLRateLimit,
+		ImportURLRateBurst:          importURLRateBurst,
+		CRDMaxDepth:                 crdMaxDepth,
+		CRDMaxFields:                crdMaxFields,
+		CRDMaxDefaults:              crdMaxDefaults,
+		CRDServiceKeywords:          crdServiceKeywords,
+		CRDServiceMarkers:           crdServiceMarkers,
+		StripPaths:                  stripPaths,
+		TrustedProxies:              trustedProxies,
 	}
 }
 
+// TLSEnabled reports whether both a certificate and key were configured.
+func (c Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// ValidateTLS checks that the configured certificate and key files exist.
+// Setting only one of the two is treated as a configuration error.
+func (c Config) ValidateTLS() error {
+	if c.TLSCertFile == "" && c.TLSKeyFile == "" {
+		return nil
+	}
+	if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	for name, path := range map[string]string{"TLS_CERT_FILE": c.TLSCertFile, "TLS_KEY_FILE": c.TLSKeyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("%s %q: %w", name, path, err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("%s %q is a directory", name, path)
+		}
+	}
+	return nil
+}
+
 func getenv(key, fallback string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -46,3 +182,56 @@ func getenv(key, fallback string) string {
 	}
 	return value
 }
+
+// getenvList splits a comma- or newline-separated value, dropping blanks.
+func getenvList(key string) []string {
+	fields := strings.FieldsFunc(os.Getenv(key), func(r rune) bool {
+		return r == ',' || r == '\n'
+	})
+	out := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			out = append(out, field)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func getenvDuration(key string, fallback time.Duration) time.Duration {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		return fallback
+	}
+	return parsed
+}
+
+func getenvUint(key string, fallback uint64) uint64 {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func getenvBool(key string, fallback bool) bool {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
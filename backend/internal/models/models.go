@@ -15,11 +15,32 @@ type APIResponse struct {
 }
 
 type FieldDefinition struct {
-	Path        string `json:"path"`
-	Label       string `json:"label,omitempty"`
-	Value       string `json:"value,omitempty"`
-	Description string `json:"description"`
-	Type        string `json:"type,omitempty"`
+	Path        string   `json:"path"`
+	Label       string   `json:"label,omitempty"`
+	Value       string   `json:"value,omitempty"`
+	Description string   `json:"description"`
+	Type        string   `json:"type,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	// Immutable marks fields whose CEL validation rules forbid changes after
+	// creation, so editing them requires recreating the resource.
+	Immutable bool `json:"immutable,omitempty"`
+	// Constraints carries the schema's validation bounds so clients can show
+	// hints such as "1-10" or the expected pattern next to the input.
+	Constraints *FieldConstraints `json:"constraints,omitempty"`
+	// Rules lists the messages of the field's x-kubernetes-validations so
+	// authors see the CEL constraints the API server will enforce.
+	Rules []string `json:"rules,omitempty"`
+}
+
+type FieldConstraints struct {
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+	Pattern string   `json:"pattern,omitempty"`
+	Format  string   `json:"format,omitempty"`
+	// MinItems is set on the fields of a required array that declares
+	// minItems, so an array that ends up shorter can be flagged.
+	MinItems int `json:"minItems,omitempty"`
 }
 
 type TemplateDefinition struct {
@@ -28,22 +49,118 @@ type TemplateDefinition struct {
 	APIVersion     string            `json:"apiVersion"`
 	Kind           string            `json:"kind"`
 	Note           string            `json:"note"`
+	Category       string            `json:"category,omitempty"`
 	DefaultFields  []FieldDefinition `json:"defaultFields"`
 	OptionalFields []FieldDefinition `json:"optionalFields"`
+	// Scope names the team that imported the template. Empty means global,
+	// which is how built-ins and templates saved before scoping are listed.
+	Scope string `json:"scope,omitempty"`
+	// SelectedVersion names the CRD version whose schema the fields were
+	// built from, normally the storage version.
+	SelectedVersion string `json:"selectedVersion,omitempty"`
+	// AvailableVersions lists every version a parsed CRD declares so clients
+	// can tell when the template was built from one of several schemas.
+	AvailableVersions []VersionInfo `json:"availableVersions,omitempty"`
+	// Rules holds the validation messages declared on the spec object itself,
+	// which usually relate several fields and so belong to no single one.
+	Rules []string `json:"rules,omitempty"`
+}
+
+type VersionInfo struct {
+	Name      string `json:"name"`
+	Served    bool   `json:"served"`
+	Storage   bool   `json:"storage"`
+	HasSchema bool   `json:"hasSchema"`
+}
+
+type PatchTemplateRequest struct {
+	Title             *string           `json:"title,omitempty"`
+	Note              *string           `json:"note,omitempty"`
+	Category          *string           `json:"category,omitempty"`
+	AddFields         []FieldDefinition `json:"addFields,omitempty"`
+	AddOptionalFields []FieldDefinition `json:"addOptionalFields,omitempty"`
+	RemoveFields      []string          `json:"removeFields,omitempty"`
 }
 
 type ParseCRDRequest struct {
-	Raw string `json:"raw"`
+	Raw                  string `json:"raw"`
+	IncludeAllFields     bool   `json:"includeAllFields,omitempty"`
+	IncludeSkippedFields bool   `json:"includeSkippedFields,omitempty"`
+	Prioritization       string `json:"prioritization,omitempty"`
+	// OmitInferredDescriptions leaves descriptions empty for fields whose
+	// schema has none instead of filling in a placeholder.
+	OmitInferredDescriptions bool `json:"omitInferredDescriptions,omitempty"`
+	// StripPaths replaces the server's strip policy for a pasted resource.
+	StripPaths []string `json:"stripPaths,omitempty"`
 }
 
 type ParseCRDResponse struct {
-	Template TemplateDefinition `json:"template"`
+	Template      TemplateDefinition `json:"template"`
+	AllFields     []FieldDefinition  `json:"allFields,omitempty"`
+	SkippedFields []FieldDefinition  `json:"skippedFields,omitempty"`
+	Warnings      []string           `json:"warnings,omitempty"`
 }
 
-type ValidateCRDRequest struct {
+type JSONPathsRequest struct {
 	Raw string `json:"raw"`
 }
 
+type JSONPathsResponse struct {
+	Paths []string `json:"paths"`
+}
+
+type ImportTemplateBundleResponse struct {
+	Created  int      `json:"created"`
+	Updated  int      `json:"updated"`
+	Rejected int      `json:"rejected"`
+	Errors   []string `json:"errors"`
+}
+
+type VersionDiffRequest struct {
+	Raw  string `json:"raw"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+type FieldTypeChange struct {
+	Path string `json:"path"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type VersionDiffResponse struct {
+	From        string            `json:"from"`
+	To          string            `json:"to"`
+	Added       []string          `json:"added"`
+	Removed     []string          `json:"removed"`
+	TypeChanges []FieldTypeChange `json:"typeChanges"`
+	Breaking    bool              `json:"breaking"`
+}
+
+type GenerateRBACRequest struct {
+	Raw            string `json:"raw"`
+	Namespace      string `json:"namespace,omitempty"`
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	ClusterScoped  bool   `json:"clusterScoped,omitempty"`
+}
+
+type GenerateRBACResponse struct {
+	YAML   string `json:"yaml"`
+	Group  string `json:"group"`
+	Plural string `json:"plural"`
+	Kind   string `json:"kind"`
+}
+
+type ValidateCRDRequest struct {
+	Raw    string `json:"raw"`
+	Strict bool   `json:"strict,omitempty"`
+}
+
+type ValidateInstanceRequest struct {
+	CRD      string `json:"crd"`
+	Instance string `json:"instance"`
+}
+
 type ValidateCRDResponse struct {
 	Valid      bool     `json:"valid"`
 	Errors     []string `json:"errors"`
@@ -53,18 +170,109 @@ type ValidateCRDResponse struct {
 }
 
 type SubmitCRDRequest struct {
-	Title string `json:"title"`
-	Raw   string `json:"raw"`
+	Title        string                 `json:"title"`
+	Raw          string                 `json:"raw"`
+	Environments []SubmitCRDEnvironment `json:"environments,omitempty"`
+}
+
+// SubmitCRDEnvironment layers field overrides on top of the template defaults
+// to produce one saved manifest per environment.
+type SubmitCRDEnvironment struct {
+	Name      string            `json:"name"`
+	Overrides []FieldDefinition `json:"overrides,omitempty"`
 }
 
 type SubmitCRDResponse struct {
-	Template   TemplateDefinition  `json:"template"`
-	Manifest   ManifestRecord      `json:"manifest"`
-	Validation ValidateCRDResponse `json:"validation"`
+	Template              TemplateDefinition  `json:"template"`
+	Manifest              ManifestRecord      `json:"manifest"`
+	Manifests             []ManifestRecord    `json:"manifests,omitempty"`
+	Validation            ValidateCRDResponse `json:"validation"`
+	MissingRequiredFields []string            `json:"missingRequiredFields,omitempty"`
+}
+
+// SubmitCRDBulkRequest carries a multi-document bundle; every
+// CustomResourceDefinition in it is submitted independently.
+type SubmitCRDBulkRequest struct {
+	Raw string `json:"raw"`
+}
+
+// SubmitCRDBulkResult is the outcome for one CRD in a bundle. Index is the
+// document's position among the bundle's non-empty documents; Error is set
+// when that document could not be submitted.
+type SubmitCRDBulkResult struct {
+	Index                 int                 `json:"index"`
+	Template              *TemplateDefinition `json:"template,omitempty"`
+	Manifest              *ManifestRecord     `json:"manifest,omitempty"`
+	Validation            ValidateCRDResponse `json:"validation"`
+	MissingRequiredFields []string            `json:"missingRequiredFields,omitempty"`
+	Error                 string              `json:"error,omitempty"`
+}
+
+type SubmitCRDBulkResponse struct {
+	Results   []SubmitCRDBulkResult `json:"results"`
+	Submitted int                   `json:"submitted"`
+	Failed    int                   `json:"failed"`
+	// Skipped lists the indexes of documents that are not CRDs.
+	Skipped []int `json:"skipped,omitempty"`
 }
 
 type ImportCRDURLRequest struct {
 	URL string `json:"url"`
+	// Token is sent as a bearer token to GitHub hosts so CRDs in private
+	// repositories can be fetched. It is never sent to other hosts.
+	Token string `json:"token,omitempty"`
+}
+
+type ExportHelmValuesRequest struct {
+	Raw string `json:"raw"`
+}
+
+type ExportHelmValuesResponse struct {
+	Values string `json:"values"`
+}
+
+type SchemaAtRequest struct {
+	Raw  string `json:"raw"`
+	Path string `json:"path"`
+}
+
+// SchemaAtResponse is the raw openAPIV3Schema node at Path in the schema of
+// the CRD version named by Version.
+type SchemaAtResponse struct {
+	Path    string         `json:"path"`
+	Version string         `json:"version,omitempty"`
+	Schema  map[string]any `json:"schema"`
+}
+
+// ImportCSVRequest carries an OLM ClusterServiceVersion. Raw may also hold
+// the bundle's CRDs as extra YAML documents; Bundle is an alternative place
+// for them.
+type ImportCSVRequest struct {
+	Raw    string `json:"raw"`
+	Bundle string `json:"bundle,omitempty"`
+}
+
+// CSVOwnedCRD is one spec.customresourcedefinitions.owned entry of a CSV.
+type CSVOwnedCRD struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Version     string `json:"version"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ImportCSVResult pairs an owned CRD with the template parsed from the
+// bundle or found among stored templates. Source is "bundle" or "stored";
+// Error explains why no template could be produced.
+type ImportCSVResult struct {
+	Owned    CSVOwnedCRD         `json:"owned"`
+	Template *TemplateDefinition `json:"template,omitempty"`
+	Source   string              `json:"source,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+type ImportCSVResponse struct {
+	Results []ImportCSVResult `json:"results"`
 }
 
 type ImportCRDURLResponse struct {
@@ -73,22 +281,175 @@ type ImportCRDURLResponse struct {
 	Validation ValidateCRDResponse `json:"validation"`
 }
 
+type ImportCRDSampleResponse struct {
+	SourceURL  string              `json:"sourceUrl"`
+	Template   TemplateDefinition  `json:"template"`
+	YAML       string              `json:"yaml"`
+	Validation ValidateCRDResponse `json:"validation"`
+	Warnings   []string            `json:"warnings,omitempty"`
+}
+
+type ImportClusterCRDRequest struct {
+	Name       string `json:"name"`
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	Context    string `json:"context,omitempty"`
+}
+
+type ImportClusterCRDResponse struct {
+	SourceURL  string              `json:"sourceUrl"`
+	Raw        string              `json:"raw"`
+	Validation ValidateCRDResponse `json:"validation"`
+	Template   *TemplateDefinition `json:"template,omitempty"`
+}
+
 type GenerateYAMLRequest struct {
+	APIVersion         string            `json:"apiVersion"`
+	Kind               string            `json:"kind"`
+	Fields             []FieldDefinition `json:"fields"`
+	NamePrefix         string            `json:"namePrefix,omitempty"`
+	NameSuffix         string            `json:"nameSuffix,omitempty"`
+	ApplyKnownDefaults bool              `json:"applyKnownDefaults,omitempty"`
+	IncludeComments    bool              `json:"includeComments,omitempty"`
+	IncludeObject      bool              `json:"includeObject,omitempty"`
+	// Strict rejects fields that repeat a path with a different value
+	// instead of letting the last one win.
+	Strict bool `json:"strict,omitempty"`
+}
+
+type GenerateMultiYAMLRequest struct {
+	Documents []GenerateYAMLRequest `json:"documents"`
+}
+
+// GenerateFromSetRequest describes a resource as kubectl-style "path=value"
+// arguments, e.g. ["metadata.name=web", "spec.replicas=3"].
+type GenerateFromSetRequest struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Set        []string `json:"set"`
+}
+
+type GenerateFromSetResponse struct {
+	Fields []FieldDefinition `json:"fields"`
+	YAML   string            `json:"yaml"`
+}
+
+// GenerateKustomizeResponse maps each generated filename, including
+// kustomization.yaml, to its contents.
+type GenerateKustomizeResponse struct {
+	Files map[string]string `json:"files"`
+}
+
+type GenerateYAMLResponse struct {
+	YAML   string `json:"yaml"`
+	Object any    `json:"object,omitempty"`
+}
+
+// GeneratePreviewResponse is best-effort YAML for incomplete input together
+// with notes on what is still missing before it could be generated for real.
+type GeneratePreviewResponse struct {
+	YAML    string   `json:"yaml"`
+	Missing []string `json:"missing"`
+}
+
+type StripYAMLRequest struct {
+	Raw   string   `json:"raw"`
+	Paths []string `json:"paths,omitempty"`
+}
+
+type StripYAMLResponse struct {
+	YAML    string   `json:"yaml"`
+	Removed []string `json:"removed"`
+}
+
+type CompareYAMLRequest struct {
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+type CompareYAMLResponse struct {
+	Equal       bool     `json:"equal"`
+	Differences []string `json:"differences"`
+}
+
+type PodSpecContainer struct {
+	Name  string          `json:"name"`
+	Image string          `json:"image"`
+	Ports []int           `json:"ports,omitempty"`
+	Env   []PodSpecEnvVar `json:"env,omitempty"`
+}
+
+type PodSpecEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type PodSpecRequest struct {
+	// APIVersion and Kind default to apps/v1 Deployment; the kind decides
+	// where the pod spec lives, e.g. spec for a Pod or spec.template.spec.
+	APIVersion string             `json:"apiVersion,omitempty"`
+	Kind       string             `json:"kind,omitempty"`
+	Name       string             `json:"name,omitempty"`
+	Containers []PodSpecContainer `json:"containers"`
+}
+
+type PodSpecResponse struct {
+	Fields []FieldDefinition `json:"fields"`
+	YAML   string            `json:"yaml"`
+}
+
+type YAMLDiffEntry struct {
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+type YAMLDiff struct {
+	Equal   bool            `json:"equal"`
+	Added   []YAMLDiffEntry `json:"added"`
+	Removed []YAMLDiffEntry `json:"removed"`
+	Changed []YAMLDiffEntry `json:"changed"`
+}
+
+type FieldDiffRequest struct {
 	APIVersion string            `json:"apiVersion"`
 	Kind       string            `json:"kind"`
-	Fields     []FieldDefinition `json:"fields"`
+	Base       []FieldDefinition `json:"base"`
+	Edited     []FieldDefinition `json:"edited"`
 }
 
-type GenerateYAMLResponse struct {
-	YAML string `json:"yaml"`
+type FieldDiffResponse struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+	// Patch holds only the added and changed fields, ready to send to
+	// generate-yaml when the user wants to apply just their edits.
+	Patch GenerateYAMLRequest `json:"patch"`
 }
 
 type SaveManifestRequest struct {
-	Title      string `json:"title"`
-	Resource   string `json:"resource"`
-	APIVersion string `json:"apiVersion"`
-	Kind       string `json:"kind"`
-	YAML       string `json:"yaml"`
+	Title      string   `json:"title"`
+	Resource   string   `json:"resource"`
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	YAML       string   `json:"yaml"`
+	Note       string   `json:"note,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	// SourceRaw is the CRD the YAML was generated from and Overrides the
+	// field values layered over its defaults, kept so the manifest can be
+	// regenerated later. Updates leave each stored value alone when empty.
+	SourceRaw string            `json:"sourceRaw,omitempty"`
+	Overrides []FieldDefinition `json:"overrides,omitempty"`
+}
+
+type UpdateManifestNoteRequest struct {
+	Note string `json:"note"`
+}
+
+type ManifestApplyCommandResponse struct {
+	ID          string `json:"id"`
+	DownloadURL string `json:"downloadUrl"`
+	Command     string `json:"command"`
+	Heredoc     string `json:"heredoc"`
 }
 
 type ManifestRecord struct {
@@ -98,6 +459,82 @@ type ManifestRecord struct {
 	APIVersion string    `json:"apiVersion" bson:"apiVersion"`
 	Kind       string    `json:"kind" bson:"kind"`
 	YAML       string    `json:"yaml" bson:"yaml"`
+	Note       string    `json:"note,omitempty" bson:"note,omitempty"`
+	Tags       []string  `json:"tags,omitempty" bson:"tags,omitempty"`
 	CreatedAt  time.Time `json:"createdAt" bson:"createdAt"`
 	UpdatedAt  time.Time `json:"updatedAt" bson:"updatedAt"`
+	Warnings   []string  `json:"warnings,omitempty" bson:"-"`
+	// SourceRaw and Overrides can be large, so they are left out of list and
+	// get responses; RegenerateManifestResponse exposes the source.
+	SourceRaw string            `json:"-" bson:"sourceRaw,omitempty"`
+	Overrides []FieldDefinition `json:"-" bson:"overrides,omitempty"`
+}
+
+// RegenerateManifestRequest optionally supplies the CRD source for manifests
+// saved before it was stored alongside them.
+type RegenerateManifestRequest struct {
+	SourceRaw string `json:"sourceRaw,omitempty"`
+}
+
+// RegenerateManifestResponse carries freshly generated YAML for a stored
+// manifest. Manifest is the stored record, updated only when Saved is true.
+type RegenerateManifestResponse struct {
+	Template  TemplateDefinition `json:"template"`
+	YAML      string             `json:"yaml"`
+	SourceRaw string             `json:"sourceRaw,omitempty"`
+	Warnings  []string           `json:"warnings,omitempty"`
+	Saved     bool               `json:"saved"`
+	Manifest  ManifestRecord     `json:"manifest"`
+}
+
+// ManifestPage is one page of a manifest listing. Total counts every match,
+// not just the items on this page.
+type ManifestPage struct {
+	Items  []ManifestRecord `json:"items"`
+	Total  int64            `json:"total"`
+	Limit  int64            `json:"limit"`
+	Offset int64            `json:"offset"`
+}
+
+type BulkTagManifestsRequest struct {
+	IDs    []string `json:"ids,omitempty"`
+	Query  string   `json:"query,omitempty"`
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+type BulkTagManifestsResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+type ManifestDuplicateGroup struct {
+	Fingerprint string           `json:"fingerprint"`
+	Manifests   []ManifestRecord `json:"manifests"`
+}
+
+type CompactManifestsRequest struct {
+	// CollapseDuplicates removes older copies of manifests that are identical
+	// in every stored field apart from their ID and timestamps.
+	CollapseDuplicates bool `json:"collapseDuplicates,omitempty"`
+}
+
+type CompactManifestsResponse struct {
+	Mode       string   `json:"mode"`
+	Expired    int      `json:"expired"`
+	Duplicates int      `json:"duplicates"`
+	Trimmed    int      `json:"trimmed"`
+	Remaining  int64    `json:"remaining"`
+	RemovedIDs []string `json:"removedIds"`
+}
+
+type HealthResponse struct {
+	Status    string `json:"status"`
+	Templates int64  `json:"templates"`
+	Manifests int64  `json:"manifests"`
+}
+
+type ReadinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+	Mongo  string            `json:"mongo,omitempty"`
 }
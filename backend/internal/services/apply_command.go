@@ -0,0 +1,41 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// BuildApplyCommand returns kubectl snippets for a stored manifest: one that
+// pulls it from the download endpoint and one that embeds the YAML inline.
+func BuildApplyCommand(record models.ManifestRecord, downloadURL string) models.ManifestApplyCommandResponse {
+	body := record.YAML
+	if !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	return models.ManifestApplyCommandResponse{
+		ID:          record.ID,
+		DownloadURL: downloadURL,
+		Command:     "kubectl apply -f " + shellQuote(downloadURL),
+		Heredoc:     heredoc("kubectl apply -f -", body),
+	}
+}
+
+// shellQuote wraps value in single quotes for a POSIX shell, closing and
+// reopening the quotes around any single quote it contains.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// heredoc feeds body to command through a quoted heredoc, picking a
+// delimiter that no line of body matches so the YAML cannot end it early.
+func heredoc(command, body string) string {
+	delimiter := "EOF"
+	lines := strings.Split(body, "\n")
+	for n := 2; slices.Contains(lines, delimiter); n++ {
+		delimiter = fmt.Sprintf("EOF_%d", n)
+	}
+	return command + " <<'" + delimiter + "'\n" + body + delimiter
+}
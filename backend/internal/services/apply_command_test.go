@@ -0,0 +1,20 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+func TestBuildApplyCommandQuotesURLAndHeredocDelimiter(t *testing.T) {
+	record := models.ManifestRecord{ID: "m1", YAML: "kind: ConfigMap\ndata:\n  script: |\nEOF\n"}
+	result := BuildApplyCommand(record, "http://x'; rm -rf ~; '/download")
+
+	if want := `kubectl apply -f 'http://x'\''; rm -rf ~; '\''/download'`; result.Command != want {
+		t.Fatalf("expected the URL to stay one shell word, got %s", result.Command)
+	}
+	if !strings.HasPrefix(result.Heredoc, "kubectl apply -f - <<'EOF_2'\n") || !strings.HasSuffix(result.Heredoc, "\nEOF_2") {
+		t.Fatalf("expected a delimiter the YAML does not contain, got %s", result.Heredoc)
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BundleDocument is one non-empty document of a multi-document YAML bundle.
+// Index counts only non-empty documents, starting at 0.
+type BundleDocument struct {
+	Index int
+	Kind  string
+	Name  string
+	Raw   string
+}
+
+// SplitYAMLDocuments breaks a YAML bundle into its non-empty documents, each
+// re-encoded on its own so it can be handed to the single-document parsers.
+func SplitYAMLDocuments(raw string) ([]BundleDocument, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(raw))
+	docs := make([]BundleDocument, 0, 4)
+
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("YAML parse error: %w", err)
+		}
+		if len(node.Content) == 0 {
+			continue
+		}
+
+		var decoded map[string]any
+		if err := node.Decode(&decoded); err != nil || len(decoded) == 0 {
+			continue
+		}
+
+		var out bytes.Buffer
+		encoder := yaml.NewEncoder(&out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&node); err != nil {
+			return nil, fmt.Errorf("marshal YAML: %w", err)
+		}
+		_ = encoder.Close()
+
+		docs = append(docs, BundleDocument{
+			Index: len(docs),
+			Kind:  asString(decoded["kind"]),
+			Name:  asString(nested(decoded, "metadata", "name")),
+			Raw:   out.String(),
+		})
+	}
+
+	if len(docs) == 0 {
+		return nil, errors.New("no YAML documents found")
+	}
+	return docs, nil
+}
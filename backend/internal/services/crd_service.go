@@ -1,46 +1,255 @@
 package services
 
 import (
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	neturl "net/url"
 	"path"
+	"reflect"
 	"regexp"
 	"sort"
 	"strings"
 	"time"
+	"unsafe"
 
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
 	"github.com/aneeshchawla/kubetools/backend/internal/models"
 	"gopkg.in/yaml.v3"
 )
 
 var (
-	regexGroup      = regexp.MustCompile(`(?m)^\s*group:\s*([A-Za-z0-9.-]+)\s*$`)
-	regexVersion    = regexp.MustCompile(`(?m)^\s*version:\s*(v[0-9A-Za-z.-]+)\s*$`)
-	regexVersionRef = regexp.MustCompile(`(?m)^\s*-\s*name:\s*(v[0-9A-Za-z.-]+)\s*$`)
-	regexKind       = regexp.MustCompile(`(?s)names:\s*(?:\n[^\n]*){0,20}\n\s*kind:\s*([A-Za-z0-9]+)\s*`)
-	regexField      = regexp.MustCompile(`(?m)^\s{8,}([A-Za-z][A-Za-z0-9_-]*):\s*$`)
+	regexGroup          = regexp.MustCompile(`(?m)^\s*group:\s*([A-Za-z0-9.-]+)\s*$`)
+	regexVersion        = regexp.MustCompile(`(?m)^\s*version:\s*(v[0-9A-Za-z.-]+)\s*$`)
+	regexVersionRef     = regexp.MustCompile(`(?m)^\s*-\s*name:\s*(v[0-9A-Za-z.-]+)\s*$`)
+	regexKind           = regexp.MustCompile(`(?s)names:\s*(?:\n[^\n]*){0,20}\n\s*kind:\s*([A-Za-z0-9]+)\s*`)
+	regexField          = regexp.MustCompile(`(?m)^\s{8,}([A-Za-z][A-Za-z0-9_-]*):\s*$`)
+	regexInvalidIDChars = regexp.MustCompile(`[^a-z0-9-]`)
 )
 
-type CRDService struct{}
+// allFieldsLimit bounds the uncurated field walk used by AllFields.
+const allFieldsLimit = 5000
+
+// canonicalKinds maps lowercase spellings, plurals, and kubectl short names of
+// common built-in kinds to their canonical Kind.
+var canonicalKinds = func() map[string]string {
+	kinds := []string{
+		"ClusterRole", "ClusterRoleBinding", "ConfigMap", "CronJob", "CustomResourceDefinition",
+		"DaemonSet", "Deployment", "HorizontalPodAutoscaler", "Ingress", "Job", "Namespace",
+		"NetworkPolicy", "PersistentVolume", "PersistentVolumeClaim", "Pod", "PodDisruptionBudget",
+		"ReplicaSet", "Role", "RoleBinding", "Secret", "Service", "ServiceAccount", "StatefulSet",
+		"StorageClass", "VolumeSnapshot",
+	}
+	out := make(map[string]string, len(kinds)*2+24)
+	for _, kind := range kinds {
+		lower := strings.ToLower(kind)
+		out[lower] = kind
+		out[lower+"s"] = kind
+	}
+	out["ingresses"] = "Ingress"
+	out["horizontalpodautoscalers"] = "HorizontalPodAutoscaler"
+	out["storageclasses"] = "StorageClass"
+	out["deploy"] = "Deployment"
+	out["sts"] = "StatefulSet"
+	out["ds"] = "DaemonSet"
+	out["rs"] = "ReplicaSet"
+	out["po"] = "Pod"
+	out["svc"] = "Service"
+	out["cm"] = "ConfigMap"
+	out["ing"] = "Ingress"
+	out["cj"] = "CronJob"
+	out["pvc"] = "PersistentVolumeClaim"
+	out["pv"] = "PersistentVolume"
+	out["ns"] = "Namespace"
+	out["sa"] = "ServiceAccount"
+	out["netpol"] = "NetworkPolicy"
+	out["hpa"] = "HorizontalPodAutoscaler"
+	out["sc"] = "StorageClass"
+	out["pdb"] = "PodDisruptionBudget"
+	out["crd"] = "CustomResourceDefinition"
+	out["crds"] = "CustomResourceDefinition"
+	return out
+}()
+
+type CRDService struct {
+	limits     SchemaLimits
+	heuristics ServiceHeuristics
+	stripPaths []string
+}
+
+// SchemaLimits bounds how much of a CRD schema is turned into fields. Zero
+// values fall back to DefaultSchemaLimits.
+type SchemaLimits struct {
+	MaxDepth    int
+	MaxFields   int
+	MaxDefaults int
+}
 
-func NewCRDService() *CRDService {
-	return &CRDService{}
+// DefaultSchemaLimits are used for any limit that is not configured.
+var DefaultSchemaLimits = SchemaLimits{MaxDepth: 4, MaxFields: 420, MaxDefaults: 64}
+
+func (l SchemaLimits) withDefaults() SchemaLimits {
+	if l.MaxDepth <= 0 {
+		l.MaxDepth = DefaultSchemaLimits.MaxDepth
+	}
+	if l.MaxFields <= 0 {
+		l.MaxFields = DefaultSchemaLimits.MaxFields
+	}
+	if l.MaxDefaults <= 0 {
+		l.MaxDefaults = DefaultSchemaLimits.MaxDefaults
+	}
+	return l
+}
+
+// PlaceholderAPIVersion is used for generated resources when a CRD does not
+// declare both a group and a version.
+const PlaceholderAPIVersion = "example.io/v1"
+
+// PlaceholderAPIVersionWarning is reported by the parsers whenever they had
+// to fall back to PlaceholderAPIVersion.
+const PlaceholderAPIVersionWarning = "CRD lacks spec.group or a version; generated resources use the placeholder apiVersion " + PlaceholderAPIVersion + " and will not apply to a cluster."
+
+func NewCRDService(cfg config.Config) *CRDService {
+	return &CRDService{
+		limits: SchemaLimits{
+			MaxDepth:    cfg.CRDMaxDepth,
+			MaxFields:   cfg.CRDMaxFields,
+			MaxDefaults: cfg.CRDMaxDefaults,
+		}.withDefaults(),
+		heuristics: ServiceHeuristics{
+			Keywords: cfg.CRDServiceKeywords,
+			Markers:  cfg.CRDServiceMarkers,
+		}.withDefaults(DefaultServiceHeuristics),
+		stripPaths: stripPathsOrDefault(cfg.StripPaths),
+	}
 }
 
 func (s *CRDService) ParseCRD(raw string) (models.TemplateDefinition, error) {
-	raw = strings.TrimSpace(raw)
+	template, _, err := s.ParseCRDWithWarnings(raw)
+	return template, err
+}
+
+// ParseCRDWithWarnings parses like ParseCRD and also reports where the result
+// is lower quality than a full schema parse: the regex fallback was used, the
+// schema was missing, or placeholder fields had to be synthesized.
+func (s *CRDService) ParseCRDWithWarnings(raw string) (models.TemplateDefinition, []string, error) {
+	return s.ParseCRDWithOptions(raw, ParseOptions{})
+}
+
+// ParseCRDWithOptions parses like ParseCRDWithWarnings with the field
+// prioritization strategy taken from opts.
+func (s *CRDService) ParseCRDWithOptions(raw string, opts ParseOptions) (models.TemplateDefinition, []string, error) {
+	raw = strings.TrimSpace(normalizeLineEndings(raw))
 	if raw == "" {
-		return models.TemplateDefinition{}, errors.New("CRD payload is empty")
+		return models.TemplateDefinition{}, nil, errors.New("CRD payload is empty")
 	}
 
-	if structured, ok := parseStructuredYAML(raw); ok {
-		return structured, nil
+	opts.limits = s.limits
+	opts.ServiceHeuristics = opts.ServiceHeuristics.withDefaults(s.heuristics)
+	if len(opts.StripPaths) == 0 {
+		opts.StripPaths = s.stripPaths
+	}
+	warnings := make([]string, 0)
+	if structured, ok := parseStructuredYAML(raw, opts, &warnings); ok {
+		return structured, warnings, nil
 	}
 
-	return parseWithRegexFallback(raw), nil
+	warnings = append(warnings, "Input could not be parsed as structured YAML; fell back to regex parser, so field inference may be incomplete.")
+	return parseWithRegexFallback(raw, opts.OmitInferredDescriptions, &warnings), warnings, nil
+}
+
+// AllFields returns the complete flattened field list for a CRD schema,
+// without the curation ParseCRD applies to pick default and optional fields.
+// Non-CRD input falls back to the union of the parsed template's fields.
+func (s *CRDService) AllFields(raw string) ([]models.FieldDefinition, error) {
+	template, err := s.ParseCRD(raw)
+	if err != nil {
+		return nil, err
+	}
+	metadataFields := []models.FieldDefinition{
+		{Path: "metadata.name", Description: "Name for this resource."},
+		{Path: "metadata.namespace", Description: "Namespace for this resource."},
+	}
+
+	docs, err := decodeYAMLDocuments(strings.TrimSpace(raw))
+	if err == nil {
+		if root, ok := selectPrimaryResourceDoc(docs); ok && strings.EqualFold(asString(root["kind"]), "CustomResourceDefinition") {
+			if specSchema, _ := selectSpecSchema(root); specSchema != nil {
+				properties, _ := specSchema["properties"].(map[string]any)
+				collected := make([]schemaFieldCandidate, 0, 128)
+				collectSchemaFields("spec", properties, parseRequiredSet(specSchema["required"]), 0, s.limits.withDefaults().MaxDepth, allFieldsLimit, make(schemaAncestors), &collected)
+				collected = dedupeCandidates(collected)
+
+				fields := make([]models.FieldDefinition, 0, len(metadataFields)+len(collected))
+				fields = append(fields, metadataFields...)
+				for _, candidate := range collected {
+					fields = append(fields, candidate.Field)
+				}
+				return fields, nil
+			}
+		}
+	}
+
+	fields := append(metadataFields, template.DefaultFields...)
+	return dedupeFields(append(fields, template.OptionalFields...)), nil
+}
+
+// SkippedFields returns the schema fields that AllFields finds but that the
+// curated template dropped, for example because the candidate walk hit its
+// field cap. The result is empty when nothing was left out.
+func (s *CRDService) SkippedFields(raw string, template models.TemplateDefinition) ([]models.FieldDefinition, error) {
+	allFields, err := s.AllFields(raw)
+	if err != nil {
+		return nil, err
+	}
+	kept := make(map[string]struct{}, len(template.DefaultFields)+len(template.OptionalFields))
+	for _, field := range append(append([]models.FieldDefinition(nil), template.DefaultFields...), template.OptionalFields...) {
+		kept[field.Path] = struct{}{}
+	}
+
+	skipped := make([]models.FieldDefinition, 0)
+	for _, field := range allFields {
+		if _, ok := kept[field.Path]; !ok {
+			skipped = append(skipped, field)
+		}
+	}
+	return skipped, nil
+}
+
+type apiKind struct {
+	APIVersion string
+	Kind       string
+}
+
+// deprecatedAPIVersions maps removed or deprecated apiVersion/kind pairs to
+// the apiVersion that replaces them.
+var deprecatedAPIVersions = map[apiKind]string{
+	{"extensions/v1beta1", "Deployment"}:                                       "apps/v1",
+	{"extensions/v1beta1", "DaemonSet"}:                                        "apps/v1",
+	{"extensions/v1beta1", "ReplicaSet"}:                                       "apps/v1",
+	{"extensions/v1beta1", "Ingress"}:                                          "networking.k8s.io/v1",
+	{"extensions/v1beta1", "NetworkPolicy"}:                                    "networking.k8s.io/v1",
+	{"apps/v1beta1", "Deployment"}:                                             "apps/v1",
+	{"apps/v1beta1", "StatefulSet"}:                                            "apps/v1",
+	{"apps/v1beta2", "Deployment"}:                                             "apps/v1",
+	{"apps/v1beta2", "StatefulSet"}:                                            "apps/v1",
+	{"apps/v1beta2", "DaemonSet"}:                                              "apps/v1",
+	{"apps/v1beta2", "ReplicaSet"}:                                             "apps/v1",
+	{"networking.k8s.io/v1beta1", "Ingress"}:                                   "networking.k8s.io/v1",
+	{"networking.k8s.io/v1beta1", "IngressClass"}:                              "networking.k8s.io/v1",
+	{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition"}:               "apiextensions.k8s.io/v1",
+	{"batch/v1beta1", "CronJob"}:                                               "batch/v1",
+	{"policy/v1beta1", "PodDisruptionBudget"}:                                  "policy/v1",
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler"}:                         "autoscaling/v2",
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler"}:                         "autoscaling/v2",
+	{"rbac.authorization.k8s.io/v1beta1", "Role"}:                              "rbac.authorization.k8s.io/v1",
+	{"rbac.authorization.k8s.io/v1beta1", "RoleBinding"}:                       "rbac.authorization.k8s.io/v1",
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRole"}:                       "rbac.authorization.k8s.io/v1",
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding"}:                "rbac.authorization.k8s.io/v1",
+	{"admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration"}: "admissionregistration.k8s.io/v1",
+	{"admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration"}:   "admissionregistration.k8s.io/v1",
 }
 
 func (s *CRDService) ValidateCRD(raw string) models.ValidateCRDResponse {
@@ -49,7 +258,7 @@ func (s *CRDService) ValidateCRD(raw string) models.ValidateCRDResponse {
 		Warnings: make([]string, 0),
 	}
 
-	raw = strings.TrimSpace(raw)
+	raw = strings.TrimSpace(normalizeLineEndings(raw))
 	if raw == "" {
 		result.Errors = append(result.Errors, "CRD payload is empty.")
 		return result
@@ -75,6 +284,12 @@ func (s *CRDService) ValidateCRD(raw string) models.ValidateCRDResponse {
 	if result.APIVersion == "" {
 		result.Errors = append(result.Errors, "Missing required top-level field: apiVersion")
 	}
+	if suggestion, ok := suggestKind(result.Kind); ok {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Unrecognized kind %q: did you mean %s?", result.Kind, suggestion))
+	}
+	if replacement, ok := deprecatedAPIVersions[apiKind{result.APIVersion, result.Kind}]; ok {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("apiVersion %s for %s is deprecated; use %s instead.", result.APIVersion, result.Kind, replacement))
+	}
 
 	if strings.EqualFold(result.Kind, "CustomResourceDefinition") {
 		specMap, ok := root["spec"].(map[string]any)
@@ -107,16 +322,38 @@ func (s *CRDService) ValidateCRD(raw string) models.ValidateCRDResponse {
 			if !hasCRDSchema(root) {
 				result.Warnings = append(result.Warnings, "CRD schema not found. Add openAPIV3Schema for richer field guidance.")
 			}
+			result.Warnings = append(result.Warnings, printerColumnWarnings(specMap)...)
 		}
 	} else if result.Kind != "" {
 		result.Warnings = append(result.Warnings, "Input kind is not CustomResourceDefinition. It will still be accepted.")
 	}
+	for _, doc := range docs {
+		result.Warnings = append(result.Warnings, scanDocumentSecrets(doc)...)
+	}
+	if warning, ok := mixedIndentWarning(raw); ok {
+		result.Warnings = append(result.Warnings, warning)
+	}
 
 	result.Valid = len(result.Errors) == 0
 	return result
 }
 
-func (s *CRDService) FetchCRDFromURL(rawURL string) (string, string, error) {
+// StrictValidation folds warnings into errors so that any warning makes the
+// result invalid. CI pipelines use it to hold manifests to a higher bar.
+func StrictValidation(result models.ValidateCRDResponse) models.ValidateCRDResponse {
+	if len(result.Warnings) == 0 {
+		return result
+	}
+	result.Errors = append(result.Errors, result.Warnings...)
+	result.Warnings = make([]string, 0)
+	result.Valid = false
+	return result
+}
+
+// FetchCRDFromURL downloads a CRD document. When token is set and the
+// normalized URL points at GitHub, it is sent as a bearer token so private
+// repositories can be read; other hosts never see it.
+func (s *CRDService) FetchCRDFromURL(rawURL string, token string) (string, string, error) {
 	trimmed := strings.TrimSpace(rawURL)
 	if trimmed == "" {
 		return "", "", errors.New("url is required")
@@ -134,13 +371,28 @@ func (s *CRDService) FetchCRDFromURL(rawURL string) (string, string, error) {
 	}
 
 	normalized := normalizeSourceURL(parsed)
-	client := &http.Client{Timeout: 12 * time.Second}
+	client := &http.Client{
+		Timeout: 12 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if !acceptsGitHubToken(req.URL) {
+				req.Header.Del("Authorization")
+			}
+			return nil
+		},
+	}
 	req, err := http.NewRequest(http.MethodGet, normalized, nil)
 	if err != nil {
 		return "", "", fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("User-Agent", "kubebuilder-crd-import/1.0")
 	req.Header.Set("Accept", "text/plain, application/yaml, application/x-yaml, */*")
+	req.Header.Set("Accept-Encoding", "gzip")
+	if token = strings.TrimSpace(token); token != "" && acceptsGitHubToken(req.URL) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -152,8 +404,14 @@ func (s *CRDService) FetchCRDFromURL(rawURL string) (string, string, error) {
 		return "", "", fmt.Errorf("fetch failed with status %d", resp.StatusCode)
 	}
 
+	reader, err := DecodedBody(resp)
+	if err != nil {
+		return "", "", fmt.Errorf("read response: %w", err)
+	}
+	defer reader.Close()
+
 	const maxBytes = 2 * 1024 * 1024
-	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	body, err := io.ReadAll(io.LimitReader(reader, maxBytes+1))
 	if err != nil {
 		return "", "", fmt.Errorf("read response: %w", err)
 	}
@@ -169,6 +427,50 @@ func (s *CRDService) FetchCRDFromURL(rawURL string) (string, string, error) {
 	return normalized, contents, nil
 }
 
+// DecodedBody returns the response body, gunzipping it when the server sent
+// Content-Encoding: gzip that the transport left in place. Size limits should
+// be applied to the returned reader so they count decompressed bytes.
+// Closing the result also closes resp.Body.
+func DecodedBody(resp *http.Response) (io.ReadCloser, error) {
+	if resp.Uncompressed || !strings.EqualFold(strings.TrimSpace(resp.Header.Get("Content-Encoding")), "gzip") {
+		return resp.Body, nil
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("decompress gzip body: %w", err)
+	}
+	return gzipBody{Reader: zr, body: resp.Body}, nil
+}
+
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (g gzipBody) Close() error {
+	zerr := g.Reader.Close()
+	if err := g.body.Close(); err != nil {
+		return err
+	}
+	return zerr
+}
+
+// acceptsGitHubToken reports whether a GitHub token may be sent to u: only
+// GitHub's content hosts, and only over https so it never travels in
+// cleartext.
+func acceptsGitHubToken(u *neturl.URL) bool {
+	return u.Scheme == "https" && isGitHubContentHost(u.Hostname())
+}
+
+func isGitHubContentHost(host string) bool {
+	switch strings.ToLower(host) {
+	case "raw.githubusercontent.com", "api.github.com":
+		return true
+	default:
+		return false
+	}
+}
+
 func normalizeSourceURL(parsed *neturl.URL) string {
 	host := strings.ToLower(parsed.Hostname())
 	if host == "github.com" {
@@ -184,7 +486,7 @@ func normalizeSourceURL(parsed *neturl.URL) string {
 	return parsed.String()
 }
 
-func parseStructuredYAML(raw string) (models.TemplateDefinition, bool) {
+func parseStructuredYAML(raw string, opts ParseOptions, warnings *[]string) (models.TemplateDefinition, bool) {
 	docs, err := decodeYAMLDocuments(raw)
 	if err != nil {
 		return models.TemplateDefinition{}, false
@@ -196,39 +498,51 @@ func parseStructuredYAML(raw string) (models.TemplateDefinition, bool) {
 
 	topKind := asString(root["kind"])
 	if strings.EqualFold(topKind, "CustomResourceDefinition") {
-		return parseCRDDocument(root), true
+		var schemaLines map[string]int
+		if opts.Prioritization == PrioritizeSchemaOrder {
+			_, version := selectSpecSchema(root)
+			schemaLines = schemaFieldLines(raw, version)
+		}
+		return parseCRDDocument(root, opts, schemaLines, warnings), true
 	}
 
 	if topKind != "" {
-		return parseArbitraryResource(root), true
+		return parseArbitraryResource(root, opts), true
 	}
 
 	return models.TemplateDefinition{}, false
 }
 
-func parseCRDDocument(root map[string]any) models.TemplateDefinition {
+func parseCRDDocument(root map[string]any, opts ParseOptions, schemaLines map[string]int, warnings *[]string) models.TemplateDefinition {
 	kind := asString(nested(root, "spec", "names", "kind"))
 	if kind == "" {
 		kind = "CustomResource"
 	}
 
 	group := asString(nested(root, "spec", "group"))
-	version := asString(nested(root, "spec", "version"))
+	version := strings.TrimSpace(asString(nested(root, "spec", "version")))
 
-	defaultFields, optionalFields, schemaVersion := extractCRDSpecFields(root)
+	defaultFields, optionalFields, schemaVersion := extractCRDSpecFields(root, opts, schemaLines)
 	if version == "" {
 		version = schemaVersion
 	}
 	if version == "" {
-		version = firstVersion(nested(root, "spec", "versions"))
+		versions, _ := nested(root, "spec", "versions").([]any)
+		version = preferredVersionName(versions)
 	}
 
-	apiVersion := "example.io/v1"
+	apiVersion := PlaceholderAPIVersion
 	if group != "" && version != "" {
 		apiVersion = fmt.Sprintf("%s/%s", group, version)
+	} else {
+		*warnings = append(*warnings, PlaceholderAPIVersionWarning)
 	}
 
+	if !hasCRDSchema(root) {
+		*warnings = append(*warnings, "CRD has no openAPIV3Schema; fields could not be inferred from a schema.")
+	}
 	if len(defaultFields) == 0 {
+		*warnings = append(*warnings, "No spec fields found; synthesized placeholder field spec.example.")
 		defaultFields = []models.FieldDefinition{
 			{
 				Path:        "spec.example",
@@ -236,9 +550,11 @@ func parseCRDDocument(root map[string]any) models.TemplateDefinition {
 			},
 		}
 	}
+	var specRules []string
 	if specSchema, _ := selectSpecSchema(root); specSchema != nil {
-		serviceSeeds := extractServiceSeedFields(specSchema)
+		serviceSeeds := extractServiceSeedFields(specSchema, opts.ServiceHeuristics.withDefaults(DefaultServiceHeuristics))
 		defaultFields = dedupeFields(append(serviceSeeds, defaultFields...))
+		specRules = schemaRuleMessages(specSchema)
 	}
 
 	optionalFields = dedupeFields(append(optionalFields,
@@ -256,15 +572,51 @@ func parseCRDDocument(root map[string]any) models.TemplateDefinition {
 			{Path: "metadata.name", Value: strings.ToLower(kind) + "-sample", Description: "Name for this custom resource."},
 			{Path: "metadata.namespace", Value: "default", Description: "Namespace for this custom resource."},
 		}, defaultFields...),
-		OptionalFields: optionalFields,
+		OptionalFields:    optionalFields,
+		SelectedVersion:   version,
+		AvailableVersions: crdVersionInfos(root),
+		Rules:             specRules,
+	}
+}
+
+// crdVersionInfos lists the versions a CRD declares. A legacy CRD with a
+// single spec.version is reported as one served storage version.
+func crdVersionInfos(root map[string]any) []models.VersionInfo {
+	versions, _ := nested(root, "spec", "versions").([]any)
+	infos := make([]models.VersionInfo, 0, len(versions))
+	for _, entry := range versions {
+		versionMap, _ := entry.(map[string]any)
+		name := asString(versionMap["name"])
+		if name == "" {
+			continue
+		}
+		infos = append(infos, models.VersionInfo{
+			Name:      name,
+			Served:    asBool(versionMap["served"]),
+			Storage:   asBool(versionMap["storage"]),
+			HasSchema: nested(versionMap, "schema", "openAPIV3Schema") != nil || nested(root, "spec", "validation", "openAPIV3Schema") != nil,
+		})
+	}
+	if len(infos) == 0 {
+		if name := asString(nested(root, "spec", "version")); name != "" {
+			infos = append(infos, models.VersionInfo{
+				Name:      name,
+				Served:    true,
+				Storage:   true,
+				HasSchema: nested(root, "spec", "validation", "openAPIV3Schema") != nil,
+			})
+		}
 	}
+	return infos
 }
 
-func parseArbitraryResource(root map[string]any) models.TemplateDefinition {
+func parseArbitraryResource(root map[string]any, opts ParseOptions) models.TemplateDefinition {
+	stripMap(root, nil, compileStripPolicy(stripPathsOrDefault(opts.StripPaths)))
+
 	kind := asString(root["kind"])
 	apiVersion := asString(root["apiVersion"])
 	if apiVersion == "" {
-		apiVersion = "example.io/v1"
+		apiVersion = PlaceholderAPIVersion
 	}
 
 	name := asString(nested(root, "metadata", "name"))
@@ -294,9 +646,9 @@ func parseArbitraryResource(root map[string]any) models.TemplateDefinition {
 				break
 			}
 			value := specMap[key]
-			field := models.FieldDefinition{
-				Path:        "spec." + key,
-				Description: fmt.Sprintf("Inferred from resource spec field '%s'.", key),
+			field := models.FieldDefinition{Path: "spec." + key}
+			if !opts.OmitInferredDescriptions {
+				field.Description = fmt.Sprintf("Inferred from resource spec field '%s'.", key)
 			}
 			switch typed := value.(type) {
 			case string:
@@ -311,6 +663,7 @@ func parseArbitraryResource(root map[string]any) models.TemplateDefinition {
 			fields = append(fields, field)
 		}
 	}
+	fields = append(fields, requiredKindSeeds(apiVersion, kind, name, specMap)...)
 
 	return models.TemplateDefinition{
 		ID:             normalizeID("parsed-" + kind),
@@ -323,14 +676,77 @@ func parseArbitraryResource(root map[string]any) models.TemplateDefinition {
 	}
 }
 
+// builtinKindSeeds lists the fields the API server requires for common
+// built-in kinds, so a template parsed from a bare object is valid as-is.
+// Label and selector seeds ending in ".app" take the resource name.
+var builtinKindSeeds = map[string][]models.FieldDefinition{
+	"Deployment":  podControllerSeeds(),
+	"ReplicaSet":  podControllerSeeds(),
+	"DaemonSet":   podControllerSeeds(),
+	"StatefulSet": append(podControllerSeeds(), models.FieldDefinition{Path: "spec.serviceName", Value: "headless", Description: "Headless service governing the pods' network identity."}),
+	"Job": {
+		{Path: "spec.template.spec.restartPolicy", Value: "Never", Description: "Job pods must not restart in place."},
+		{Path: "spec.template.spec.containers[0].name", Value: "job", Description: "Container name."},
+		{Path: "spec.template.spec.containers[0].image", Value: "busybox:1.36", Description: "Container image."},
+	},
+	"CronJob": {
+		{Path: "spec.schedule", Value: "0 * * * *", Description: "Cron schedule for the job."},
+		{Path: "spec.jobTemplate.spec.template.spec.restartPolicy", Value: "OnFailure", Description: "Job pods must not restart in place."},
+		{Path: "spec.jobTemplate.spec.template.spec.containers[0].name", Value: "job", Description: "Container name."},
+		{Path: "spec.jobTemplate.spec.template.spec.containers[0].image", Value: "busybox:1.36", Description: "Container image."},
+	},
+	"Service": {
+		{Path: "spec.selector.app", Description: "Labels of the pods receiving traffic."},
+		{Path: "spec.ports[0].port", Value: "80", Type: "number", Description: "Port exposed by the service."},
+	},
+}
+
+func podControllerSeeds() []models.FieldDefinition {
+	return []models.FieldDefinition{
+		{Path: "spec.selector.matchLabels.app", Description: "Pod label selector."},
+		{Path: "spec.template.metadata.labels.app", Description: "Pod template labels; must match the selector."},
+		{Path: "spec.template.spec.containers[0].name", Value: "app", Description: "Container name."},
+		{Path: "spec.template.spec.containers[0].image", Value: "nginx:1.27", Description: "Container image."},
+	}
+}
+
+// requiredKindSeeds returns the required seeds for a built-in kind, skipping
+// any whose top-level spec key the object already sets. Kinds from a dotted
+// API group are custom resources and get no seeds even if the name matches.
+func requiredKindSeeds(apiVersion, kind, name string, spec map[string]any) []models.FieldDefinition {
+	group := ""
+	if slash := strings.Index(apiVersion, "/"); slash >= 0 {
+		group = apiVersion[:slash]
+	}
+	if strings.Contains(group, ".") {
+		return nil
+	}
+
+	seeds := make([]models.FieldDefinition, 0, len(builtinKindSeeds[kind]))
+	for _, seed := range builtinKindSeeds[kind] {
+		if _, exists := spec[topLevelSpecKey(seed.Path)]; exists {
+			continue
+		}
+		if strings.HasSuffix(seed.Path, ".app") {
+			seed.Value = name
+		}
+		seed.Required = true
+		seeds = append(seeds, seed)
+	}
+	return seeds
+}
+
 type schemaFieldCandidate struct {
 	Field      models.FieldDefinition
 	Required   bool
 	Depth      int
 	HasDefault bool
+	// InferredDescription marks a placeholder description, which a real
+	// one found for the same path during dedupe replaces.
+	InferredDescription bool
 }
 
-func extractCRDSpecFields(root map[string]any) ([]models.FieldDefinition, []models.FieldDefinition, string) {
+func extractCRDSpecFields(root map[string]any, opts ParseOptions, schemaLines map[string]int) ([]models.FieldDefinition, []models.FieldDefinition, string) {
 	specSchema, schemaVersion := selectSpecSchema(root)
 	if specSchema == nil {
 		return nil, nil, schemaVersion
@@ -342,35 +758,29 @@ func extractCRDSpecFields(root map[string]any) ([]models.FieldDefinition, []mode
 	}
 
 	requiredSet := parseRequiredSet(specSchema["required"])
-	collected := make([]schemaFieldCandidate, 0, 96)
-	collectSchemaFields("spec", properties, requiredSet, 0, 4, 420, &collected)
+	limits := opts.limits.withDefaults()
+	collected := make([]schemaFieldCandidate, 0, limits.MaxFields)
+	collectSchemaFields("spec", properties, requiredSet, 0, limits.MaxDepth, limits.MaxFields, make(schemaAncestors), &collected)
 
 	if len(collected) == 0 {
 		return nil, nil, schemaVersion
 	}
 
 	collected = dedupeCandidates(collected)
-	sort.SliceStable(collected, func(i, j int) bool {
-		left := collected[i]
-		right := collected[j]
-
-		if left.Required != right.Required {
-			return left.Required
-		}
-		if left.HasDefault != right.HasDefault {
-			return left.HasDefault
-		}
-		if left.Depth != right.Depth {
-			return left.Depth < right.Depth
+	if opts.OmitInferredDescriptions {
+		for i := range collected {
+			if collected[i].InferredDescription {
+				collected[i].Field.Description = ""
+			}
 		}
-		return left.Field.Path < right.Field.Path
-	})
+	}
+	collected = sortCandidates(collected, opts.Prioritization, schemaLines)
 
 	defaults := make([]models.FieldDefinition, 0, 16)
 	optionals := make([]models.FieldDefinition, 0, len(collected))
 	seenDefault := make(map[string]struct{}, 16)
 
-	const maxDefaultFields = 64
+	maxDefaultFields := limits.MaxDefaults
 	for _, candidate := range collected {
 		shouldDefault := candidate.Required || candidate.HasDefault
 		if shouldDefault && len(defaults) < maxDefaultFields {
@@ -400,11 +810,25 @@ func extractCRDSpecFields(root map[string]any) ([]models.FieldDefinition, []mode
 		finalOptionals = append(finalOptionals, field)
 	}
 
+	// Required and defaulted fields are still guaranteed a default slot, but
+	// for the other strategies they shouldn't jump ahead of the chosen order.
+	if strategy := opts.Prioritization; strategy != "" && strategy != PrioritizeRequiredFirst {
+		rank := make(map[string]int, len(collected))
+		for i, candidate := range collected {
+			rank[candidate.Field.Path] = i
+		}
+		sort.SliceStable(defaults, func(i, j int) bool { return rank[defaults[i].Path] < rank[defaults[j].Path] })
+	}
+
 	defaults = ensureTopLevelSpecCoverage(specSchema, defaults, collected)
 
 	return defaults, finalOptionals, schemaVersion
 }
 
+// schemaAncestors holds the properties maps on the path collectSchemaFields
+// is currently walking, keyed by map identity.
+type schemaAncestors map[unsafe.Pointer]bool
+
 func collectSchemaFields(
 	prefix string,
 	properties map[string]any,
@@ -412,12 +836,24 @@ func collectSchemaFields(
 	depth int,
 	maxDepth int,
 	limit int,
+	ancestors schemaAncestors,
 	out *[]schemaFieldCandidate,
 ) {
 	if len(*out) >= limit {
 		return
 	}
 
+	// A properties map that is already on the current ancestor chain is a
+	// self-referential schema; stop there instead of re-expanding it down to
+	// maxDepth. Only the chain counts, so sibling paths that share a
+	// subschema are each still collected.
+	nodeKey := reflect.ValueOf(properties).UnsafePointer()
+	if ancestors[nodeKey] {
+		return
+	}
+	ancestors[nodeKey] = true
+	defer delete(ancestors, nodeKey)
+
 	keys := make([]string, 0, len(properties))
 	for key := range properties {
 		keys = append(keys, key)
@@ -436,8 +872,9 @@ func collectSchemaFields(
 
 		path := prefix + "." + key
 		nodeType := asString(node["type"])
-		description := asString(node["description"])
-		if description == "" {
+		description := strings.TrimSpace(asString(node["description"]))
+		inferred := description == ""
+		if inferred {
 			description = fmt.Sprintf("Inferred from CRD schema field '%s'.", key)
 		}
 
@@ -455,22 +892,41 @@ func collectSchemaFields(
 		if nodeType == "array" {
 			items, _ := node["items"].(map[string]any)
 			itemProps, _ := items["properties"].(map[string]any)
+			minItems := requiredMinItems(node, isRequired)
 			if len(itemProps) > 0 && depth <= maxDepth && !hasPreservedUnknownFields(items) {
 				itemRequired := parseRequiredSet(items["required"])
-				collectSchemaFields(path+"[0]", itemProps, itemRequired, depth, maxDepth, limit, out)
+				start := len(*out)
+				collectSchemaFields(path+"[0]", itemProps, itemRequired, depth, maxDepth, limit, ancestors, out)
+				if minItems > 0 {
+					markArrayMinItems((*out)[start:], path+"[0]", minItems)
+				}
 				continue
 			}
 
+			// A default on the array itself is a list; seed the [0] field
+			// with its first element rather than the formatted list.
+			if list, ok := node["default"].([]any); ok {
+				defaultValue, hasDefault = "", len(list) > 0
+				if hasDefault {
+					defaultValue = formatDefaultValue(list[0])
+				}
+			}
+
 			candidate := schemaFieldCandidate{
 				Field: models.FieldDefinition{
 					Path:        path + "[0]",
 					Type:        fieldType,
 					Value:       defaultValue,
 					Description: description,
+					Enum:        schemaEnumValues(items),
+					Constraints: withMinItems(schemaConstraints(items), minItems),
+					Immutable:   isImmutableSchema(node),
+					Rules:       schemaRuleMessages(node),
 				},
-				Required:   isRequired,
-				Depth:      depth,
-				HasDefault: hasDefault,
+				Required:            isRequired,
+				Depth:               depth,
+				HasDefault:          hasDefault,
+				InferredDescription: inferred,
 			}
 			*out = append(*out, candidate)
 			continue
@@ -480,7 +936,7 @@ func collectSchemaFields(
 		if hasNested && len(nestedProps) > 0 {
 			if depth < maxDepth && !hasPreservedUnknownFields(node) {
 				nestedRequired := parseRequiredSet(node["required"])
-				collectSchemaFields(path, nestedProps, nestedRequired, depth+1, maxDepth, limit, out)
+				collectSchemaFields(path, nestedProps, nestedRequired, depth+1, maxDepth, limit, ancestors, out)
 			}
 			continue
 		}
@@ -488,10 +944,11 @@ func collectSchemaFields(
 		if nodeType == "object" || (nodeType == "" && node["additionalProperties"] != nil) {
 			if field, ok := mapSeedField(path, node, description); ok {
 				*out = append(*out, schemaFieldCandidate{
-					Field:      field,
-					Required:   isRequired,
-					Depth:      depth,
-					HasDefault: hasDefault,
+					Field:               field,
+					Required:            isRequired,
+					Depth:               depth,
+					HasDefault:          hasDefault,
+					InferredDescription: inferred,
 				})
 			}
 			continue
@@ -503,10 +960,15 @@ func collectSchemaFields(
 				Type:        fieldType,
 				Value:       defaultValue,
 				Description: description,
+				Enum:        schemaEnumValues(node),
+				Constraints: schemaConstraints(node),
+				Immutable:   isImmutableSchema(node),
+				Rules:       schemaRuleMessages(node),
 			},
-			Required:   isRequired,
-			Depth:      depth,
-			HasDefault: hasDefault,
+			Required:            isRequired,
+			Depth:               depth,
+			HasDefault:          hasDefault,
+			InferredDescription: inferred,
 		})
 	}
 }
@@ -525,11 +987,41 @@ func selectSpecSchema(root map[string]any) (map[string]any, string) {
 		}
 	}
 
+	// v1beta1 CRDs share one spec.validation schema across versions. The
+	// deprecated spec.version names the first version when set; otherwise
+	// the schema applies to the storage version like any other.
 	validation, _ := specMap["validation"].(map[string]any)
 	openSchema, _ := validation["openAPIV3Schema"].(map[string]any)
 	properties, _ := openSchema["properties"].(map[string]any)
 	specSchema, _ := properties["spec"].(map[string]any)
-	return specSchema, asString(specMap["version"])
+	version := strings.TrimSpace(asString(specMap["version"]))
+	if version == "" {
+		version = preferredVersionName(versions)
+	}
+	return specSchema, version
+}
+
+// preferredVersionName picks the storage version, then the first served
+// one, then the first named one.
+func preferredVersionName(versions []any) string {
+	first, served := "", ""
+	for _, entry := range versions {
+		versionMap, _ := entry.(map[string]any)
+		name := asString(versionMap["name"])
+		if name == "" {
+			continue
+		}
+		if asBool(versionMap["storage"]) {
+			return name
+		}
+		if served == "" && asBool(versionMap["served"]) {
+			served = name
+		}
+		if first == "" {
+			first = name
+		}
+	}
+	return fallback(served, first)
 }
 
 func selectVersionSchema(versions []any) (map[string]any, string) {
@@ -596,6 +1088,8 @@ func parseRequiredSet(value any) map[string]bool {
 	return required
 }
 
+// inferSchemaDefault picks a seed value for a field: its default, then its
+// example, then its first enum value.
 func inferSchemaDefault(node map[string]any) (string, bool) {
 	if node == nil {
 		return "", false
@@ -604,6 +1098,9 @@ func inferSchemaDefault(node map[string]any) (string, bool) {
 		text := formatDefaultValue(value)
 		return text, true
 	}
+	if value, exists := node["example"]; exists && value != nil {
+		return formatDefaultValue(value), true
+	}
 
 	enumValues, _ := node["enum"].([]any)
 	if len(enumValues) > 0 {
@@ -614,6 +1111,112 @@ func inferSchemaDefault(node map[string]any) (string, bool) {
 	return "", false
 }
 
+func schemaEnumValues(node map[string]any) []string {
+	enumValues, _ := node["enum"].([]any)
+	if len(enumValues) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(enumValues))
+	for _, value := range enumValues {
+		out = append(out, formatDefaultValue(value))
+	}
+	return out
+}
+
+// schemaConstraints returns the numeric bounds and string pattern/format a
+// schema node declares, or nil when it declares none.
+func schemaConstraints(node map[string]any) *models.FieldConstraints {
+	constraints := models.FieldConstraints{
+		Pattern: asString(node["pattern"]),
+		Format:  asString(node["format"]),
+	}
+	if minimum, ok := asNumber(node["minimum"]); ok {
+		constraints.Minimum = &minimum
+	}
+	if maximum, ok := asNumber(node["maximum"]); ok {
+		constraints.Maximum = &maximum
+	}
+	if constraints == (models.FieldConstraints{}) {
+		return nil
+	}
+	return &constraints
+}
+
+// requiredMinItems returns the minItems of a required array node, or 0 when
+// the array is optional or allows being empty.
+func requiredMinItems(node map[string]any, required bool) int {
+	if !required {
+		return 0
+	}
+	minItems, ok := asNumber(node["minItems"])
+	if !ok || minItems < 1 {
+		return 0
+	}
+	return int(minItems)
+}
+
+func withMinItems(constraints *models.FieldConstraints, minItems int) *models.FieldConstraints {
+	if minItems == 0 {
+		return constraints
+	}
+	out := models.FieldConstraints{}
+	if constraints != nil {
+		out = *constraints
+	}
+	out.MinItems = minItems
+	return &out
+}
+
+// markArrayMinItems records minItems on the fields collected for one element
+// of an array of objects. Fields of nested arrays keep their own bounds.
+func markArrayMinItems(candidates []schemaFieldCandidate, elementPath string, minItems int) {
+	for i := range candidates {
+		rest, ok := strings.CutPrefix(candidates[i].Field.Path, elementPath)
+		if !ok || strings.Contains(rest, "[") {
+			continue
+		}
+		candidates[i].Field.Constraints = withMinItems(candidates[i].Field.Constraints, minItems)
+	}
+}
+
+// immutableRuleRegex matches the common CEL immutability idioms
+// "self == oldSelf" and "oldSelf == self", allowing for whitespace.
+var immutableRuleRegex = regexp.MustCompile(`^\s*(self\s*==\s*oldSelf|oldSelf\s*==\s*self)\s*$`)
+
+// isImmutableSchema reports whether a schema node's x-kubernetes-validations
+// pin the field to its previous value. This is a best-effort check: only the
+// plain equality rule is recognized, not transition rules that allow some
+// changes.
+func isImmutableSchema(node map[string]any) bool {
+	rules, _ := node["x-kubernetes-validations"].([]any)
+	for _, entry := range rules {
+		rule, _ := entry.(map[string]any)
+		if immutableRuleRegex.MatchString(asString(rule["rule"])) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaRuleMessages returns the message of each x-kubernetes-validations
+// rule on a schema node, falling back to the CEL expression when a rule has no
+// message.
+func schemaRuleMessages(node map[string]any) []string {
+	rules, _ := node["x-kubernetes-validations"].([]any)
+	var out []string
+	for _, entry := range rules {
+		rule, _ := entry.(map[string]any)
+		message := strings.TrimSpace(asString(rule["message"]))
+		if message == "" {
+			message = strings.TrimSpace(asString(rule["rule"]))
+		}
+		if message != "" {
+			out = append(out, message)
+		}
+	}
+	return out
+}
+
 func formatDefaultValue(value any) string {
 	switch typed := value.(type) {
 	case string:
@@ -643,16 +1246,17 @@ func hasPreservedUnknownFields(node map[string]any) bool {
 }
 
 func dedupeCandidates(items []schemaFieldCandidate) []schemaFieldCandidate {
-	merged := make(map[string]schemaFieldCandidate, len(items))
-	order := make([]string, 0, len(items))
+	index := make(map[string]int, len(items))
+	out := make([]schemaFieldCandidate, 0, len(items))
 	for _, item := range items {
-		existing, found := merged[item.Field.Path]
+		position, found := index[item.Field.Path]
 		if !found {
-			merged[item.Field.Path] = item
-			order = append(order, item.Field.Path)
+			index[item.Field.Path] = len(out)
+			out = append(out, item)
 			continue
 		}
 
+		existing := &out[position]
 		if item.Required {
 			existing.Required = true
 		}
@@ -660,21 +1264,62 @@ func dedupeCandidates(items []schemaFieldCandidate) []schemaFieldCandidate {
 			existing.HasDefault = true
 			existing.Field.Value = item.Field.Value
 		}
-		if existing.Field.Description == "" {
+		if existing.Field.Description == "" || (existing.InferredDescription && !item.InferredDescription) {
 			existing.Field.Description = item.Field.Description
+			existing.InferredDescription = item.InferredDescription
 		}
 		if existing.Field.Type == "" {
 			existing.Field.Type = item.Field.Type
 		}
+		if len(existing.Field.Enum) == 0 {
+			existing.Field.Enum = item.Field.Enum
+		}
+		if existing.Field.Constraints == nil {
+			existing.Field.Constraints = item.Field.Constraints
+		}
+		if item.Field.Immutable {
+			existing.Field.Immutable = true
+		}
+		if len(existing.Field.Rules) == 0 {
+			existing.Field.Rules = item.Field.Rules
+		}
 		if item.Depth < existing.Depth {
 			existing.Depth = item.Depth
 		}
-		merged[item.Field.Path] = existing
 	}
+	for i := range out {
+		out[i].Field.Required = out[i].Required
+	}
+	return out
+}
 
-	out := make([]schemaFieldCandidate, 0, len(order))
-	for _, key := range order {
-		out = append(out, merged[key])
+// sortCandidatesByPriority orders candidates required > default > shallow >
+// alphabetical. It sorts an index slice rather than the candidates themselves
+// so large CRDs don't pay for swapping whole field structs.
+func sortCandidatesByPriority(items []schemaFieldCandidate) []schemaFieldCandidate {
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		left := &items[order[i]]
+		right := &items[order[j]]
+
+		if left.Required != right.Required {
+			return left.Required
+		}
+		if left.HasDefault != right.HasDefault {
+			return left.HasDefault
+		}
+		if left.Depth != right.Depth {
+			return left.Depth < right.Depth
+		}
+		return left.Field.Path < right.Field.Path
+	})
+
+	out := make([]schemaFieldCandidate, len(items))
+	for i, position := range order {
+		out[i] = items[position]
 	}
 	return out
 }
@@ -719,13 +1364,15 @@ func ensureTopLevelSpecCoverage(
 		return defaults
 	}
 
-	candidatesByTop := make(map[string][]models.FieldDefinition, len(properties))
-	for _, candidate := range collected {
-		top := topLevelSpecKey(candidate.Field.Path)
+	firstByTop := make(map[string]int, len(properties))
+	for i := range collected {
+		top := topLevelSpecKey(collected[i].Field.Path)
 		if top == "" {
 			continue
 		}
-		candidatesByTop[top] = append(candidatesByTop[top], candidate.Field)
+		if _, exists := firstByTop[top]; !exists {
+			firstByTop[top] = i
+		}
 	}
 
 	existingTop := make(map[string]bool, len(defaults))
@@ -748,8 +1395,8 @@ func ensureTopLevelSpecCoverage(
 			continue
 		}
 
-		if candidates := candidatesByTop[key]; len(candidates) > 0 {
-			extra = append(extra, candidates[0])
+		if position, ok := firstByTop[key]; ok {
+			extra = append(extra, collected[position].Field)
 			continue
 		}
 
@@ -767,18 +1414,18 @@ func ensureTopLevelSpecCoverage(
 }
 
 func topLevelSpecKey(path string) string {
-	parts := strings.Split(path, ".")
-	if len(parts) < 2 || parts[0] != "spec" {
+	rest, ok := strings.CutPrefix(path, "spec.")
+	if !ok {
 		return ""
 	}
-	key := parts[1]
-	if idx := strings.Index(key, "["); idx >= 0 {
+	key, _, _ := strings.Cut(rest, ".")
+	if idx := strings.IndexByte(key, '['); idx >= 0 {
 		key = key[:idx]
 	}
 	return key
 }
 
-func extractServiceSeedFields(specSchema map[string]any) []models.FieldDefinition {
+func extractServiceSeedFields(specSchema map[string]any, heuristics ServiceHeuristics) []models.FieldDefinition {
 	properties, _ := specSchema["properties"].(map[string]any)
 	if len(properties) == 0 {
 		return nil
@@ -793,7 +1440,7 @@ func extractServiceSeedFields(specSchema map[string]any) []models.FieldDefinitio
 	seeds := make([]models.FieldDefinition, 0, len(keys))
 	for _, key := range keys {
 		node, _ := properties[key].(map[string]any)
-		if !isServiceLikeNode(key, node) {
+		if !isServiceLikeNode(key, node, heuristics) {
 			continue
 		}
 
@@ -807,7 +1454,32 @@ func extractServiceSeedFields(specSchema map[string]any) []models.FieldDefinitio
 	return dedupeFields(seeds)
 }
 
-func isServiceLikeNode(name string, node map[string]any) bool {
+// ServiceHeuristics decides which top-level spec properties look like service
+// components and get a seeded default field. An object property qualifies
+// when it has one of the Markers as a property, or when it has a nested spec
+// and its name contains one of the Keywords (case-insensitively).
+type ServiceHeuristics struct {
+	Keywords []string
+	Markers  []string
+}
+
+// DefaultServiceHeuristics are used for any list that is not configured.
+var DefaultServiceHeuristics = ServiceHeuristics{
+	Keywords: []string{"madara", "bootstrapper", "orchestrator", "path", "dna", "faucet"},
+	Markers:  []string{"configMaps", "envFromSecret"},
+}
+
+func (h ServiceHeuristics) withDefaults(base ServiceHeuristics) ServiceHeuristics {
+	if len(h.Keywords) == 0 {
+		h.Keywords = base.Keywords
+	}
+	if len(h.Markers) == 0 {
+		h.Markers = base.Markers
+	}
+	return h
+}
+
+func isServiceLikeNode(name string, node map[string]any, heuristics ServiceHeuristics) bool {
 	if node == nil {
 		return false
 	}
@@ -820,22 +1492,18 @@ func isServiceLikeNode(name string, node map[string]any) bool {
 		return false
 	}
 
-	if _, ok := properties["configMaps"]; ok {
-		return true
-	}
-	if _, ok := properties["envFromSecret"]; ok {
-		return true
+	for _, marker := range heuristics.Markers {
+		if _, ok := properties[marker]; ok {
+			return true
+		}
 	}
 	if _, ok := properties["spec"]; ok {
 		// Typical component/service shape in operator CRDs.
 		lower := strings.ToLower(name)
-		if strings.Contains(lower, "madara") ||
-			strings.Contains(lower, "bootstrapper") ||
-			strings.Contains(lower, "orchestrator") ||
-			strings.Contains(lower, "path") ||
-			strings.Contains(lower, "dna") ||
-			strings.Contains(lower, "faucet") {
-			return true
+		for _, keyword := range heuristics.Keywords {
+			if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" && strings.Contains(lower, keyword) {
+				return true
+			}
 		}
 	}
 
@@ -1019,7 +1687,7 @@ func firstScalarSeed(
 	return models.FieldDefinition{}, false
 }
 
-func parseWithRegexFallback(raw string) models.TemplateDefinition {
+func parseWithRegexFallback(raw string, omitInferredDescriptions bool, warnings *[]string) models.TemplateDefinition {
 	kind := firstCapture(regexKind, raw)
 	if kind == "" {
 		kind = "CustomResource"
@@ -1031,9 +1699,11 @@ func parseWithRegexFallback(raw string) models.TemplateDefinition {
 		version = firstCapture(regexVersion, raw)
 	}
 
-	apiVersion := "example.io/v1"
+	apiVersion := PlaceholderAPIVersion
 	if group != "" && version != "" {
 		apiVersion = fmt.Sprintf("%s/%s", group, version)
+	} else {
+		*warnings = append(*warnings, PlaceholderAPIVersionWarning)
 	}
 
 	fields := make([]models.FieldDefinition, 0, 8)
@@ -1053,16 +1723,18 @@ func parseWithRegexFallback(raw string) models.TemplateDefinition {
 		if fieldPathExists(fields, path) {
 			continue
 		}
-		fields = append(fields, models.FieldDefinition{
-			Path:        path,
-			Description: fmt.Sprintf("Inferred from parsed field '%s'.", name),
-		})
+		field := models.FieldDefinition{Path: path}
+		if !omitInferredDescriptions {
+			field.Description = fmt.Sprintf("Inferred from parsed field '%s'.", name)
+		}
+		fields = append(fields, field)
 		if len(fields) >= 8 {
 			break
 		}
 	}
 
 	if len(fields) == 0 {
+		*warnings = append(*warnings, "No spec fields found; synthesized placeholder field spec.example.")
 		fields = append(fields, models.FieldDefinition{
 			Path:        "spec.example",
 			Description: "No schema fields inferred from input. Replace this with real fields.",
@@ -1197,24 +1869,6 @@ func nestedWithArrayPath(root map[string]any, keys ...string) any {
 	return current
 }
 
-func firstVersion(candidate any) string {
-	versions, ok := candidate.([]any)
-	if !ok {
-		return ""
-	}
-	for _, version := range versions {
-		verMap, ok := version.(map[string]any)
-		if !ok {
-			continue
-		}
-		name := asString(verMap["name"])
-		if name != "" {
-			return name
-		}
-	}
-	return ""
-}
-
 func hasCRDSchema(root map[string]any) bool {
 	legacy := nested(root, "spec", "validation", "openAPIV3Schema")
 	if legacy != nil {
@@ -1238,6 +1892,28 @@ func hasCRDSchema(root map[string]any) bool {
 	return false
 }
 
+// suggestKind returns the canonical Kind for a near-miss spelling such as
+// "deployment" or "deploy". Exact matches and unknown kinds return false.
+func suggestKind(kind string) (string, bool) {
+	if kind == "" {
+		return "", false
+	}
+	canonical, ok := canonicalKinds[strings.ToLower(kind)]
+	if !ok || canonical == kind {
+		return "", false
+	}
+	return canonical, true
+}
+
+// normalizeLineEndings converts Windows (\r\n) and classic Mac (\r) line
+// endings to \n so the line-anchored fallback regexes see clean lines.
+func normalizeLineEndings(raw string) string {
+	if !strings.Contains(raw, "\r") {
+		return raw
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(raw, "\r\n", "\n"), "\r", "\n")
+}
+
 func asString(value any) string {
 	text, ok := value.(string)
 	if !ok {
@@ -1246,6 +1922,19 @@ func asString(value any) string {
 	return strings.TrimSpace(text)
 }
 
+func asNumber(value any) (float64, bool) {
+	switch typed := value.(type) {
+	case int:
+		return float64(typed), true
+	case int64:
+		return float64(typed), true
+	case float64:
+		return typed, true
+	default:
+		return 0, false
+	}
+}
+
 func asBool(value any) bool {
 	typed, ok := value.(bool)
 	if !ok {
@@ -1260,6 +1949,8 @@ func normalizeFieldType(value string) string {
 		return "number"
 	case "boolean":
 		return "boolean"
+	case "string":
+		return "string"
 	default:
 		return ""
 	}
@@ -1285,7 +1976,7 @@ func fieldPathExists(fields []models.FieldDefinition, path string) bool {
 func normalizeID(input string) string {
 	lower := strings.ToLower(input)
 	lower = strings.ReplaceAll(lower, "_", "-")
-	lower = regexp.MustCompile(`[^a-z0-9-]`).ReplaceAllString(lower, "")
+	lower = regexInvalidIDChars.ReplaceAllString(lower, "")
 	lower = strings.Trim(lower, "-")
 	if lower == "" {
 		return "parsed-custom-resource"
@@ -1,9 +1,27 @@
 package services
 
-import "testing"
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files under testdata")
 
 func TestParseCRD(t *testing.T) {
-	service := NewCRDService()
+	service := NewCRDService(config.Config{})
 	raw := `
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -58,7 +76,7 @@ spec:
 }
 
 func TestParseResourceYAML(t *testing.T) {
-	service := NewCRDService()
+	service := NewCRDService(config.Config{})
 	raw := `
 apiVersion: apps/v1
 kind: Deployment
@@ -82,8 +100,129 @@ spec:
 	}
 }
 
+func TestParseResourceYAML_SeedsRequiredFieldsForBuiltinKinds(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	raw := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`
+
+	result, err := service.ParseCRD(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	values := map[string]string{}
+	for _, field := range result.DefaultFields {
+		values[field.Path] = field.Value
+	}
+	for path, expected := range map[string]string{
+		"spec.selector.matchLabels.app":          "web",
+		"spec.template.metadata.labels.app":      "web",
+		"spec.template.spec.containers[0].image": "nginx:1.27",
+	} {
+		if values[path] != expected {
+			t.Fatalf("expected %s=%q, got %q in %v", path, expected, values[path], values)
+		}
+	}
+
+	manifest, err := NewYAMLService().GenerateYAML(result.APIVersion, result.Kind, result.DefaultFields)
+	if err != nil {
+		t.Fatalf("generate yaml: %v", err)
+	}
+	if !strings.Contains(manifest, "matchLabels:") || !strings.Contains(manifest, "containers:") {
+		t.Fatalf("expected selector and pod template in generated manifest:\n%s", manifest)
+	}
+}
+
+func TestParseResourceYAML_SkipsSeedsForCustomGroupsAndExistingSpecKeys(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	custom, err := service.ParseCRD("apiVersion: apps.example.io/v1\nkind: Deployment\nmetadata:\n  name: web\n")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(custom.DefaultFields) != 2 {
+		t.Fatalf("expected only metadata seeds for a custom Deployment kind, got %+v", custom.DefaultFields)
+	}
+
+	job, err := service.ParseCRD("apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: migrate\nspec:\n  template:\n    spec:\n      restartPolicy: OnFailure\n")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, field := range job.DefaultFields {
+		if strings.HasPrefix(field.Path, "spec.template.") {
+			t.Fatalf("expected no seeds under an existing spec.template, got %s", field.Path)
+		}
+	}
+}
+
+func TestParseCRD_LegacyV1beta1SchemaUsesDeclaredVersion(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	singleVersion := `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  group: stable.example.com
+  version: v1beta1
+  scope: Namespaced
+  names:
+    plural: crontabs
+    singular: crontab
+    kind: CronTab
+    shortNames:
+      - ct
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          required: [cronSpec]
+          properties:
+            cronSpec:
+              type: string
+              pattern: '^(\d+|\*)(/\d+)?(\s+(\d+|\*)(/\d+)?){4}$'
+            replicas:
+              type: integer
+              minimum: 1
+              maximum: 10
+`
+	result, warnings, err := service.ParseCRDWithWarnings(singleVersion)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.APIVersion != "stable.example.com/v1beta1" {
+		t.Fatalf("expected apiVersion stable.example.com/v1beta1, got %s", result.APIVersion)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("expected the legacy schema to be used without warnings, got %v", warnings)
+	}
+	if !fieldPathExists(result.DefaultFields, "spec.cronSpec") {
+		t.Fatalf("expected spec.cronSpec from spec.validation, got %+v", result.DefaultFields)
+	}
+
+	// A versions list without per-version schemas shares spec.validation; the
+	// storage version, not the first listed one, belongs in apiVersion.
+	versionList := strings.Replace(singleVersion, "  version: v1beta1\n", `  versions:
+    - name: v1alpha1
+      served: true
+      storage: false
+    - name: v1beta1
+      served: true
+      storage: true
+`, 1)
+	listed, err := service.ParseCRD(versionList)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if listed.APIVersion != "stable.example.com/v1beta1" {
+		t.Fatalf("expected storage version in apiVersion, got %s", listed.APIVersion)
+	}
+}
+
 func TestParseCRD_PrioritizesSignalFields(t *testing.T) {
-	service := NewCRDService()
+	service := NewCRDService(config.Config{})
 	raw := `
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -167,7 +306,7 @@ spec:
 }
 
 func TestParseCRD_IncludesServiceSeedsForAllComponents(t *testing.T) {
-	service := NewCRDService()
+	service := NewCRDService(config.Config{})
 	raw := `
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -264,7 +403,7 @@ spec:
 }
 
 func TestParseCRD_UsesMapEntryFieldForAdditionalProperties(t *testing.T) {
-	service := NewCRDService()
+	service := NewCRDService(config.Config{})
 	raw := `
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -309,3 +448,953 @@ spec:
 		t.Fatalf("expected map entry seed path for annotations")
 	}
 }
+
+func TestParseCRD_SeedsArrayFieldFromFirstDefaultElement(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: demo.io
+  names:
+    kind: Demo
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                args:
+                  type: array
+                  default: ["--verbose", "--color"]
+                  items:
+                    type: string
+`
+
+	result, err := service.ParseCRD(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, field := range result.DefaultFields {
+		if field.Path == "spec.args[0]" {
+			if field.Value != "--verbose" {
+				t.Fatalf("expected first default element, got %q", field.Value)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected spec.args[0] in default fields, got %+v", result.DefaultFields)
+}
+
+func TestParseCRD_MarksFieldsWithOldSelfRuleImmutable(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: demo.io
+  names:
+    kind: Demo
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                storageClass:
+                  type: string
+                  x-kubernetes-validations:
+                    - rule: oldSelf == self
+                      message: storageClass is immutable
+                size:
+                  type: string
+                  x-kubernetes-validations:
+                    - rule: self.endsWith('Gi')
+`
+
+	fields, err := service.AllFields(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	immutable := map[string]bool{}
+	for _, field := range fields {
+		immutable[field.Path] = field.Immutable
+	}
+	if !immutable["spec.storageClass"] {
+		t.Fatalf("expected spec.storageClass to be immutable, got %+v", fields)
+	}
+	if immutable["spec.size"] {
+		t.Fatalf("expected unrelated validation rules not to mark spec.size immutable")
+	}
+}
+
+func TestParseCRD_KeepsSchemaDescriptionsOverInferredText(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: demo.io
+  names:
+    kind: Demo
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                replicas:
+                  type: integer
+                  description: Number of desired pods.
+                paused:
+                  type: boolean
+`
+
+	template, _, err := service.ParseCRDWithOptions(raw, ParseOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	descriptions := map[string]string{}
+	for _, field := range append(template.DefaultFields, template.OptionalFields...) {
+		descriptions[field.Path] = field.Description
+	}
+	if got := descriptions["spec.replicas"]; got != "Number of desired pods." {
+		t.Fatalf("expected schema description to be kept, got %q", got)
+	}
+	if got := descriptions["spec.paused"]; !strings.HasPrefix(got, "Inferred from") {
+		t.Fatalf("expected inferred description for undocumented field, got %q", got)
+	}
+
+	template, _, err = service.ParseCRDWithOptions(raw, ParseOptions{OmitInferredDescriptions: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, field := range append(template.DefaultFields, template.OptionalFields...) {
+		if field.Path == "spec.paused" && field.Description != "" {
+			t.Fatalf("expected empty description when omitting inferred text, got %q", field.Description)
+		}
+		if field.Path == "spec.replicas" && field.Description != "Number of desired pods." {
+			t.Fatalf("expected schema description to survive omission, got %q", field.Description)
+		}
+	}
+}
+
+func TestDedupeCandidatesPrefersSchemaDescription(t *testing.T) {
+	deduped := dedupeCandidates([]schemaFieldCandidate{
+		{
+			Field:               models.FieldDefinition{Path: "spec.size", Description: "Inferred from CRD schema field 'size'."},
+			InferredDescription: true,
+		},
+		{
+			Field: models.FieldDefinition{Path: "spec.size", Description: "Volume size in GiB."},
+		},
+	})
+
+	if len(deduped) != 1 {
+		t.Fatalf("expected one candidate, got %d", len(deduped))
+	}
+	if deduped[0].Field.Description != "Volume size in GiB." || deduped[0].InferredDescription {
+		t.Fatalf("expected schema description to replace inferred text, got %+v", deduped[0])
+	}
+}
+
+func TestParseCRD_CollectsValidationRuleMessages(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: demo.io
+  names:
+    kind: Demo
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              x-kubernetes-validations:
+                - rule: self.minReplicas <= self.maxReplicas
+                  message: minReplicas must not exceed maxReplicas
+              required: [minReplicas, maxReplicas]
+              properties:
+                minReplicas:
+                  type: integer
+                maxReplicas:
+                  type: integer
+                  x-kubernetes-validations:
+                    - rule: self <= 100
+                      message: maxReplicas must be at most 100
+                    - rule: self % 2 == 0
+`
+
+	template, err := service.ParseCRD(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(template.Rules) != 1 || template.Rules[0] != "minReplicas must not exceed maxReplicas" {
+		t.Fatalf("expected spec-level rule message, got %v", template.Rules)
+	}
+	for _, field := range template.DefaultFields {
+		if field.Path != "spec.maxReplicas" {
+			continue
+		}
+		if len(field.Rules) != 2 || field.Rules[0] != "maxReplicas must be at most 100" || field.Rules[1] != "self % 2 == 0" {
+			t.Fatalf("expected rule messages with expression fallback, got %v", field.Rules)
+		}
+		return
+	}
+	t.Fatalf("expected spec.maxReplicas in default fields, got %+v", template.DefaultFields)
+}
+
+func TestCollectSchemaFields_StopsAtRecursiveSchema(t *testing.T) {
+	node := map[string]any{"type": "object"}
+	nodeProps := map[string]any{
+		"name": map[string]any{"type": "string"},
+	}
+	nodeProps["child"] = node
+	node["properties"] = nodeProps
+
+	properties := map[string]any{
+		"node":  node,
+		"alias": node,
+	}
+
+	collected := make([]schemaFieldCandidate, 0)
+	collectSchemaFields("spec", properties, map[string]bool{}, 0, 4, 420, make(schemaAncestors), &collected)
+
+	paths := make([]string, 0, len(collected))
+	for _, candidate := range collected {
+		paths = append(paths, candidate.Field.Path)
+	}
+	expected := []string{"spec.alias.name", "spec.node.name"}
+	if !slices.Equal(paths, expected) {
+		t.Fatalf("expected recursion to stop with both siblings collected, got %v", paths)
+	}
+}
+
+func TestParseCRD_CollectsSiblingsSharingAnchoredSubschema(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                a: &shared
+                  type: object
+                  properties:
+                    n:
+                      type: string
+                    m:
+                      type: integer
+                b: *shared
+`
+	template, err := service.ParseCRD(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	paths := make(map[string]bool)
+	for _, field := range append(template.DefaultFields, template.OptionalFields...) {
+		paths[field.Path] = true
+	}
+	for _, path := range []string{"spec.a.n", "spec.a.m", "spec.b.n", "spec.b.m"} {
+		if !paths[path] {
+			t.Fatalf("expected %s to be collected, got %v", path, paths)
+		}
+	}
+}
+
+func TestParseCRD_LargeFixtureMatchesGolden(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("testdata", "large_crd.yaml"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	result, err := NewCRDService(config.Config{}).ParseCRD(string(raw))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	got, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal result: %v", err)
+	}
+
+	goldenPath := filepath.Join("testdata", "large_crd.golden.json")
+	if *updateGolden {
+		if err := os.WriteFile(goldenPath, append(got, '\n'), 0o644); err != nil {
+			t.Fatalf("write golden: %v", err)
+		}
+	}
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("read golden: %v", err)
+	}
+	if string(append(got, '\n')) != string(want) {
+		t.Fatalf("parsed output drifted from %s; rerun with -update only if the change is intended", goldenPath)
+	}
+}
+
+func BenchmarkParseCRD(b *testing.B) {
+	raw, err := os.ReadFile(filepath.Join("testdata", "large_crd.yaml"))
+	if err != nil {
+		b.Fatalf("read fixture: %v", err)
+	}
+	service := NewCRDService(config.Config{})
+	input := string(raw)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := service.ParseCRD(input); err != nil {
+			b.Fatalf("parse: %v", err)
+		}
+	}
+}
+
+func BenchmarkExtractCRDSpecFields(b *testing.B) {
+	raw, err := os.ReadFile(filepath.Join("testdata", "large_crd.yaml"))
+	if err != nil {
+		b.Fatalf("read fixture: %v", err)
+	}
+	docs, err := decodeYAMLDocuments(string(raw))
+	if err != nil {
+		b.Fatalf("decode fixture: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		extractCRDSpecFields(docs[0], ParseOptions{Prioritization: PrioritizeRequiredFirst}, nil)
+	}
+}
+
+func TestValidateCRD_SuggestsCanonicalKind(t *testing.T) {
+	service := NewCRDService(config.Config{})
+
+	cases := map[string]string{
+		"deployment": "Deployment",
+		"Deploy":     "Deployment",
+		"svc":        "Service",
+	}
+	for kind, expected := range cases {
+		result := service.ValidateCRD("apiVersion: apps/v1\nkind: " + kind + "\nmetadata:\n  name: web\n")
+		if !result.Valid {
+			t.Fatalf("expected kind %q to stay valid with only a warning, got errors %v", kind, result.Errors)
+		}
+		found := false
+		for _, warning := range result.Warnings {
+			if strings.Contains(warning, "did you mean "+expected+"?") {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("expected suggestion %q for kind %q, got %v", expected, kind, result.Warnings)
+		}
+	}
+
+	result := service.ValidateCRD("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n")
+	for _, warning := range result.Warnings {
+		if strings.Contains(warning, "did you mean") {
+			t.Fatalf("expected no suggestion for canonical kind, got %q", warning)
+		}
+	}
+}
+
+func TestValidateCRDWarnsAboutDeprecatedAPIVersions(t *testing.T) {
+	service := NewCRDService(config.Config{})
+
+	deprecated := service.ValidateCRD("apiVersion: extensions/v1beta1\nkind: Deployment\nmetadata:\n  name: web\n")
+	if !deprecated.Valid {
+		t.Fatalf("expected deprecated apiVersion to stay valid with a warning, got errors %v", deprecated.Errors)
+	}
+	if !slices.Contains(deprecated.Warnings, "apiVersion extensions/v1beta1 for Deployment is deprecated; use apps/v1 instead.") {
+		t.Fatalf("expected a deprecation warning naming apps/v1, got %v", deprecated.Warnings)
+	}
+
+	current := service.ValidateCRD("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n")
+	for _, warning := range current.Warnings {
+		if strings.Contains(warning, "deprecated") {
+			t.Fatalf("expected no deprecation warning for apps/v1, got %q", warning)
+		}
+	}
+}
+
+func TestParseCRDWithWarningsReportsRegexFallback(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	template, warnings, err := service.ParseCRDWithWarnings("names: [unclosed\n  kind: Widget\n  group: example.io")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if template.Kind == "" {
+		t.Fatalf("expected fallback parser to still produce a template")
+	}
+	if len(warnings) == 0 || !strings.Contains(warnings[0], "fell back to regex parser") {
+		t.Fatalf("expected regex fallback warning, got %v", warnings)
+	}
+	if !containsSubstring(warnings, "synthesized placeholder field") {
+		t.Fatalf("expected placeholder field warning, got %v", warnings)
+	}
+
+	_, warnings, err = service.ParseCRDWithWarnings(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+`)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !containsSubstring(warnings, "no openAPIV3Schema") || containsSubstring(warnings, "regex parser") {
+		t.Fatalf("expected only schema-quality warnings for a schemaless CRD, got %v", warnings)
+	}
+}
+
+func containsSubstring(items []string, needle string) bool {
+	for _, item := range items {
+		if strings.Contains(item, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateCRDWarnsOnMalformedPrinterColumnJSONPath(t *testing.T) {
+	crd := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.io
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      additionalPrinterColumns:
+        - name: Ready
+          type: string
+          jsonPath: .status.conditions[?(@.type=="Ready")].status
+        - name: Replicas
+          type: integer
+          jsonPath: .spec..replicas
+        - name: Phase
+          type: string
+          jsonPath: .status.phase[0
+      schema:
+        openAPIV3Schema:
+          type: object
+`
+	result := NewCRDService(config.Config{}).ValidateCRD(crd)
+	if !result.Valid {
+		t.Fatalf("expected printer column problems to be warnings, got errors %v", result.Errors)
+	}
+	if len(result.Warnings) != 2 {
+		t.Fatalf("expected two printer column warnings, got %v", result.Warnings)
+	}
+	if !strings.Contains(result.Warnings[0], "spec.versions[0].additionalPrinterColumns[1].jsonPath") ||
+		!strings.Contains(result.Warnings[1], "spec.versions[0].additionalPrinterColumns[2].jsonPath") {
+		t.Fatalf("expected warnings to name the malformed columns, got %v", result.Warnings)
+	}
+
+	for _, valid := range []string{".metadata.creationTimestamp", ".spec.items[*].name", "$.status['ready-replicas']", ".spec.ports[0:2]"} {
+		if err := ValidateJSONPathSyntax(valid); err != nil {
+			t.Fatalf("expected %q to be valid, got %v", valid, err)
+		}
+	}
+}
+
+func TestJSONPathsUseWildcardForArrays(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	resource := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app.kubernetes.io/name: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: web
+          image: nginx:1.27
+        - name: sidecar
+          image: envoy:1.30
+`
+	paths, err := service.JSONPaths(resource)
+	if err != nil {
+		t.Fatalf("jsonpaths: %v", err)
+	}
+	for _, expected := range []string{
+		"$.spec.replicas",
+		"$.spec.template.spec.containers[*].image",
+		"$.metadata.labels['app.kubernetes.io/name']",
+	} {
+		if !slices.Contains(paths, expected) {
+			t.Fatalf("expected %s in %v", expected, paths)
+		}
+	}
+	if containsSubstring(paths, "[0]") {
+		t.Fatalf("expected no numeric indexes, got %v", paths)
+	}
+
+	crd := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.io
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                ports:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      port:
+                        type: integer
+`
+	paths, err = service.JSONPaths(crd)
+	if err != nil {
+		t.Fatalf("jsonpaths: %v", err)
+	}
+	if !slices.Contains(paths, "$.spec.ports[*].port") {
+		t.Fatalf("expected schema array field to use [*], got %v", paths)
+	}
+}
+
+func TestParseCRDNormalizesWindowsLineEndings(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	inputs := []string{
+		"names: [unclosed\n  kind: Widget\n  group: example.io\nversions:\n  - name: v1\n        size:\n        replicas:\n",
+		"apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nspec:\n  group: example.io\n  names:\n    kind: Widget\n",
+	}
+	for _, lf := range inputs {
+		want, err := service.ParseCRD(lf)
+		if err != nil {
+			t.Fatalf("parse LF input: %v", err)
+		}
+		wantJSON, _ := json.Marshal(want)
+		wantValidation, _ := json.Marshal(service.ValidateCRD(lf))
+
+		for _, ending := range []string{"\r\n", "\r"} {
+			input := strings.ReplaceAll(lf, "\n", ending)
+			got, err := service.ParseCRD(input)
+			if err != nil {
+				t.Fatalf("parse %q input: %v", ending, err)
+			}
+			if gotJSON, _ := json.Marshal(got); string(wantJSON) != string(gotJSON) {
+				t.Fatalf("expected %q input to parse like LF input\nwant: %s\ngot:  %s", ending, wantJSON, gotJSON)
+			}
+			if gotValidation, _ := json.Marshal(service.ValidateCRD(input)); string(wantValidation) != string(gotValidation) {
+				t.Fatalf("expected %q validation to match LF\nwant: %s\ngot:  %s", ending, wantValidation, gotValidation)
+			}
+		}
+	}
+}
+
+func TestParseCRDListsAvailableVersions(t *testing.T) {
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1beta1
+      served: true
+      storage: false
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                size:
+                  type: string
+`
+	template, err := NewCRDService(config.Config{}).ParseCRD(raw)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	expected := []models.VersionInfo{
+		{Name: "v1beta1", Served: true, Storage: false, HasSchema: false},
+		{Name: "v1", Served: true, Storage: true, HasSchema: true},
+	}
+	if !slices.Equal(template.AvailableVersions, expected) {
+		t.Fatalf("expected versions %+v, got %+v", expected, template.AvailableVersions)
+	}
+}
+
+func TestParseCRDReportsStorageVersionAsSelected(t *testing.T) {
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1alpha1
+      served: true
+      storage: false
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                legacySize:
+                  type: string
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                size:
+                  type: string
+`
+	template, err := NewCRDService(config.Config{}).ParseCRD(raw)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if template.SelectedVersion != "v1" {
+		t.Fatalf("expected selected version v1, got %q", template.SelectedVersion)
+	}
+	if template.APIVersion != "example.io/v1" {
+		t.Fatalf("expected apiVersion example.io/v1, got %q", template.APIVersion)
+	}
+	names := make([]string, 0, len(template.AvailableVersions))
+	for _, version := range template.AvailableVersions {
+		names = append(names, version.Name)
+	}
+	if !slices.Equal(names, []string{"v1alpha1", "v1"}) {
+		t.Fatalf("expected both versions to be listed, got %v", names)
+	}
+}
+
+func TestAcceptsGitHubTokenOnlyOverHTTPS(t *testing.T) {
+	for raw, want := range map[string]bool{
+		"https://raw.githubusercontent.com/org/repo/main/crd.yaml": true,
+		"https://api.github.com/repos/org/repo/contents/crd.yaml":  true,
+		"http://raw.githubusercontent.com/org/repo/main/crd.yaml":  false,
+		"https://example.com/crd.yaml":                             false,
+	} {
+		parsed, err := neturl.Parse(raw)
+		if err != nil {
+			t.Fatalf("parse %s: %v", raw, err)
+		}
+		if got := acceptsGitHubToken(parsed); got != want {
+			t.Fatalf("expected acceptsGitHubToken(%s) = %v, got %v", raw, want, got)
+		}
+	}
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestFetchCRDFromURLDecompressesGzipBodies(t *testing.T) {
+	crd := []byte("apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\n")
+	oversized := bytes.Repeat([]byte("# padding\n"), 300*1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		switch r.URL.Path {
+		case "/large.yaml":
+			_, _ = w.Write(gzipBytes(t, oversized))
+		default:
+			_, _ = w.Write(gzipBytes(t, crd))
+		}
+	}))
+	defer server.Close()
+
+	service := NewCRDService(config.Config{})
+	_, contents, err := service.FetchCRDFromURL(server.URL+"/crd.yaml", "")
+	if err != nil {
+		t.Fatalf("expected gzipped CRD to be fetched, got %v", err)
+	}
+	if !strings.Contains(contents, "kind: CustomResourceDefinition") {
+		t.Fatalf("expected decompressed YAML, got %q", contents)
+	}
+
+	if _, _, err := service.FetchCRDFromURL(server.URL+"/large.yaml", ""); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Fatalf("expected size limit to apply to decompressed bytes, got %v", err)
+	}
+}
+
+func TestParseCRDWarnsWhenAPIVersionFallsBackToPlaceholder(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  names:
+    kind: Orphan
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                size:
+                  type: integer
+`
+
+	template, warnings, err := service.ParseCRDWithWarnings(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if template.APIVersion != PlaceholderAPIVersion {
+		t.Fatalf("expected placeholder apiVersion, got %q", template.APIVersion)
+	}
+	if !slices.Contains(warnings, PlaceholderAPIVersionWarning) {
+		t.Fatalf("expected placeholder apiVersion warning, got %v", warnings)
+	}
+
+	_, warnings, err = service.ParseCRDWithWarnings(strings.Replace(raw, "  names:", "  group: demo.io\n  names:", 1))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if slices.Contains(warnings, PlaceholderAPIVersionWarning) {
+		t.Fatalf("expected no placeholder warning once spec.group is set, got %v", warnings)
+	}
+}
+
+func TestParseCRD_SeedsValuesFromSchemaExamples(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: demo.io
+  names:
+    kind: Demo
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required: [image, tier, mode]
+              properties:
+                image:
+                  type: string
+                  example: nginx:1.27
+                tier:
+                  type: string
+                  enum: [bronze, silver, gold]
+                  example: gold
+                mode:
+                  type: string
+                  default: fast
+                  example: slow
+`
+
+	template, err := service.ParseCRD(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	values := map[string]string{}
+	for _, field := range append(template.DefaultFields, template.OptionalFields...) {
+		values[field.Path] = field.Value
+	}
+	if values["spec.image"] != "nginx:1.27" {
+		t.Fatalf("expected example to seed spec.image, got %q", values["spec.image"])
+	}
+	if values["spec.tier"] != "gold" {
+		t.Fatalf("expected example to win over the first enum value, got %q", values["spec.tier"])
+	}
+	if values["spec.mode"] != "fast" {
+		t.Fatalf("expected default to win over example, got %q", values["spec.mode"])
+	}
+}
+
+func TestNewCRDServiceAppliesConfiguredFieldLimit(t *testing.T) {
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: demo.io
+  names:
+    kind: Demo
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                a: {type: string}
+                b: {type: string}
+                c: {type: string}
+                d: {type: string}
+                e: {type: string}
+`
+
+	countSpecFields := func(service *CRDService) int {
+		template, err := service.ParseCRD(raw)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		count := 0
+		for _, field := range append(template.DefaultFields, template.OptionalFields...) {
+			if strings.HasPrefix(field.Path, "spec.") {
+				count++
+			}
+		}
+		return count
+	}
+
+	if got := countSpecFields(NewCRDService(config.Config{})); got != 5 {
+		t.Fatalf("expected all 5 spec fields with default limits, got %d", got)
+	}
+	if got := countSpecFields(NewCRDService(config.Config{CRDMaxFields: 2})); got != 2 {
+		t.Fatalf("expected CRDMaxFields to cap spec fields at 2, got %d", got)
+	}
+}
+
+func TestParseCRDSeedsServiceComponentsMatchingConfiguredKeywords(t *testing.T) {
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: demo.io
+  names:
+    kind: Pipeline
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                ingestWorker:
+                  type: object
+                  properties:
+                    spec:
+                      type: object
+                      properties:
+                        image:
+                          type: string
+`
+
+	hasSeed := func(template models.TemplateDefinition) bool {
+		for _, field := range template.DefaultFields {
+			if strings.HasPrefix(field.Path, "spec.ingestWorker.") && strings.Contains(field.Description, "Service component 'ingestWorker'") {
+				return true
+			}
+		}
+		return false
+	}
+
+	template, err := NewCRDService(config.Config{}).ParseCRD(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if hasSeed(template) {
+		t.Fatalf("did not expect a service seed with the default keywords, got %+v", template.DefaultFields)
+	}
+
+	template, err = NewCRDService(config.Config{CRDServiceKeywords: []string{"Worker"}}).ParseCRD(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !hasSeed(template) {
+		t.Fatalf("expected a service seed for a configured keyword, got %+v", template.DefaultFields)
+	}
+
+	template, _, err = NewCRDService(config.Config{}).ParseCRDWithOptions(raw, ParseOptions{
+		ServiceHeuristics: ServiceHeuristics{Keywords: []string{"ingest"}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !hasSeed(template) {
+		t.Fatalf("expected a service seed for a keyword passed as a parse option, got %+v", template.DefaultFields)
+	}
+}
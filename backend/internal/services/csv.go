@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// OwnedCRDs returns the spec.customresourcedefinitions.owned entries of the
+// first ClusterServiceVersion among docs, in the order the CSV lists them.
+func OwnedCRDs(docs []BundleDocument) ([]models.CSVOwnedCRD, error) {
+	for _, doc := range docs {
+		if !strings.EqualFold(doc.Kind, "ClusterServiceVersion") {
+			continue
+		}
+		var csv map[string]any
+		if err := yaml.Unmarshal([]byte(doc.Raw), &csv); err != nil {
+			return nil, err
+		}
+		items, _ := nested(csv, "spec", "customresourcedefinitions", "owned").([]any)
+		owned := make([]models.CSVOwnedCRD, 0, len(items))
+		for _, item := range items {
+			entry, _ := item.(map[string]any)
+			name := strings.TrimSpace(asString(entry["name"]))
+			if name == "" {
+				continue
+			}
+			owned = append(owned, models.CSVOwnedCRD{
+				Name:        name,
+				Kind:        asString(entry["kind"]),
+				Version:     asString(entry["version"]),
+				DisplayName: asString(entry["displayName"]),
+				Description: asString(entry["description"]),
+			})
+		}
+		return owned, nil
+	}
+	return nil, errors.New("no ClusterServiceVersion document found")
+}
+
+// FindBundleCRD returns the CustomResourceDefinition document in docs whose
+// metadata.name matches name.
+func FindBundleCRD(docs []BundleDocument, name string) (BundleDocument, bool) {
+	for _, doc := range docs {
+		if strings.EqualFold(doc.Kind, "CustomResourceDefinition") && doc.Name == name {
+			return doc, true
+		}
+	}
+	return BundleDocument{}, false
+}
+
+// OwnedCRDGroup returns the API group of an owned CRD, which OLM encodes in
+// the CRD name as <plural>.<group>.
+func OwnedCRDGroup(owned models.CSVOwnedCRD) string {
+	_, group, _ := strings.Cut(owned.Name, ".")
+	return group
+}
@@ -0,0 +1,59 @@
+package services
+
+import "testing"
+
+const testCSV = `apiVersion: operators.coreos.com/v1alpha1
+kind: ClusterServiceVersion
+metadata:
+  name: etcd-operator.v0.9.4
+spec:
+  displayName: etcd
+  customresourcedefinitions:
+    owned:
+      - name: etcdclusters.etcd.database.coreos.com
+        kind: EtcdCluster
+        version: v1beta2
+        displayName: etcd Cluster
+        description: Represents a cluster of etcd nodes.
+      - name: etcdbackups.etcd.database.coreos.com
+        kind: EtcdBackup
+        version: v1beta2
+    required:
+      - name: other.example.com
+        kind: Other
+        version: v1
+`
+
+func TestOwnedCRDsExtractsOwnedEntries(t *testing.T) {
+	docs, err := SplitYAMLDocuments(testCSV)
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+
+	owned, err := OwnedCRDs(docs)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(owned) != 2 {
+		t.Fatalf("expected 2 owned CRDs, got %+v", owned)
+	}
+	if owned[0].Name != "etcdclusters.etcd.database.coreos.com" || owned[1].Name != "etcdbackups.etcd.database.coreos.com" {
+		t.Fatalf("unexpected owned CRD names: %+v", owned)
+	}
+	if owned[0].Kind != "EtcdCluster" || owned[0].Version != "v1beta2" || owned[0].Description != "Represents a cluster of etcd nodes." {
+		t.Fatalf("unexpected owned CRD details: %+v", owned[0])
+	}
+	if group := OwnedCRDGroup(owned[0]); group != "etcd.database.coreos.com" {
+		t.Fatalf("expected group from CRD name, got %q", group)
+	}
+}
+
+func TestOwnedCRDsRequiresClusterServiceVersion(t *testing.T) {
+	docs, err := SplitYAMLDocuments("apiVersion: v1\nkind: ConfigMap\n")
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+	if _, err := OwnedCRDs(docs); err == nil {
+		t.Fatalf("expected an error without a ClusterServiceVersion")
+	}
+}
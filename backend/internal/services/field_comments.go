@@ -0,0 +1,138 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// FieldComment summarizes a field's metadata as a single YAML comment, e.g.
+// "string, required, one of: dev|prod — Target environment".
+func FieldComment(field models.FieldDefinition) string {
+	parts := []string{fallback(field.Type, "string")}
+	if field.Required {
+		parts = append(parts, "required")
+	}
+	if field.Immutable {
+		parts = append(parts, "immutable")
+	}
+	if len(field.Enum) > 0 {
+		parts = append(parts, "one of: "+strings.Join(field.Enum, "|"))
+	}
+
+	comment := strings.Join(parts, ", ")
+	if description := strings.Join(strings.Fields(field.Description), " "); description != "" {
+		comment += " — " + description
+	}
+	return comment
+}
+
+// maxCommentWidth is the longest comment line annotateFieldComments writes,
+// including the leading "# ".
+const maxCommentWidth = 80
+
+// annotateFieldComments attaches FieldComment to the node each field path
+// resolves to. Scalars get a trailing line comment; mappings and sequences
+// carry it on their key so the comment stays on the line that names them.
+// Comments longer than maxCommentWidth are wrapped onto lines above the key
+// instead, since a trailing comment can't span lines.
+func annotateFieldComments(root *yaml.Node, fields []models.FieldDefinition) {
+	for _, field := range fields {
+		key, value := findFieldNode(root, parsePath(field.Path))
+		if value == nil {
+			continue
+		}
+		comment := "# " + FieldComment(field)
+		if len(comment) > maxCommentWidth {
+			target := key
+			if target == nil {
+				target = value
+			}
+			target.HeadComment = strings.Join(wrapComment(FieldComment(field), maxCommentWidth), "\n")
+			continue
+		}
+		switch {
+		case value.Kind == yaml.ScalarNode:
+			value.LineComment = comment
+		case key != nil:
+			key.LineComment = comment
+		default:
+			value.HeadComment = comment
+		}
+	}
+}
+
+// annotateEnumComments lists the allowed values of enum fields as a trailing
+// "# allowed: a, b, c" comment on the field's line. Lines that already carry
+// a comment, such as a FieldComment, are left alone.
+func annotateEnumComments(root *yaml.Node, fields []models.FieldDefinition) {
+	for _, field := range fields {
+		if len(field.Enum) == 0 {
+			continue
+		}
+		key, value := findFieldNode(root, parsePath(field.Path))
+		if value == nil {
+			continue
+		}
+		target := value
+		if value.Kind != yaml.ScalarNode {
+			if key == nil {
+				continue
+			}
+			target = key
+		}
+		if target.LineComment == "" && (key == nil || key.HeadComment == "") {
+			target.LineComment = "# allowed: " + strings.Join(field.Enum, ", ")
+		}
+	}
+}
+
+// wrapComment splits text into "# "-prefixed lines no wider than width,
+// breaking between words. A single word longer than the width gets a line of
+// its own.
+func wrapComment(text string, width int) []string {
+	lines := make([]string, 0, 2)
+	line := "#"
+	for _, word := range strings.Fields(text) {
+		if line != "#" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = "#"
+		}
+		line += " " + word
+	}
+	if line != "#" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func findFieldNode(root *yaml.Node, segments []any) (*yaml.Node, *yaml.Node) {
+	var key *yaml.Node
+	current := root
+	for _, segment := range segments {
+		if current == nil {
+			return nil, nil
+		}
+		switch typed := segment.(type) {
+		case string:
+			if current.Kind != yaml.MappingNode {
+				return nil, nil
+			}
+			var next *yaml.Node
+			for i := 0; i+1 < len(current.Content); i += 2 {
+				if current.Content[i].Value == typed {
+					key, next = current.Content[i], current.Content[i+1]
+					break
+				}
+			}
+			current = next
+		case int:
+			if current.Kind != yaml.SequenceNode || typed >= len(current.Content) {
+				return nil, nil
+			}
+			key, current = nil, current.Content[typed]
+		}
+	}
+	return key, current
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// DiffFields compares two field sets by path and returns the added, removed
+// and changed paths plus a generate-yaml request carrying only the added and
+// changed fields. Values are compared after the same parsing GenerateYAML
+// applies, so "1" and "1.0" on a number field are not reported as a change.
+func (s *YAMLService) DiffFields(req models.FieldDiffRequest) models.FieldDiffResponse {
+	base := indexFieldsByPath(req.Base)
+	edited := indexFieldsByPath(req.Edited)
+
+	result := models.FieldDiffResponse{
+		Added:   make([]string, 0),
+		Removed: make([]string, 0),
+		Changed: make([]string, 0),
+		Patch: models.GenerateYAMLRequest{
+			APIVersion: req.APIVersion,
+			Kind:       req.Kind,
+			Fields:     make([]models.FieldDefinition, 0),
+		},
+	}
+	for _, path := range sortedKeys(edited) {
+		field := edited[path]
+		previous, ok := base[path]
+		switch {
+		case !ok:
+			result.Added = append(result.Added, path)
+		case !fieldValuesEqual(previous, field):
+			result.Changed = append(result.Changed, path)
+		default:
+			continue
+		}
+		result.Patch.Fields = append(result.Patch.Fields, field)
+	}
+	for _, path := range sortedKeys(base) {
+		if _, ok := edited[path]; !ok {
+			result.Removed = append(result.Removed, path)
+		}
+	}
+	return result
+}
+
+// indexFieldsByPath keys fields by their trimmed path. When a path repeats,
+// the last entry wins, matching how GenerateYAML applies fields in order.
+func indexFieldsByPath(fields []models.FieldDefinition) map[string]models.FieldDefinition {
+	out := make(map[string]models.FieldDefinition, len(fields))
+	for _, field := range fields {
+		path := strings.TrimSpace(field.Path)
+		if path == "" {
+			continue
+		}
+		field.Path = path
+		out[path] = field
+	}
+	return out
+}
+
+func fieldValuesEqual(left, right models.FieldDefinition) bool {
+	if left.Type != right.Type {
+		return false
+	}
+	return reflect.DeepEqual(parseValue(left.Value, left.Type), parseValue(right.Value, right.Type))
+}
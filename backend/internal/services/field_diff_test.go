@@ -0,0 +1,60 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+func TestDiffFieldsPatchContainsOnlyEdits(t *testing.T) {
+	service := NewYAMLService()
+	result := service.DiffFields(models.FieldDiffRequest{
+		APIVersion: "demo.io/v1",
+		Kind:       "Demo",
+		Base: []models.FieldDefinition{
+			{Path: "metadata.name", Value: "demo"},
+			{Path: "spec.replicas", Value: "1", Type: "number"},
+			{Path: "spec.image", Value: "nginx:1.25"},
+			{Path: "spec.debug", Value: "false"},
+		},
+		Edited: []models.FieldDefinition{
+			{Path: "metadata.name", Value: "demo"},
+			{Path: "spec.replicas", Value: "3", Type: "number"},
+			{Path: "spec.image", Value: "nginx:1.25"},
+			{Path: " spec.port ", Value: "8080"},
+		},
+	})
+
+	if !reflect.DeepEqual(result.Added, []string{"spec.port"}) {
+		t.Fatalf("expected spec.port added, got %v", result.Added)
+	}
+	if !reflect.DeepEqual(result.Removed, []string{"spec.debug"}) {
+		t.Fatalf("expected spec.debug removed, got %v", result.Removed)
+	}
+	if !reflect.DeepEqual(result.Changed, []string{"spec.replicas"}) {
+		t.Fatalf("expected spec.replicas changed, got %v", result.Changed)
+	}
+
+	patch := result.Patch
+	if patch.APIVersion != "demo.io/v1" || patch.Kind != "Demo" {
+		t.Fatalf("expected patch to keep the resource type, got %s %s", patch.APIVersion, patch.Kind)
+	}
+	paths := make([]string, 0, len(patch.Fields))
+	for _, field := range patch.Fields {
+		paths = append(paths, field.Path)
+	}
+	if !reflect.DeepEqual(paths, []string{"spec.port", "spec.replicas"}) {
+		t.Fatalf("expected only edited fields in the patch, got %v", paths)
+	}
+}
+
+func TestDiffFieldsIgnoresEquivalentNumbers(t *testing.T) {
+	result := NewYAMLService().DiffFields(models.FieldDiffRequest{
+		Base:   []models.FieldDefinition{{Path: "spec.replicas", Value: "2", Type: "number"}},
+		Edited: []models.FieldDefinition{{Path: "spec.replicas", Value: "2.0", Type: "number"}},
+	})
+	if len(result.Changed) != 0 || len(result.Patch.Fields) != 0 {
+		t.Fatalf("expected no change for equivalent numbers, got %+v", result)
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+var fieldsCSVHeader = []string{"path", "type", "required", "default", "description"}
+
+// FieldsToCSV renders a field list as CSV for documenting a CRD in a
+// spreadsheet. Untyped fields are reported as "string".
+func FieldsToCSV(fields []models.FieldDefinition) (string, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(fieldsCSVHeader); err != nil {
+		return "", fmt.Errorf("write csv header: %w", err)
+	}
+	for _, field := range fields {
+		fieldType := field.Type
+		if fieldType == "" {
+			fieldType = "string"
+		}
+		record := []string{field.Path, fieldType, strconv.FormatBool(field.Required), field.Value, field.Description}
+		if err := writer.Write(record); err != nil {
+			return "", fmt.Errorf("write csv row: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("flush csv: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+func TestFieldsToCSV(t *testing.T) {
+	output, err := FieldsToCSV([]models.FieldDefinition{
+		{Path: "spec.replicas", Type: "number", Required: true, Value: "3", Description: "Desired replicas, at least 1."},
+		{Path: "spec.image", Description: "Container image"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and two rows, got %q", output)
+	}
+	if lines[0] != "path,type,required,default,description" {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	if lines[1] != `spec.replicas,number,true,3,"Desired replicas, at least 1."` {
+		t.Fatalf("unexpected row %q", lines[1])
+	}
+	if lines[2] != "spec.image,string,false,,Container image" {
+		t.Fatalf("unexpected row %q", lines[2])
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+	"strconv"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// Fingerprint returns a SHA-256 hex digest of raw that is independent of key
+// order, indentation, quoting and equivalent scalar spellings, so two
+// manifests that CompareYAML considers equal always share a fingerprint.
+func (s *YAMLService) Fingerprint(raw string) (string, error) {
+	value, err := decodeNormalizedYAML(raw)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.New()
+	writeCanonical(digest, value)
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// GroupDuplicateManifests buckets records by fingerprint and returns only the
+// buckets holding more than one manifest, in the order each was first seen.
+// Records whose YAML no longer parses are skipped.
+func (s *YAMLService) GroupDuplicateManifests(records []models.ManifestRecord) []models.ManifestDuplicateGroup {
+	index := make(map[string]int)
+	groups := make([]models.ManifestDuplicateGroup, 0)
+	for _, record := range records {
+		fingerprint, err := s.Fingerprint(record.YAML)
+		if err != nil {
+			continue
+		}
+		position, ok := index[fingerprint]
+		if !ok {
+			position = len(groups)
+			index[fingerprint] = position
+			groups = append(groups, models.ManifestDuplicateGroup{Fingerprint: fingerprint})
+		}
+		groups[position].Manifests = append(groups[position].Manifests, record)
+	}
+
+	out := make([]models.ManifestDuplicateGroup, 0)
+	for _, group := range groups {
+		if len(group.Manifests) > 1 {
+			out = append(out, group)
+		}
+	}
+	return out
+}
+
+// writeCanonical streams a type-tagged encoding of value with map keys sorted.
+// Lengths prefix every string so distinct structures can't collide by
+// concatenation.
+func writeCanonical(h hash.Hash, value any) {
+	switch typed := value.(type) {
+	case nil:
+		h.Write([]byte("n"))
+	case bool:
+		h.Write([]byte("b" + strconv.FormatBool(typed)))
+	case float64:
+		h.Write([]byte("f" + strconv.FormatFloat(typed, 'g', -1, 64) + ";"))
+	case string:
+		writeCanonicalString(h, "s", typed)
+	case []any:
+		h.Write([]byte(fmt.Sprintf("l%d:", len(typed))))
+		for _, item := range typed {
+			writeCanonical(h, item)
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		h.Write([]byte(fmt.Sprintf("m%d:", len(keys))))
+		for _, key := range keys {
+			writeCanonicalString(h, "k", key)
+			writeCanonical(h, typed[key])
+		}
+	default:
+		writeCanonicalString(h, "s", fmt.Sprint(typed))
+	}
+}
+
+func writeCanonicalString(h hash.Hash, tag, value string) {
+	h.Write([]byte(tag + strconv.Itoa(len(value)) + ":" + value))
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+func TestFingerprintIgnoresKeyOrderAndFormatting(t *testing.T) {
+	service := NewYAMLService()
+	left := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels: {app: web, tier: frontend}
+spec:
+  replicas: 3
+`
+	right := `
+kind: Deployment
+spec:
+    replicas: 3.0
+metadata:
+    labels:
+        tier: "frontend"
+        app: web
+    name: web
+apiVersion: apps/v1
+`
+	leftPrint, err := service.Fingerprint(left)
+	if err != nil {
+		t.Fatalf("fingerprint left: %v", err)
+	}
+	rightPrint, err := service.Fingerprint(right)
+	if err != nil {
+		t.Fatalf("fingerprint right: %v", err)
+	}
+	if leftPrint != rightPrint {
+		t.Fatalf("expected equal fingerprints, got %s and %s", leftPrint, rightPrint)
+	}
+	if len(leftPrint) != 64 {
+		t.Fatalf("expected a hex SHA-256 digest, got %q", leftPrint)
+	}
+
+	changed, err := service.Fingerprint(`{apiVersion: apps/v1, kind: Deployment, metadata: {name: web}, spec: {replicas: 4}}`)
+	if err != nil {
+		t.Fatalf("fingerprint changed: %v", err)
+	}
+	if changed == leftPrint {
+		t.Fatalf("expected different content to change the fingerprint")
+	}
+}
+
+func TestGroupDuplicateManifestsSkipsAliasBombs(t *testing.T) {
+	service := NewYAMLService()
+	recursive := "kind: ConfigMap\ndata: &x {nested: *x}\n"
+	if _, err := service.Fingerprint(recursive); err == nil {
+		t.Fatalf("expected a recursive anchor to be rejected")
+	}
+
+	bomb := `a: &a [x,x,x,x,x,x,x,x,x,x]
+b: &b [*a,*a,*a,*a,*a,*a,*a,*a,*a,*a]
+c: &c [*b,*b,*b,*b,*b,*b,*b,*b,*b,*b]
+d: &d [*c,*c,*c,*c,*c,*c,*c,*c,*c,*c]
+e: &e [*d,*d,*d,*d,*d,*d,*d,*d,*d,*d]
+f: &f [*e,*e,*e,*e,*e,*e,*e,*e,*e,*e]
+g: &g [*f,*f,*f,*f,*f,*f,*f,*f,*f,*f]
+`
+	records := []models.ManifestRecord{
+		{ID: "recursive", YAML: recursive},
+		{ID: "bomb", YAML: bomb},
+		{ID: "one", YAML: "kind: ConfigMap\nmetadata: {name: a}\n"},
+		{ID: "two", YAML: "metadata:\n  name: a\nkind: ConfigMap\n"},
+	}
+	groups := service.GroupDuplicateManifests(records)
+	if len(groups) != 1 || len(groups[0].Manifests) != 2 {
+		t.Fatalf("expected one duplicate group of the parseable manifests, got %+v", groups)
+	}
+	if groups[0].Manifests[0].ID != "one" || groups[0].Manifests[1].ID != "two" {
+		t.Fatalf("unexpected group members %+v", groups[0].Manifests)
+	}
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ExportHelmValues renders a starter Helm values.yaml for a CRD. Its
+// top-level keys mirror the properties of the spec schema and nest the same
+// way, so a chart can pass .Values straight into the resource's spec. Leaves
+// are seeded with the schema default, then example, then first enum value,
+// and otherwise with the zero value of their type.
+func (s *CRDService) ExportHelmValues(crdRaw string) (string, error) {
+	docs, err := decodeYAMLDocuments(strings.TrimSpace(normalizeLineEndings(crdRaw)))
+	if err != nil {
+		return "", fmt.Errorf("YAML parse error: %w", err)
+	}
+	crd, ok := selectPrimaryResourceDoc(docs)
+	if !ok || !strings.EqualFold(asString(crd["kind"]), "CustomResourceDefinition") {
+		return "", errors.New("payload does not contain a CustomResourceDefinition")
+	}
+	specSchema, _ := selectSpecSchema(crd)
+	properties, _ := specSchema["properties"].(map[string]any)
+	if len(properties) == 0 {
+		return "", errors.New("CRD has no spec properties to export")
+	}
+
+	values := helmValuesFromProperties(properties, 0, s.limits.withDefaults().MaxDepth)
+	node, err := resourceNode(values)
+	if err != nil {
+		return "", fmt.Errorf("marshal YAML: %w", err)
+	}
+	return marshalNode(node)
+}
+
+func helmValuesFromProperties(properties map[string]any, depth, maxDepth int) map[string]any {
+	values := make(map[string]any, len(properties))
+	for key, raw := range properties {
+		node, _ := raw.(map[string]any)
+		values[key] = helmValue(node, depth, maxDepth)
+	}
+	return values
+}
+
+func helmValue(node map[string]any, depth, maxDepth int) any {
+	for _, key := range []string{"default", "example"} {
+		if value, exists := node[key]; exists && value != nil {
+			return value
+		}
+	}
+	if enumValues, _ := node["enum"].([]any); len(enumValues) > 0 {
+		return enumValues[0]
+	}
+
+	switch asString(node["type"]) {
+	case "array":
+		return []any{}
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "string":
+		return ""
+	}
+	nestedProps, _ := node["properties"].(map[string]any)
+	if len(nestedProps) == 0 || depth >= maxDepth {
+		return map[string]any{}
+	}
+	return helmValuesFromProperties(nestedProps, depth+1, maxDepth)
+}
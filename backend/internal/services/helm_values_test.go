@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExportHelmValuesMirrorsSpecProperties(t *testing.T) {
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: demo.io
+  names:
+    kind: Cache
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                replicas:
+                  type: integer
+                  default: 3
+                tier:
+                  type: string
+                  enum: [small, large]
+                image:
+                  type: string
+                persistence:
+                  type: object
+                  properties:
+                    enabled:
+                      type: boolean
+                    size:
+                      type: string
+                      example: 10Gi
+                tolerations:
+                  type: array
+                  items:
+                    type: object
+`
+
+	output, err := NewCRDService(config.Config{}).ExportHelmValues(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var values map[string]any
+	if err := yaml.Unmarshal([]byte(output), &values); err != nil {
+		t.Fatalf("expected valid YAML, got %v:\n%s", err, output)
+	}
+	if len(values) != 5 {
+		t.Fatalf("expected top-level keys to mirror the 5 spec properties, got %v", values)
+	}
+	if values["replicas"] != 3 || values["tier"] != "small" || values["image"] != "" {
+		t.Fatalf("expected seeded scalar values, got %v", values)
+	}
+	persistence, _ := values["persistence"].(map[string]any)
+	if persistence["enabled"] != false || persistence["size"] != "10Gi" {
+		t.Fatalf("expected nested persistence values, got %v", values["persistence"])
+	}
+	if tolerations, ok := values["tolerations"].([]any); !ok || len(tolerations) != 0 {
+		t.Fatalf("expected an empty tolerations list, got %v", values["tolerations"])
+	}
+}
+
+func TestExportHelmValuesRejectsNonCRDInput(t *testing.T) {
+	if _, err := NewCRDService(config.Config{}).ExportHelmValues("apiVersion: v1\nkind: ConfigMap\n"); err == nil {
+		t.Fatalf("expected an error for non-CRD input")
+	}
+}
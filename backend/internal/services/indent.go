@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// blockScalarRegex matches a line that opens a literal or folded block
+// scalar, whose body keeps its own indentation and is skipped.
+var blockScalarRegex = regexp.MustCompile(`(^|:|-)\s*[|>][-+0-9]*\s*(#.*)?$`)
+
+// mixedIndentWarning reports when raw nests with more than one indentation
+// width, e.g. 2 spaces in one block and 4 in another. Each step is measured
+// from a line to the deeper line that follows it, with a "- " sequence
+// marker counted as part of the indentation so list items don't register as
+// a 2-space step in 4-space documents.
+func mixedIndentWarning(raw string) (string, bool) {
+	widths := make(map[int]int)
+	previous := -1
+	blockIndent := -1
+	for _, line := range strings.Split(raw, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.TrimSpace(trimmed) == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(trimmed)
+		if blockIndent >= 0 {
+			if indent > blockIndent {
+				continue
+			}
+			blockIndent = -1
+		}
+
+		if previous >= 0 && indent > previous {
+			widths[indent-previous]++
+		}
+
+		content := indent
+		for strings.HasPrefix(trimmed, "- ") {
+			trimmed = strings.TrimLeft(trimmed[1:], " ")
+			content = len(line) - len(trimmed)
+		}
+		previous = content
+		if blockScalarRegex.MatchString(strings.TrimRight(line, " \t")) {
+			blockIndent = indent
+		}
+	}
+
+	if len(widths) < 2 {
+		return "", false
+	}
+	sizes := make([]string, 0, len(widths))
+	for _, width := range sortedIntKeys(widths) {
+		sizes = append(sizes, fmt.Sprintf("%d", width))
+	}
+	return fmt.Sprintf("Document mixes indentation widths (%s spaces); use one width consistently.", strings.Join(sizes, ", ")), true
+}
+
+func sortedIntKeys(values map[int]int) []int {
+	keys := make([]int, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+)
+
+func TestValidateCRDWarnsAboutMixedIndentation(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	raw := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+    replicas: 2
+    template:
+        spec:
+            containers:
+                - name: web
+                  image: nginx
+`
+
+	result := service.ValidateCRD(raw)
+	if !result.Valid {
+		t.Fatalf("expected mixed indentation to stay a warning, got errors %v", result.Errors)
+	}
+	found := false
+	for _, warning := range result.Warnings {
+		if strings.Contains(warning, "mixes indentation widths (2, 4 spaces)") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected mixed indentation warning, got %v", result.Warnings)
+	}
+}
+
+func TestMixedIndentWarningIgnoresConsistentDocuments(t *testing.T) {
+	cases := map[string]string{
+		"two spaces with indented lists": "spec:\n  versions:\n    - name: v1\n      served: true\n",
+		"four spaces with lists":         "spec:\n    versions:\n        - name: v1\n          served: true\n",
+		"block scalar body":              "metadata:\n  annotations:\n    note: |\n        indented\n          freely\n  name: web\n",
+	}
+	for name, raw := range cases {
+		if warning, ok := mixedIndentWarning(raw); ok {
+			t.Fatalf("%s: expected no warning, got %q", name, warning)
+		}
+	}
+}
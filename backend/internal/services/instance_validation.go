@@ -0,0 +1,251 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// ValidateInstance checks a custom resource against the openAPIV3Schema of
+// its CRD, using the storage version's schema (or the legacy
+// spec.validation schema). It reports missing required fields, type
+// mismatches and enum violations by field path.
+func (s *CRDService) ValidateInstance(crdRaw string, instanceRaw string) models.ValidateCRDResponse {
+	result := models.ValidateCRDResponse{
+		Errors:   make([]string, 0),
+		Warnings: make([]string, 0),
+	}
+
+	crdDocs, err := decodeYAMLDocuments(strings.TrimSpace(normalizeLineEndings(crdRaw)))
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("CRD YAML parse error: %v", err))
+		return result
+	}
+	crd, ok := selectPrimaryResourceDoc(crdDocs)
+	if !ok || !strings.EqualFold(asString(crd["kind"]), "CustomResourceDefinition") {
+		result.Errors = append(result.Errors, "CRD payload does not contain a CustomResourceDefinition.")
+		return result
+	}
+	schema, version := selectRootSchema(crd)
+	if schema == nil {
+		result.Errors = append(result.Errors, "CRD has no openAPIV3Schema to validate against.")
+		return result
+	}
+
+	instanceDocs, err := decodeYAMLDocuments(strings.TrimSpace(normalizeLineEndings(instanceRaw)))
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("Instance YAML parse error: %v", err))
+		return result
+	}
+	instance, ok := selectPrimaryResourceDoc(instanceDocs)
+	if !ok || len(instance) == 0 {
+		result.Errors = append(result.Errors, "Instance payload has no valid resource documents.")
+		return result
+	}
+	result.Kind = asString(instance["kind"])
+	result.APIVersion = asString(instance["apiVersion"])
+
+	if kind := asString(nested(crd, "spec", "names", "kind")); kind != "" && result.Kind != kind {
+		result.Errors = append(result.Errors, fmt.Sprintf("kind: expected %s, got %q", kind, result.Kind))
+	}
+	group := asString(nested(crd, "spec", "group"))
+	if instanceGroup, _, _ := strings.Cut(result.APIVersion, "/"); group != "" && instanceGroup != group {
+		result.Errors = append(result.Errors, fmt.Sprintf("apiVersion: expected group %s, got %q", group, result.APIVersion))
+	} else if _, instanceVersion, _ := strings.Cut(result.APIVersion, "/"); version != "" && instanceVersion != version {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("apiVersion: validated against schema version %s, but the instance uses %q", version, result.APIVersion))
+	}
+
+	validateSchemaValue("", instance, schema, &result.Errors)
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// selectRootSchema returns the whole openAPIV3Schema of the storage version,
+// falling back to a served version, the first version with a schema, and
+// finally the legacy spec.validation location.
+func selectRootSchema(root map[string]any) (map[string]any, string) {
+	versions, _ := nested(root, "spec", "versions").([]any)
+	var served, first map[string]any
+	var servedName, firstName string
+	for _, entry := range versions {
+		versionMap, _ := entry.(map[string]any)
+		schema, _ := nested(versionMap, "schema", "openAPIV3Schema").(map[string]any)
+		if schema == nil {
+			continue
+		}
+		name := asString(versionMap["name"])
+		if asBool(versionMap["storage"]) {
+			return schema, name
+		}
+		if served == nil && asBool(versionMap["served"]) {
+			served, servedName = schema, name
+		}
+		if first == nil {
+			first, firstName = schema, name
+		}
+	}
+	if served != nil {
+		return served, servedName
+	}
+	if first != nil {
+		return first, firstName
+	}
+
+	legacy, _ := nested(root, "spec", "validation", "openAPIV3Schema").(map[string]any)
+	return legacy, asString(nested(root, "spec", "version"))
+}
+
+func validateSchemaValue(path string, value any, schema map[string]any, errs *[]string) {
+	if value == nil {
+		if !asBool(schema["nullable"]) && asString(schema["type"]) != "" {
+			*errs = append(*errs, fmt.Sprintf("%s: must not be null", displayPath(path)))
+		}
+		return
+	}
+
+	if expected := asString(schema["type"]); expected != "" && !matchesSchemaType(value, expected, asBool(schema["x-kubernetes-int-or-string"])) {
+		*errs = append(*errs, fmt.Sprintf("%s: expected %s, got %s", displayPath(path), expected, describeValueType(value)))
+		return
+	}
+
+	if allowed, _ := schema["enum"].([]any); len(allowed) > 0 {
+		actual := formatDefaultValue(value)
+		matched := false
+		options := make([]string, 0, len(allowed))
+		for _, option := range allowed {
+			formatted := formatDefaultValue(option)
+			options = append(options, formatted)
+			if formatted == actual {
+				matched = true
+			}
+		}
+		if !matched {
+			*errs = append(*errs, fmt.Sprintf("%s: %q is not one of %s", displayPath(path), actual, strings.Join(options, "|")))
+		}
+	}
+
+	validateSchemaConstraints(path, value, schema, errs)
+
+	switch typed := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		required := parseRequiredSet(schema["required"])
+		missing := make([]string, 0, len(required))
+		for key := range required {
+			if _, ok := typed[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+		sort.Strings(missing)
+		for _, key := range missing {
+			*errs = append(*errs, fmt.Sprintf("%s: required field is missing", joinDiffPath(path, key)))
+		}
+		additional, _ := schema["additionalProperties"].(map[string]any)
+		for _, key := range sortedKeys(typed) {
+			if child, ok := properties[key].(map[string]any); ok {
+				validateSchemaValue(joinDiffPath(path, key), typed[key], child, errs)
+			} else if additional != nil {
+				validateSchemaValue(joinDiffPath(path, key), typed[key], additional, errs)
+			}
+		}
+	case []any:
+		items, _ := schema["items"].(map[string]any)
+		if items == nil {
+			return
+		}
+		for i, item := range typed {
+			validateSchemaValue(fmt.Sprintf("%s[%d]", path, i), item, items, errs)
+		}
+	}
+}
+
+// validateSchemaConstraints enforces minimum/maximum on numbers and pattern on
+// strings. Unparseable patterns are skipped rather than reported against the
+// instance.
+func validateSchemaConstraints(path string, value any, schema map[string]any, errs *[]string) {
+	if number, ok := asNumber(value); ok {
+		if minimum, ok := asNumber(schema["minimum"]); ok {
+			if asBool(schema["exclusiveMinimum"]) && number <= minimum {
+				*errs = append(*errs, fmt.Sprintf("%s must be > %s", displayPath(path), formatDefaultValue(schema["minimum"])))
+			} else if number < minimum {
+				*errs = append(*errs, fmt.Sprintf("%s must be >= %s", displayPath(path), formatDefaultValue(schema["minimum"])))
+			}
+		}
+		if maximum, ok := asNumber(schema["maximum"]); ok {
+			if asBool(schema["exclusiveMaximum"]) && number >= maximum {
+				*errs = append(*errs, fmt.Sprintf("%s must be < %s", displayPath(path), formatDefaultValue(schema["maximum"])))
+			} else if number > maximum {
+				*errs = append(*errs, fmt.Sprintf("%s must be <= %s", displayPath(path), formatDefaultValue(schema["maximum"])))
+			}
+		}
+	}
+	if text, ok := value.(string); ok {
+		if pattern := asString(schema["pattern"]); pattern != "" {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(text) {
+				*errs = append(*errs, fmt.Sprintf("%s must match pattern %q", displayPath(path), pattern))
+			}
+		}
+	}
+}
+
+func matchesSchemaType(value any, expected string, intOrString bool) bool {
+	if intOrString {
+		switch value.(type) {
+		case string, int, int64:
+			return true
+		}
+	}
+	switch expected {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		switch typed := value.(type) {
+		case int, int64:
+			return true
+		case float64:
+			return typed == math.Trunc(typed)
+		}
+		return false
+	case "number":
+		switch value.(type) {
+		case int, int64, float64:
+			return true
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func describeValueType(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64:
+		return "integer"
+	case float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
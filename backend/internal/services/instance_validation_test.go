@@ -0,0 +1,181 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+const instanceTestCRD = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1beta1
+      served: true
+      storage: false
+      schema:
+        openAPIV3Schema:
+          type: object
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          required: [spec]
+          properties:
+            spec:
+              type: object
+              required: [size, replicas]
+              properties:
+                size:
+                  type: string
+                  enum: [small, large]
+                replicas:
+                  type: integer
+                port:
+                  x-kubernetes-int-or-string: true
+                ports:
+                  type: array
+                  items:
+                    type: object
+                    required: [port]
+                    properties:
+                      port:
+                        type: integer
+`
+
+func TestValidateInstanceReportsSchemaViolations(t *testing.T) {
+	service := NewCRDService(config.Config{})
+
+	valid := service.ValidateInstance(instanceTestCRD, `
+apiVersion: example.io/v1
+kind: Widget
+spec:
+  size: small
+  replicas: 2
+  port: http
+  ports:
+    - port: 80
+`)
+	if !valid.Valid {
+		t.Fatalf("expected conforming instance to be valid, got %+v", valid.Errors)
+	}
+
+	invalid := service.ValidateInstance(instanceTestCRD, `
+apiVersion: example.io/v1
+kind: Widget
+spec:
+  size: medium
+  replicas: "two"
+  ports:
+    - name: web
+`)
+	if invalid.Valid {
+		t.Fatalf("expected violations to make the instance invalid")
+	}
+	for _, expected := range []string{
+		`spec.size: "medium" is not one of small|large`,
+		"spec.replicas: expected integer, got string",
+		"spec.ports[0].port: required field is missing",
+	} {
+		if !slices.Contains(invalid.Errors, expected) {
+			t.Fatalf("expected error %q, got %v", expected, invalid.Errors)
+		}
+	}
+}
+
+func TestValidateInstanceUsesLegacyValidationSchema(t *testing.T) {
+	legacy := `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  version: v1
+  names:
+    kind: Widget
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          required: [size]
+          properties:
+            size:
+              type: string
+`
+	result := NewCRDService(config.Config{}).ValidateInstance(legacy, "apiVersion: example.io/v1\nkind: Gadget\nspec: {}\n")
+	if result.Valid {
+		t.Fatalf("expected legacy schema violations")
+	}
+	for _, expected := range []string{`kind: expected Widget, got "Gadget"`, "spec.size: required field is missing"} {
+		if !slices.Contains(result.Errors, expected) {
+			t.Fatalf("expected error %q, got %v", expected, result.Errors)
+		}
+	}
+}
+
+func TestSchemaConstraintsArePopulatedAndEnforced(t *testing.T) {
+	crd := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                replicas:
+                  type: integer
+                  minimum: 1
+                  maximum: 10
+                name:
+                  type: string
+                  pattern: "^[a-z]+$"
+                contact:
+                  type: string
+                  format: email
+`
+	service := NewCRDService(config.Config{})
+	fields, err := service.AllFields(crd)
+	if err != nil {
+		t.Fatalf("all fields: %v", err)
+	}
+	constraints := make(map[string]*models.FieldConstraints)
+	for _, field := range fields {
+		constraints[field.Path] = field.Constraints
+	}
+	if got := constraints["spec.replicas"]; got == nil || got.Minimum == nil || *got.Minimum != 1 || got.Maximum == nil || *got.Maximum != 10 {
+		t.Fatalf("expected replicas bounds 1..10, got %+v", got)
+	}
+	if got := constraints["spec.name"]; got == nil || got.Pattern != "^[a-z]+$" {
+		t.Fatalf("expected name pattern, got %+v", got)
+	}
+	if got := constraints["spec.contact"]; got == nil || got.Format != "email" || got.Minimum != nil {
+		t.Fatalf("expected contact format only, got %+v", got)
+	}
+
+	result := service.ValidateInstance(crd, "apiVersion: example.io/v1\nkind: Widget\nspec:\n  replicas: 12\n  name: Web-1\n")
+	for _, expected := range []string{"spec.replicas must be <= 10", `spec.name must match pattern "^[a-z]+$"`} {
+		if !slices.Contains(result.Errors, expected) {
+			t.Fatalf("expected error %q, got %v", expected, result.Errors)
+		}
+	}
+	if low := service.ValidateInstance(crd, "apiVersion: example.io/v1\nkind: Widget\nspec:\n  replicas: 0\n"); !slices.Contains(low.Errors, "spec.replicas must be >= 1") {
+		t.Fatalf("expected minimum violation, got %v", low.Errors)
+	}
+}
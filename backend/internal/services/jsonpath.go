@@ -0,0 +1,266 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var jsonPathIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// JSONPaths returns the JSONPath expressions addressing every field of a CRD
+// schema or pasted resource, in the form accepted by `kubectl get -o jsonpath`.
+// Array indexes are emitted as [*] so each expression selects all elements.
+func (s *CRDService) JSONPaths(raw string) ([]string, error) {
+	docs, err := decodeYAMLDocuments(strings.TrimSpace(raw))
+	if err == nil {
+		if root, ok := selectPrimaryResourceDoc(docs); ok && !strings.EqualFold(asString(root["kind"]), "CustomResourceDefinition") {
+			stripMap(root, nil, compileStripPolicy(s.stripPaths))
+			paths := make(map[string]struct{})
+			collectJSONPaths(nil, root, paths)
+			return sortedPathSet(paths), nil
+		}
+	}
+
+	fields, err := s.AllFields(raw)
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		paths[FieldPathToJSONPath(field.Path)] = struct{}{}
+	}
+	return sortedPathSet(paths), nil
+}
+
+// FieldPathToJSONPath converts a dotted field path such as
+// "spec.containers[0].image" into "$.spec.containers[*].image".
+func FieldPathToJSONPath(path string) string {
+	return formatJSONPath(parsePath(path))
+}
+
+func collectJSONPaths(prefix []any, value any, out map[string]struct{}) {
+	switch typed := value.(type) {
+	case map[string]any:
+		if len(typed) == 0 && len(prefix) > 0 {
+			out[formatJSONPath(prefix)] = struct{}{}
+		}
+		for key, child := range typed {
+			collectJSONPaths(append(prefix[:len(prefix):len(prefix)], key), child, out)
+		}
+	case []any:
+		if len(typed) == 0 {
+			out[formatJSONPath(prefix)] = struct{}{}
+		}
+		for _, item := range typed {
+			collectJSONPaths(append(prefix[:len(prefix):len(prefix)], 0), item, out)
+		}
+	default:
+		out[formatJSONPath(prefix)] = struct{}{}
+	}
+}
+
+func formatJSONPath(segments []any) string {
+	var builder strings.Builder
+	builder.WriteString("$")
+	for _, segment := range segments {
+		switch typed := segment.(type) {
+		case int:
+			builder.WriteString("[*]")
+		case string:
+			if jsonPathIdentifierRegex.MatchString(typed) {
+				builder.WriteString("." + typed)
+				continue
+			}
+			builder.WriteString("['" + strings.ReplaceAll(typed, "'", `\'`) + "']")
+		}
+	}
+	return builder.String()
+}
+
+func sortedPathSet(paths map[string]struct{}) []string {
+	out := make([]string, 0, len(paths))
+	for path := range paths {
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ValidateJSONPathSyntax checks that expr is a syntactically valid JSONPath
+// of the kind used by CRD printer columns, e.g. ".status.replicas" or
+// `.status.conditions[?(@.type=="Ready")].status`. It does not evaluate the
+// expression against a schema.
+func ValidateJSONPathSyntax(expr string) error {
+	trimmed := strings.TrimSpace(expr)
+	if trimmed == "" {
+		return errors.New("expression is empty")
+	}
+	rest := strings.TrimPrefix(trimmed, "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		return fmt.Errorf("expression must start with '.', got %q", rest[:1])
+	}
+
+	for i := 0; i < len(rest); {
+		switch rest[i] {
+		case '.':
+			start := i + 1
+			end := start
+			for end < len(rest) && rest[end] != '.' && rest[end] != '[' {
+				end++
+			}
+			name := rest[start:end]
+			if name == "" {
+				return fmt.Errorf("empty field name at offset %d", start)
+			}
+			if name != "*" && !jsonPathIdentifierRegex.MatchString(name) {
+				return fmt.Errorf("invalid field name %q", name)
+			}
+			i = end
+		case '[':
+			end, err := jsonPathBracketEnd(rest, i)
+			if err != nil {
+				return err
+			}
+			if err := validateJSONPathSubscript(rest[i+1 : end]); err != nil {
+				return err
+			}
+			i = end + 1
+		default:
+			return fmt.Errorf("unexpected character %q at offset %d", rest[i], i)
+		}
+	}
+	return nil
+}
+
+// jsonPathBracketEnd returns the index of the ']' closing the '[' at start,
+// skipping over quoted strings and nested brackets inside filters.
+func jsonPathBracketEnd(expr string, start int) (int, error) {
+	depth := 0
+	var quote byte
+	for i := start; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	if quote != 0 {
+		return 0, fmt.Errorf("unterminated string at offset %d", start)
+	}
+	return 0, fmt.Errorf("unclosed '[' at offset %d", start)
+}
+
+func validateJSONPathSubscript(subscript string) error {
+	subscript = strings.TrimSpace(subscript)
+	switch {
+	case subscript == "":
+		return errors.New("empty subscript []")
+	case subscript == "*":
+		return nil
+	case strings.HasPrefix(subscript, "?"):
+		filter := strings.TrimSpace(subscript[1:])
+		if !strings.HasPrefix(filter, "(") || !strings.HasSuffix(filter, ")") || strings.TrimSpace(filter[1:len(filter)-1]) == "" {
+			return fmt.Errorf("filter %q must have the form ?(<expression>)", subscript)
+		}
+		if !balancedJSONPathParens(filter) {
+			return fmt.Errorf("unbalanced parentheses in filter %q", subscript)
+		}
+		return nil
+	case subscript[0] == '\'' || subscript[0] == '"':
+		if len(subscript) < 2 || subscript[len(subscript)-1] != subscript[0] {
+			return fmt.Errorf("unterminated string in subscript [%s]", subscript)
+		}
+		return nil
+	}
+
+	// Indexes, unions and slices: [0], [0,2], [1:3], [-1:], [::2].
+	for _, part := range strings.Split(subscript, ",") {
+		bounds := strings.Split(part, ":")
+		if len(bounds) > 3 {
+			return fmt.Errorf("invalid slice [%s]", subscript)
+		}
+		for _, bound := range bounds {
+			bound = strings.TrimSpace(bound)
+			if bound == "" && len(bounds) > 1 {
+				continue
+			}
+			if _, err := strconv.Atoi(bound); err != nil {
+				return fmt.Errorf("invalid subscript [%s]", subscript)
+			}
+		}
+	}
+	return nil
+}
+
+func balancedJSONPathParens(expr string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0 && quote == 0
+}
+
+// printerColumnWarnings reports additionalPrinterColumns whose JSONPath does
+// not parse. Such columns are accepted by the API server but print nothing.
+func printerColumnWarnings(specMap map[string]any) []string {
+	warnings := make([]string, 0)
+	check := func(prefix string, columns []any, key string) {
+		for i, entry := range columns {
+			column, _ := entry.(map[string]any)
+			expr, ok := column[key].(string)
+			if !ok {
+				continue
+			}
+			if err := ValidateJSONPathSyntax(expr); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s.additionalPrinterColumns[%d].%s: invalid JSONPath %q: %v", prefix, i, key, expr, err))
+			}
+		}
+	}
+
+	// apiextensions.k8s.io/v1beta1 declared columns once for all versions
+	// and spelled the key JSONPath.
+	legacy, _ := specMap["additionalPrinterColumns"].([]any)
+	check("spec", legacy, "JSONPath")
+	versions, _ := specMap["versions"].([]any)
+	for i, entry := range versions {
+		versionMap, _ := entry.(map[string]any)
+		columns, _ := versionMap["additionalPrinterColumns"].([]any)
+		check(fmt.Sprintf("spec.versions[%d]", i), columns, "jsonPath")
+	}
+	return warnings
+}
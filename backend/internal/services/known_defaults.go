@@ -0,0 +1,62 @@
+package services
+
+// knownDefault is a value the API server fills in when a field is omitted.
+type knownDefault struct {
+	Path  string
+	Value any
+}
+
+// knownDefaults lists common server-side defaults for built-in kinds, keyed by
+// "apiVersion/Kind". It intentionally covers only scalar fields at fixed paths
+// so applying it never has to guess at list lengths.
+var knownDefaults = map[string][]knownDefault{
+	"v1/Service": {
+		{Path: "spec.type", Value: "ClusterIP"},
+		{Path: "spec.sessionAffinity", Value: "None"},
+	},
+	"apps/v1/Deployment": {
+		{Path: "spec.replicas", Value: 1},
+		{Path: "spec.revisionHistoryLimit", Value: 10},
+		{Path: "spec.progressDeadlineSeconds", Value: 600},
+		{Path: "spec.strategy.type", Value: "RollingUpdate"},
+		{Path: "spec.template.spec.restartPolicy", Value: "Always"},
+		{Path: "spec.template.spec.dnsPolicy", Value: "ClusterFirst"},
+		{Path: "spec.template.spec.terminationGracePeriodSeconds", Value: 30},
+	},
+	"apps/v1/StatefulSet": {
+		{Path: "spec.replicas", Value: 1},
+		{Path: "spec.podManagementPolicy", Value: "OrderedReady"},
+		{Path: "spec.updateStrategy.type", Value: "RollingUpdate"},
+		{Path: "spec.template.spec.restartPolicy", Value: "Always"},
+	},
+	"apps/v1/DaemonSet": {
+		{Path: "spec.updateStrategy.type", Value: "RollingUpdate"},
+		{Path: "spec.template.spec.restartPolicy", Value: "Always"},
+	},
+	"batch/v1/Job": {
+		{Path: "spec.backoffLimit", Value: 6},
+		{Path: "spec.completions", Value: 1},
+		{Path: "spec.parallelism", Value: 1},
+	},
+	"batch/v1/CronJob": {
+		{Path: "spec.concurrencyPolicy", Value: "Allow"},
+		{Path: "spec.suspend", Value: false},
+		{Path: "spec.successfulJobsHistoryLimit", Value: 3},
+		{Path: "spec.failedJobsHistoryLimit", Value: 1},
+	},
+	"v1/PersistentVolumeClaim": {
+		{Path: "spec.volumeMode", Value: "Filesystem"},
+	},
+}
+
+// applyKnownDefaults fills the defaults for the resource's kind into any path
+// that is still unset. Unrecognized kinds are left untouched.
+func applyKnownDefaults(resource map[string]any, apiVersion, kind string) {
+	for _, item := range knownDefaults[apiVersion+"/"+kind] {
+		segments := parsePath(item.Path)
+		if lookupPath(resource, segments) != nil {
+			continue
+		}
+		setValue(resource, segments, item.Value)
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// KustomizationFile is the name of the generated Kustomize entry point.
+const KustomizationFile = "kustomization.yaml"
+
+// GenerateKustomize renders each request as its own file and adds a
+// kustomization.yaml listing them under resources, in request order. Files
+// are named "<kind>-<metadata.name>.yaml" (lowercased, see ManifestFilename),
+// with a numeric suffix when two resources would share a name. Requests with
+// no apiVersion, kind or fields are skipped as in GenerateMultiYAML.
+func (s *YAMLService) GenerateKustomize(docs []models.GenerateYAMLRequest) (map[string]string, error) {
+	files := make(map[string]string, len(docs)+1)
+	files[KustomizationFile] = ""
+	resources := make([]any, 0, len(docs))
+	for i, doc := range docs {
+		if isEmptyGenerateRequest(doc) {
+			continue
+		}
+		output, object, err := s.GenerateYAMLWithObject(doc)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+
+		base := strings.TrimSpace(doc.Kind)
+		if name := asString(nested(object, "metadata", "name")); name != "" {
+			base += "-" + name
+		}
+		filename := ManifestFilename(base)
+		for n := 2; ; n++ {
+			if _, taken := files[filename]; !taken {
+				break
+			}
+			filename = ManifestFilename(fmt.Sprintf("%s-%d", base, n))
+		}
+		files[filename] = output
+		resources = append(resources, filename)
+	}
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("at least one document is required")
+	}
+
+	node, err := resourceNode(map[string]any{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  resources,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal YAML: %w", err)
+	}
+	kustomization, err := marshalNode(node)
+	if err != nil {
+		return nil, err
+	}
+	files[KustomizationFile] = kustomization
+	return files, nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateKustomizeWritesResourcesAndKustomization(t *testing.T) {
+	files, err := NewYAMLService().GenerateKustomize([]models.GenerateYAMLRequest{
+		{APIVersion: "apps/v1", Kind: "Deployment", Fields: []models.FieldDefinition{{Path: "metadata.name", Value: "web"}}},
+		{},
+		{APIVersion: "v1", Kind: "Service", Fields: []models.FieldDefinition{{Path: "metadata.name", Value: "web"}}},
+		{APIVersion: "v1", Kind: "Service", Fields: []models.FieldDefinition{{Path: "metadata.name", Value: "web"}}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []string{"deployment-web.yaml", "service-web.yaml", "service-web-2.yaml"}
+	for _, name := range want {
+		if !strings.Contains(files[name], "name: web") {
+			t.Fatalf("expected %s to hold the generated resource, got files %v", name, files)
+		}
+	}
+	if len(files) != len(want)+1 {
+		t.Fatalf("expected %d files, got %v", len(want)+1, files)
+	}
+
+	var kustomization struct {
+		APIVersion string   `yaml:"apiVersion"`
+		Kind       string   `yaml:"kind"`
+		Resources  []string `yaml:"resources"`
+	}
+	if err := yaml.Unmarshal([]byte(files[KustomizationFile]), &kustomization); err != nil {
+		t.Fatalf("decode kustomization: %v", err)
+	}
+	if kustomization.Kind != "Kustomization" || kustomization.APIVersion != "kustomize.config.k8s.io/v1beta1" {
+		t.Fatalf("unexpected kustomization header: %+v", kustomization)
+	}
+	if !slices.Equal(kustomization.Resources, want) {
+		t.Fatalf("expected resources %v in request order, got %v", want, kustomization.Resources)
+	}
+}
+
+func TestGenerateKustomizeRequiresADocument(t *testing.T) {
+	if _, err := NewYAMLService().GenerateKustomize([]models.GenerateYAMLRequest{{}}); err == nil {
+		t.Fatalf("expected an error without documents")
+	}
+}
@@ -2,7 +2,13 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -16,20 +22,33 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
+var ErrManifestNotFound = errors.New("manifest not found")
+
+// memoryManifestCap is how many records the in-memory fallback retains.
+const memoryManifestCap = 200
+
 type ManifestService struct {
 	client     *mongo.Client
 	collection *mongo.Collection
 	mu         sync.RWMutex
 	memory     []models.ManifestRecord
+	ttl        time.Duration
+	stopExpiry chan struct{}
+	limiter    opLimiter
+	// now is the clock used for timestamps and expiry; nil means time.Now.
+	now func() time.Time
 }
 
 func NewManifestService(ctx context.Context, cfg config.Config) (*ManifestService, error) {
 	service := &ManifestService{
-		memory: make([]models.ManifestRecord, 0, 64),
+		memory:  make([]models.ManifestRecord, 0, 64),
+		ttl:     cfg.ManifestTTL,
+		limiter: newOpLimiter(cfg.MongoMaxConcurrentOps),
 	}
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+	client, err := mongo.Connect(ctx, mongoClientOptions(cfg))
 	if err != nil {
+		service.startMemoryExpiry()
 		return service, fmt.Errorf("connect mongodb: %w", err)
 	}
 
@@ -37,6 +56,7 @@ func NewManifestService(ctx context.Context, cfg config.Config) (*ManifestServic
 	defer cancel()
 	if err := client.Ping(pingCtx, readpref.Primary()); err != nil {
 		_ = client.Disconnect(context.Background())
+		service.startMemoryExpiry()
 		return service, fmt.Errorf("ping mongodb: %w", err)
 	}
 
@@ -47,6 +67,9 @@ func NewManifestService(ctx context.Context, cfg config.Config) (*ManifestServic
 	_, _ = collection.Indexes().CreateOne(indexCtx, mongo.IndexModel{
 		Keys: bson.D{{Key: "createdAt", Value: -1}},
 	})
+	if err := reconcileManifestTTLIndex(indexCtx, collection, cfg.ManifestTTL); err != nil {
+		log.Printf("reconcile manifest TTL index: %v", err)
+	}
 
 	service.client = client
 	service.collection = collection
@@ -54,8 +77,98 @@ func NewManifestService(ctx context.Context, cfg config.Config) (*ManifestServic
 	return service, nil
 }
 
+// manifestTTLIndexName names the createdAt index MongoDB uses to expire
+// manifests.
+const manifestTTLIndexName = "createdAt_ttl"
+
+// reconcileManifestTTLIndex makes the TTL index match ttl: it is created when
+// missing, its expiry is updated in place with collMod when ttl changed, and
+// it is dropped when ttl is zero so documents stop expiring.
+func reconcileManifestTTLIndex(ctx context.Context, collection *mongo.Collection, ttl time.Duration) error {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return fmt.Errorf("list indexes: %w", err)
+	}
+	var indexes []bson.M
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return fmt.Errorf("list indexes: %w", err)
+	}
+	var existing bson.M
+	for _, index := range indexes {
+		if index["name"] == manifestTTLIndexName {
+			existing = index
+			break
+		}
+	}
+
+	if ttl <= 0 {
+		if existing == nil {
+			return nil
+		}
+		if _, err := collection.Indexes().DropOne(ctx, manifestTTLIndexName); err != nil {
+			return fmt.Errorf("drop TTL index: %w", err)
+		}
+		return nil
+	}
+
+	seconds := manifestTTLSeconds(ttl)
+	if existing == nil {
+		if _, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName(manifestTTLIndexName).SetExpireAfterSeconds(seconds),
+		}); err != nil {
+			return fmt.Errorf("create TTL index: %w", err)
+		}
+		return nil
+	}
+	if current, ok := bsonInt64(existing["expireAfterSeconds"]); ok && current == int64(seconds) {
+		return nil
+	}
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: collection.Name()},
+		{Key: "index", Value: bson.D{
+			{Key: "name", Value: manifestTTLIndexName},
+			{Key: "expireAfterSeconds", Value: seconds},
+		}},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("update TTL index: %w", err)
+	}
+	return nil
+}
+
+// manifestTTLSeconds converts ttl to the whole seconds MongoDB expects,
+// clamped to at least one second and at most what an int32 holds.
+func manifestTTLSeconds(ttl time.Duration) int32 {
+	seconds := ttl / time.Second
+	if seconds > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int32(max(seconds, 1))
+}
+
+func bsonInt64(value any) (int64, bool) {
+	switch typed := value.(type) {
+	case int32:
+		return int64(typed), true
+	case int64:
+		return typed, true
+	case float64:
+		return int64(typed), true
+	default:
+		return 0, false
+	}
+}
+
 func (s *ManifestService) Close(ctx context.Context) error {
-	if s == nil || s.client == nil {
+	if s == nil {
+		return nil
+	}
+	if s.stopExpiry != nil {
+		close(s.stopExpiry)
+		s.stopExpiry = nil
+	}
+	if s.client == nil {
 		return nil
 	}
 	return s.client.Disconnect(ctx)
@@ -66,7 +179,7 @@ func (s *ManifestService) SaveManifest(ctx context.Context, req models.SaveManif
 		return models.ManifestRecord{}, fmt.Errorf("yaml is required")
 	}
 
-	now := time.Now().UTC()
+	now := s.clock()
 	record := models.ManifestRecord{
 		ID:         primitive.NewObjectID().Hex(),
 		Title:      fallback(req.Title, "Manifest"),
@@ -74,96 +187,509 @@ func (s *ManifestService) SaveManifest(ctx context.Context, req models.SaveManif
 		APIVersion: strings.TrimSpace(req.APIVersion),
 		Kind:       strings.TrimSpace(req.Kind),
 		YAML:       req.YAML,
+		Note:       strings.TrimSpace(req.Note),
+		Tags:       normalizeTags(req.Tags),
 		CreatedAt:  now,
 		UpdatedAt:  now,
+		SourceRaw:  req.SourceRaw,
+		Overrides:  req.Overrides,
 	}
 
 	if s.collection == nil {
 		s.mu.Lock()
 		defer s.mu.Unlock()
 		s.memory = append([]models.ManifestRecord{record}, s.memory...)
-		if len(s.memory) > 200 {
-			s.memory = s.memory[:200]
+		if len(s.memory) > memoryManifestCap {
+			s.memory = s.memory[:memoryManifestCap]
 		}
 		return record, nil
 	}
 
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return models.ManifestRecord{}, err
+	}
+	defer release()
+
 	if _, err := s.collection.InsertOne(ctx, record); err != nil {
 		return models.ManifestRecord{}, fmt.Errorf("insert manifest: %w", err)
 	}
 	return record, nil
 }
 
-func (s *ManifestService) ListManifests(ctx context.Context, query string, limit int64) ([]models.ManifestRecord, error) {
-	if limit <= 0 || limit > 200 {
-		limit = 50
-	}
+// ListManifests returns one page of manifests matching query and carrying
+// every tag in tags, newest first, together with the total number of matches.
+func (s *ManifestService) ListManifests(ctx context.Context, query string, tags []string, limit, offset int64) (models.ManifestPage, error) {
+	limit = normalizeManifestLimit(limit)
+	offset = max(offset, 0)
+	page := models.ManifestPage{Items: make([]models.ManifestRecord, 0), Limit: limit, Offset: offset}
 
 	if s.collection == nil {
 		s.mu.RLock()
 		defer s.mu.RUnlock()
 
-		if query == "" {
-			if int64(len(s.memory)) <= limit {
-				return append([]models.ManifestRecord(nil), s.memory...), nil
-			}
-			return append([]models.ManifestRecord(nil), s.memory[:limit]...), nil
-		}
-
 		lowerQuery := strings.ToLower(strings.TrimSpace(query))
-		out := make([]models.ManifestRecord, 0, limit)
 		for _, item := range s.memory {
-			if matchesManifestQuery(item, lowerQuery) {
-				out = append(out, item)
+			if lowerQuery != "" && !matchesManifestQuery(item, lowerQuery) {
+				continue
+			}
+			if !hasAllTags(item.Tags, tags) {
+				continue
 			}
-			if int64(len(out)) >= limit {
-				break
+			if page.Total >= offset && int64(len(page.Items)) < limit {
+				page.Items = append(page.Items, item)
 			}
+			page.Total++
 		}
+		return page, nil
+	}
 
-		return out, nil
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return models.ManifestPage{}, err
 	}
+	defer release()
 
-	filter := bson.M{}
-	trimmed := strings.TrimSpace(query)
-	if trimmed != "" {
-		regex := bson.M{"$regex": trimmed, "$options": "i"}
-		filter = bson.M{
-			"$or": []bson.M{
-				{"title": regex},
-				{"resource": regex},
-				{"kind": regex},
-				{"apiVersion": regex},
-				{"yaml": regex},
-			},
-		}
+	filter := manifestQueryFilter(query)
+	if tags = normalizeTags(tags); len(tags) > 0 {
+		filter = bson.M{"$and": bson.A{filter, bson.M{"tags": bson.M{"$all": tags}}}}
 	}
+	total, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return models.ManifestPage{}, fmt.Errorf("count manifests: %w", err)
+	}
+	page.Total = total
 
 	cursor, err := s.collection.Find(
 		ctx,
 		filter,
 		options.Find().
 			SetSort(bson.D{{Key: "createdAt", Value: -1}}).
-			SetLimit(limit),
+			SetSkip(offset).
+			SetLimit(limit).
+			SetProjection(bson.M{"sourceRaw": 0, "overrides": 0}),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("list manifests: %w", err)
+		return models.ManifestPage{}, fmt.Errorf("list manifests: %w", err)
 	}
 	defer cursor.Close(ctx)
 
-	out := make([]models.ManifestRecord, 0)
 	for cursor.Next(ctx) {
 		var item models.ManifestRecord
 		if err := cursor.Decode(&item); err != nil {
-			return nil, fmt.Errorf("decode manifest: %w", err)
+			return models.ManifestPage{}, fmt.Errorf("decode manifest: %w", err)
 		}
-		out = append(out, item)
+		page.Items = append(page.Items, item)
 	}
 	if err := cursor.Err(); err != nil {
-		return nil, fmt.Errorf("manifest cursor: %w", err)
+		return models.ManifestPage{}, fmt.Errorf("manifest cursor: %w", err)
 	}
 
-	return out, nil
+	return page, nil
+}
+
+// Ping checks that MongoDB is reachable. It returns ErrMongoUnavailable when
+// the service is running on its in-memory fallback.
+func (s *ManifestService) Ping(ctx context.Context) error {
+	return pingMongo(ctx, s.client, s.limiter)
+}
+
+func (s *ManifestService) Count(ctx context.Context) (int64, error) {
+	if s.collection == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return int64(len(s.memory)), nil
+	}
+
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	count, err := s.collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("count manifests: %w", err)
+	}
+	return count, nil
+}
+
+func (s *ManifestService) GetManifest(ctx context.Context, id string) (models.ManifestRecord, error) {
+	id = strings.TrimSpace(id)
+
+	if s.collection == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		for _, item := range s.memory {
+			if item.ID == id {
+				return item, nil
+			}
+		}
+		return models.ManifestRecord{}, ErrManifestNotFound
+	}
+
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return models.ManifestRecord{}, err
+	}
+	defer release()
+
+	var record models.ManifestRecord
+	err = s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&record)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.ManifestRecord{}, ErrManifestNotFound
+	}
+	if err != nil {
+		return models.ManifestRecord{}, fmt.Errorf("get manifest: %w", err)
+	}
+	return record, nil
+}
+
+func (s *ManifestService) DeleteManifest(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+
+	if s.collection == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i := range s.memory {
+			if s.memory[i].ID == id {
+				s.memory = append(s.memory[:i], s.memory[i+1:]...)
+				return nil
+			}
+		}
+		return ErrManifestNotFound
+	}
+
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	result, err := s.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("delete manifest: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrManifestNotFound
+	}
+	return nil
+}
+
+// UpdateManifest overwrites a saved manifest's YAML and descriptive fields in
+// place, bumping UpdatedAt. CreatedAt and the note are left untouched; the
+// note has its own endpoint. The CRD source and overrides are only replaced
+// when given.
+func (s *ManifestService) UpdateManifest(ctx context.Context, id string, req models.SaveManifestRequest) (models.ManifestRecord, error) {
+	if strings.TrimSpace(req.YAML) == "" {
+		return models.ManifestRecord{}, fmt.Errorf("yaml is required")
+	}
+	id = strings.TrimSpace(id)
+	title := fallback(req.Title, "Manifest")
+	resource := strings.TrimSpace(req.Resource)
+	apiVersion := strings.TrimSpace(req.APIVersion)
+	kind := strings.TrimSpace(req.Kind)
+	now := s.clock()
+
+	if s.collection == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i := range s.memory {
+			if s.memory[i].ID != id {
+				continue
+			}
+			s.memory[i].Title = title
+			s.memory[i].Resource = resource
+			s.memory[i].APIVersion = apiVersion
+			s.memory[i].Kind = kind
+			s.memory[i].YAML = req.YAML
+			s.memory[i].UpdatedAt = now
+			if strings.TrimSpace(req.SourceRaw) != "" {
+				s.memory[i].SourceRaw = req.SourceRaw
+			}
+			if len(req.Overrides) > 0 {
+				s.memory[i].Overrides = req.Overrides
+			}
+			return s.memory[i], nil
+		}
+		return models.ManifestRecord{}, ErrManifestNotFound
+	}
+
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return models.ManifestRecord{}, err
+	}
+	defer release()
+
+	set := bson.M{
+		"title":      title,
+		"resource":   resource,
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"yaml":       req.YAML,
+		"updatedAt":  now,
+	}
+	if strings.TrimSpace(req.SourceRaw) != "" {
+		set["sourceRaw"] = req.SourceRaw
+	}
+	if len(req.Overrides) > 0 {
+		set["overrides"] = req.Overrides
+	}
+
+	var record models.ManifestRecord
+	err = s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&record)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.ManifestRecord{}, ErrManifestNotFound
+	}
+	if err != nil {
+		return models.ManifestRecord{}, fmt.Errorf("update manifest: %w", err)
+	}
+	return record, nil
+}
+
+// BulkTag adds and removes tags on every manifest selected by id, or by the
+// search query when no ids are given, and returns how many were matched.
+func (s *ManifestService) BulkTag(ctx context.Context, req models.BulkTagManifestsRequest) (int64, error) {
+	ids, add, remove, err := normalizeBulkTagRequest(req)
+	if err != nil {
+		return 0, err
+	}
+	now := s.clock()
+
+	if s.collection == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		selected := make(map[string]struct{}, len(ids))
+		for _, id := range ids {
+			selected[id] = struct{}{}
+		}
+		lowerQuery := strings.ToLower(strings.TrimSpace(req.Query))
+
+		var updated int64
+		for i := range s.memory {
+			if len(ids) > 0 {
+				if _, ok := selected[s.memory[i].ID]; !ok {
+					continue
+				}
+			} else if !matchesManifestQuery(s.memory[i], lowerQuery) {
+				continue
+			}
+			s.memory[i].Tags = applyTagChanges(s.memory[i].Tags, add, remove)
+			s.memory[i].UpdatedAt = now
+			updated++
+		}
+		return updated, nil
+	}
+
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	filter := bulkTagQueryFilter(req.Query)
+	if len(ids) > 0 {
+		filter = bson.M{"_id": bson.M{"$in": ids}}
+	}
+	// An update pipeline lets one UpdateMany both add and remove tags, which
+	// $addToSet and $pull on the same field cannot.
+	pipeline := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"tags": bson.M{"$setDifference": bson.A{
+			bson.M{"$setUnion": bson.A{bson.M{"$ifNull": bson.A{"$tags", bson.A{}}}, add}},
+			remove,
+		}},
+		"updatedAt": now,
+	}}}}
+	result, err := s.collection.UpdateMany(ctx, filter, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("bulk tag manifests: %w", err)
+	}
+	return result.MatchedCount, nil
+}
+
+func (s *ManifestService) UpdateNote(ctx context.Context, id string, note string) (models.ManifestRecord, error) {
+	id = strings.TrimSpace(id)
+	note = strings.TrimSpace(note)
+	now := s.clock()
+
+	if s.collection == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i := range s.memory {
+			if s.memory[i].ID != id {
+				continue
+			}
+			s.memory[i].Note = note
+			s.memory[i].UpdatedAt = now
+			return s.memory[i], nil
+		}
+		return models.ManifestRecord{}, ErrManifestNotFound
+	}
+
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return models.ManifestRecord{}, err
+	}
+	defer release()
+
+	var record models.ManifestRecord
+	err = s.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"note": note, "updatedAt": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&record)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.ManifestRecord{}, ErrManifestNotFound
+	}
+	if err != nil {
+		return models.ManifestRecord{}, fmt.Errorf("update manifest note: %w", err)
+	}
+	return record, nil
+}
+
+// ManifestFilename derives a download filename from a manifest title: the
+// title lowercased, with every run of characters outside a-z and 0-9 replaced
+// by one dash, plus ".yaml".
+func ManifestFilename(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		slug = "manifest"
+	}
+	return slug + ".yaml"
+}
+
+// clock returns the current UTC time from s.now, or time.Now when unset.
+func (s *ManifestService) clock() time.Time {
+	if s.now != nil {
+		return s.now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// startMemoryExpiry evicts in-memory records older than the TTL in the
+// background. MongoDB handles expiry itself through the TTL index.
+func (s *ManifestService) startMemoryExpiry() {
+	if s.ttl <= 0 || s.stopExpiry != nil {
+		return
+	}
+
+	interval := min(max(s.ttl/2, time.Millisecond), time.Minute)
+	stop := make(chan struct{})
+	s.stopExpiry = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				s.evictExpired(s.clock())
+			}
+		}
+	}()
+}
+
+func (s *ManifestService) evictExpired(now time.Time) {
+	cutoff := now.Add(-s.ttl)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.memory[:0]
+	for _, item := range s.memory {
+		if item.CreatedAt.After(cutoff) {
+			kept = append(kept, item)
+		}
+	}
+	clear(s.memory[len(kept):])
+	s.memory = kept
+}
+
+// Compact cleans up the in-memory fallback: records past the TTL are dropped
+// and the list is trimmed to the memory cap. With req.CollapseDuplicates,
+// older copies of a record identical in every stored field but its ID and
+// timestamps are dropped too. The IDs of every removed record are reported.
+// MongoDB enforces expiry through its TTL index, so compaction is a no-op
+// there.
+func (s *ManifestService) Compact(ctx context.Context, req models.CompactManifestsRequest) (models.CompactManifestsResponse, error) {
+	if s.collection != nil {
+		count, err := s.Count(ctx)
+		if err != nil {
+			return models.CompactManifestsResponse{}, err
+		}
+		return models.CompactManifestsResponse{Mode: "mongo", Remaining: count, RemovedIDs: []string{}}, nil
+	}
+
+	cutoff := s.clock().Add(-s.ttl)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := models.CompactManifestsResponse{Mode: "memory", RemovedIDs: make([]string, 0)}
+	kept := make([]models.ManifestRecord, 0, len(s.memory))
+	for _, item := range s.memory {
+		if s.ttl > 0 && !item.CreatedAt.After(cutoff) {
+			result.Expired++
+			result.RemovedIDs = append(result.RemovedIDs, item.ID)
+			continue
+		}
+		// Records are kept newest first, so an identical earlier entry in
+		// kept is the newer copy.
+		if req.CollapseDuplicates && slices.ContainsFunc(kept, func(newer models.ManifestRecord) bool {
+			return sameStoredManifest(newer, item)
+		}) {
+			result.Duplicates++
+			result.RemovedIDs = append(result.RemovedIDs, item.ID)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	if len(kept) > memoryManifestCap {
+		result.Trimmed = len(kept) - memoryManifestCap
+		for _, item := range kept[memoryManifestCap:] {
+			result.RemovedIDs = append(result.RemovedIDs, item.ID)
+		}
+		kept = kept[:memoryManifestCap]
+	}
+
+	s.memory = kept
+	result.Remaining = int64(len(kept))
+	return result, nil
+}
+
+// sameStoredManifest reports whether two records hold the same content,
+// ignoring their IDs and timestamps.
+func sameStoredManifest(left, right models.ManifestRecord) bool {
+	left.ID, left.CreatedAt, left.UpdatedAt = "", time.Time{}, time.Time{}
+	right.ID, right.CreatedAt, right.UpdatedAt = "", time.Time{}, time.Time{}
+	return reflect.DeepEqual(left, right)
+}
+
+func normalizeManifestLimit(limit int64) int64 {
+	if limit <= 0 || limit > 200 {
+		return 50
+	}
+	return limit
 }
 
 func fallback(value string, defaultValue string) string {
@@ -174,10 +700,36 @@ func fallback(value string, defaultValue string) string {
 	return trimmed
 }
 
+func manifestQueryFilter(query string) bson.M {
+	trimmed := strings.TrimSpace(query)
+	if trimmed == "" {
+		return bson.M{}
+	}
+	regex := bson.M{"$regex": trimmed, "$options": "i"}
+	return bson.M{
+		"$or": []bson.M{
+			{"title": regex},
+			{"resource": regex},
+			{"kind": regex},
+			{"apiVersion": regex},
+			{"yaml": regex},
+			{"note": regex},
+		},
+	}
+}
+
+// bulkTagQueryFilter is manifestQueryFilter with the query escaped, so a
+// bulk tag selects the same literal substring matches as the in-memory and
+// SQLite stores instead of treating "." or ".*" as a pattern.
+func bulkTagQueryFilter(query string) bson.M {
+	return manifestQueryFilter(regexp.QuoteMeta(strings.TrimSpace(query)))
+}
+
 func matchesManifestQuery(item models.ManifestRecord, lowerQuery string) bool {
 	return strings.Contains(strings.ToLower(item.Title), lowerQuery) ||
 		strings.Contains(strings.ToLower(item.Resource), lowerQuery) ||
 		strings.Contains(strings.ToLower(item.Kind), lowerQuery) ||
 		strings.Contains(strings.ToLower(item.APIVersion), lowerQuery) ||
-		strings.Contains(strings.ToLower(item.YAML), lowerQuery)
+		strings.Contains(strings.ToLower(item.YAML), lowerQuery) ||
+		strings.Contains(strings.ToLower(item.Note), lowerQuery)
 }
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestManifestNoteIsSavedReturnedAndSearchable(t *testing.T) {
+	service := &ManifestService{}
+	ctx := context.Background()
+
+	saved, err := service.SaveManifest(ctx, models.SaveManifestRequest{
+		Title: "web",
+		Kind:  "Deployment",
+		YAML:  "kind: Deployment\n",
+		Note:  "Canary rollout for checkout",
+	})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	if saved.Note != "Canary rollout for checkout" {
+		t.Fatalf("expected note to be saved, got %q", saved.Note)
+	}
+	if _, err := service.SaveManifest(ctx, models.SaveManifestRequest{Title: "other", YAML: "kind: Service\n"}); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+
+	page, err := service.ListManifests(ctx, "checkout", nil, 10, 0)
+	if err != nil {
+		t.Fatalf("list manifests: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != saved.ID {
+		t.Fatalf("expected note search to match only the annotated manifest, got %+v", page.Items)
+	}
+
+	updated, err := service.UpdateNote(ctx, saved.ID, "Promoted to prod")
+	if err != nil {
+		t.Fatalf("update note: %v", err)
+	}
+	if updated.Note != "Promoted to prod" {
+		t.Fatalf("expected updated note, got %q", updated.Note)
+	}
+	if updated.CreatedAt != saved.CreatedAt {
+		t.Fatalf("expected createdAt to be preserved")
+	}
+
+	if _, err := service.UpdateNote(ctx, "missing", "x"); !errors.Is(err, ErrManifestNotFound) {
+		t.Fatalf("expected ErrManifestNotFound, got %v", err)
+	}
+}
+
+func TestManifestMemoryExpiryEvictsExpiredRecords(t *testing.T) {
+	service := &ManifestService{ttl: 50 * time.Millisecond}
+	ctx := context.Background()
+
+	saved, err := service.SaveManifest(ctx, models.SaveManifestRequest{Title: "demo", YAML: "kind: ConfigMap\n"})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	service.startMemoryExpiry()
+	defer service.Close(ctx)
+
+	if _, err := service.GetManifest(ctx, saved.ID); err != nil {
+		t.Fatalf("expected manifest to exist before the TTL elapses, got %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		_, err := service.GetManifest(ctx, saved.ID)
+		if errors.Is(err, ErrManifestNotFound) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected manifest to be evicted after the TTL, got %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestManifestCompactPurgesExpiredRecordsInMemoryMode(t *testing.T) {
+	service := &ManifestService{ttl: time.Hour}
+	ctx := context.Background()
+	now := time.Now().UTC()
+	service.memory = []models.ManifestRecord{
+		{ID: "fresh", Title: "web", Kind: "Deployment", YAML: "kind: Deployment\n", CreatedAt: now},
+		{ID: "repeat", Title: "web", Kind: "Deployment", YAML: "kind: Deployment\n", CreatedAt: now.Add(-time.Minute)},
+		{ID: "expired", Title: "old", Kind: "ConfigMap", YAML: "kind: ConfigMap\n", CreatedAt: now.Add(-2 * time.Hour)},
+	}
+
+	result, err := service.Compact(ctx, models.CompactManifestsRequest{})
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if result.Mode != "memory" || result.Expired != 1 || result.Duplicates != 0 || result.Remaining != 2 {
+		t.Fatalf("unexpected compact result: %+v", result)
+	}
+	if !slices.Equal(result.RemovedIDs, []string{"expired"}) {
+		t.Fatalf("expected only the expired record to be removed, got %v", result.RemovedIDs)
+	}
+	for _, id := range []string{"fresh", "repeat"} {
+		if _, err := service.GetManifest(ctx, id); err != nil {
+			t.Fatalf("expected %s to be kept without collapseDuplicates: %v", id, err)
+		}
+	}
+}
+
+func TestManifestCompactCollapsesOnlyIdenticalRecordsWhenAsked(t *testing.T) {
+	service := &ManifestService{}
+	ctx := context.Background()
+	now := time.Now().UTC()
+	service.memory = []models.ManifestRecord{
+		{ID: "newest", Title: "web", Kind: "Deployment", YAML: "kind: Deployment\n", Tags: []string{"prod"}, CreatedAt: now},
+		{ID: "copy", Title: "web", Kind: "Deployment", YAML: "kind: Deployment\n", Tags: []string{"prod"}, CreatedAt: now.Add(-time.Minute)},
+		{ID: "annotated", Title: "web", Kind: "Deployment", YAML: "kind: Deployment\n", Tags: []string{"prod"}, Note: "keep me", CreatedAt: now.Add(-2 * time.Minute)},
+		{ID: "reordered", Title: "web", Kind: "Deployment", YAML: "kind:  Deployment\n", Tags: []string{"prod"}, CreatedAt: now.Add(-3 * time.Minute)},
+	}
+
+	result, err := service.Compact(ctx, models.CompactManifestsRequest{CollapseDuplicates: true})
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if result.Duplicates != 1 || result.Remaining != 3 || !slices.Equal(result.RemovedIDs, []string{"copy"}) {
+		t.Fatalf("expected only the identical older copy to be removed, got %+v", result)
+	}
+	if _, err := service.GetManifest(ctx, "copy"); !errors.Is(err, ErrManifestNotFound) {
+		t.Fatalf("expected the older copy to be purged, got %v", err)
+	}
+	for _, id := range []string{"newest", "annotated", "reordered"} {
+		if _, err := service.GetManifest(ctx, id); err != nil {
+			t.Fatalf("expected %s to be kept: %v", id, err)
+		}
+	}
+}
+
+func TestUpdateManifestOverwritesInPlace(t *testing.T) {
+	service := &ManifestService{}
+	ctx := context.Background()
+
+	saved, err := service.SaveManifest(ctx, models.SaveManifestRequest{Title: "web", Kind: "Deployment", YAML: "replicas: 1\n", Note: "keep me"})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+
+	updated, err := service.UpdateManifest(ctx, saved.ID, models.SaveManifestRequest{Title: "web v2", Kind: "Deployment", YAML: "replicas: 3\n"})
+	if err != nil {
+		t.Fatalf("update manifest: %v", err)
+	}
+	if updated.ID != saved.ID || updated.Title != "web v2" || updated.YAML != "replicas: 3\n" {
+		t.Fatalf("expected manifest to be overwritten in place, got %+v", updated)
+	}
+	if !updated.CreatedAt.Equal(saved.CreatedAt) || updated.UpdatedAt.Before(saved.UpdatedAt) || updated.Note != "keep me" {
+		t.Fatalf("expected createdAt and note to be preserved, got %+v", updated)
+	}
+	if count, _ := service.Count(ctx); count != 1 {
+		t.Fatalf("expected update not to create a duplicate, got %d records", count)
+	}
+
+	if _, err := service.UpdateManifest(ctx, "missing", models.SaveManifestRequest{YAML: "x: 1\n"}); !errors.Is(err, ErrManifestNotFound) {
+		t.Fatalf("expected ErrManifestNotFound, got %v", err)
+	}
+}
+
+func TestBulkTagUpdatesManifestsByID(t *testing.T) {
+	service := &ManifestService{}
+	ctx := context.Background()
+
+	ids := make([]string, 0, 3)
+	for _, title := range []string{"web", "worker", "cron"} {
+		saved, err := service.SaveManifest(ctx, models.SaveManifestRequest{Title: title, Kind: "Deployment", YAML: "kind: Deployment\n"})
+		if err != nil {
+			t.Fatalf("save manifest: %v", err)
+		}
+		ids = append(ids, saved.ID)
+	}
+	untagged, err := service.SaveManifest(ctx, models.SaveManifestRequest{Title: "other", YAML: "kind: Service\n"})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+
+	updated, err := service.BulkTag(ctx, models.BulkTagManifestsRequest{IDs: ids, Add: []string{"prod", " team-a ", "prod"}})
+	if err != nil {
+		t.Fatalf("bulk tag: %v", err)
+	}
+	if updated != 3 {
+		t.Fatalf("expected 3 manifests updated, got %d", updated)
+	}
+	for _, id := range ids {
+		record, err := service.GetManifest(ctx, id)
+		if err != nil {
+			t.Fatalf("get manifest: %v", err)
+		}
+		if !slices.Equal(record.Tags, []string{"prod", "team-a"}) {
+			t.Fatalf("expected %s to be tagged prod and team-a, got %v", record.Title, record.Tags)
+		}
+	}
+	if record, _ := service.GetManifest(ctx, untagged.ID); len(record.Tags) != 0 {
+		t.Fatalf("expected unselected manifest to stay untagged, got %v", record.Tags)
+	}
+
+	if _, err := service.BulkTag(ctx, models.BulkTagManifestsRequest{IDs: ids[:1], Remove: []string{"prod"}}); err != nil {
+		t.Fatalf("bulk untag: %v", err)
+	}
+	if record, _ := service.GetManifest(ctx, ids[0]); !slices.Equal(record.Tags, []string{"team-a"}) {
+		t.Fatalf("expected prod to be removed, got %v", record.Tags)
+	}
+	if _, err := service.BulkTag(ctx, models.BulkTagManifestsRequest{Add: []string{"prod"}}); err == nil {
+		t.Fatal("expected an error when no manifests are selected")
+	}
+}
+
+func TestListManifestsPagesNewestFirstWithTotal(t *testing.T) {
+	service := &ManifestService{}
+	ctx := context.Background()
+
+	for _, title := range []string{"one", "two", "three", "four", "five"} {
+		if _, err := service.SaveManifest(ctx, models.SaveManifestRequest{Title: title, YAML: "kind: ConfigMap\n"}); err != nil {
+			t.Fatalf("save manifest: %v", err)
+		}
+	}
+	if _, err := service.SaveManifest(ctx, models.SaveManifestRequest{Title: "svc", YAML: "kind: Service\n"}); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+
+	page, err := service.ListManifests(ctx, "configmap", nil, 2, 2)
+	if err != nil {
+		t.Fatalf("list manifests: %v", err)
+	}
+	if page.Total != 5 || page.Limit != 2 || page.Offset != 2 {
+		t.Fatalf("expected total 5 with limit 2 offset 2, got %+v", page)
+	}
+	titles := []string{}
+	for _, item := range page.Items {
+		titles = append(titles, item.Title)
+	}
+	if !slices.Equal(titles, []string{"three", "two"}) {
+		t.Fatalf("expected the second newest-first page, got %v", titles)
+	}
+
+	if past, _ := service.ListManifests(ctx, "", nil, 10, 50); len(past.Items) != 0 || past.Total != 6 {
+		t.Fatalf("expected an empty page past the end with the full total, got %+v", past)
+	}
+}
+
+func TestListManifestsFiltersByAllRequestedTags(t *testing.T) {
+	service := &ManifestService{}
+	ctx := context.Background()
+
+	for _, req := range []models.SaveManifestRequest{
+		{Title: "web-prod", YAML: "kind: Deployment\n", Tags: []string{"prod", "team-a"}},
+		{Title: "web-dev", YAML: "kind: Deployment\n", Tags: []string{"dev", "team-a"}},
+		{Title: "db-prod", YAML: "kind: StatefulSet\n", Tags: []string{"team-b", "prod"}},
+		{Title: "untagged", YAML: "kind: ConfigMap\n"},
+	} {
+		if _, err := service.SaveManifest(ctx, req); err != nil {
+			t.Fatalf("save manifest: %v", err)
+		}
+	}
+
+	page, err := service.ListManifests(ctx, "", []string{"prod", "team-a"}, 10, 0)
+	if err != nil {
+		t.Fatalf("list manifests: %v", err)
+	}
+	if page.Total != 1 || len(page.Items) != 1 || page.Items[0].Title != "web-prod" {
+		t.Fatalf("expected only the manifest carrying both tags, got %+v", page.Items)
+	}
+	if !slices.Equal(page.Items[0].Tags, []string{"prod", "team-a"}) {
+		t.Fatalf("expected saved tags to be returned, got %v", page.Items[0].Tags)
+	}
+
+	if single, _ := service.ListManifests(ctx, "", []string{"prod"}, 10, 0); single.Total != 2 {
+		t.Fatalf("expected two prod manifests, got %+v", single.Items)
+	}
+}
+
+func TestSaveManifestUsesInjectedClock(t *testing.T) {
+	frozen := time.Date(2024, time.March, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	service := &ManifestService{now: func() time.Time { return frozen }}
+	ctx := context.Background()
+
+	saved, err := service.SaveManifest(ctx, models.SaveManifestRequest{Title: "web", YAML: "kind: ConfigMap\n"})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	if !saved.CreatedAt.Equal(frozen) || saved.CreatedAt.Location() != time.UTC {
+		t.Fatalf("expected CreatedAt %v in UTC, got %v", frozen.UTC(), saved.CreatedAt)
+	}
+	if !saved.UpdatedAt.Equal(frozen) {
+		t.Fatalf("expected UpdatedAt %v, got %v", frozen.UTC(), saved.UpdatedAt)
+	}
+
+	later := frozen.Add(time.Hour)
+	service.now = func() time.Time { return later }
+	updated, err := service.UpdateNote(ctx, saved.ID, "checked")
+	if err != nil {
+		t.Fatalf("update note: %v", err)
+	}
+	if !updated.CreatedAt.Equal(frozen) || !updated.UpdatedAt.Equal(later) {
+		t.Fatalf("expected CreatedAt to stay %v and UpdatedAt to move to %v, got %+v", frozen, later, updated)
+	}
+}
+
+func TestManifestTTLSecondsClampsToInt32(t *testing.T) {
+	cases := map[time.Duration]int32{
+		500 * time.Millisecond:       1,
+		90 * time.Second:             90,
+		200 * 365 * 24 * time.Hour:   math.MaxInt32,
+		time.Duration(math.MaxInt64): math.MaxInt32,
+	}
+	for ttl, want := range cases {
+		if got := manifestTTLSeconds(ttl); got != want {
+			t.Fatalf("manifestTTLSeconds(%v) = %d, want %d", ttl, got, want)
+		}
+	}
+}
+
+func TestBulkTagQueryIsLiteralOnEveryStore(t *testing.T) {
+	service := &ManifestService{}
+	ctx := context.Background()
+	if _, err := service.SaveManifest(ctx, models.SaveManifestRequest{Title: "web", YAML: "kind: ConfigMap\n"}); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	updated, err := service.BulkTag(ctx, models.BulkTagManifestsRequest{Query: ".*", Add: []string{"prod"}})
+	if err != nil {
+		t.Fatalf("bulk tag: %v", err)
+	}
+	if updated != 0 {
+		t.Fatalf("expected a regex-looking query to match literally, tagged %d", updated)
+	}
+
+	filter := bulkTagQueryFilter(" .* ")
+	clauses, _ := filter["$or"].([]bson.M)
+	if len(clauses) == 0 {
+		t.Fatalf("expected an $or filter, got %v", filter)
+	}
+	if pattern := clauses[0]["title"].(bson.M)["$regex"]; pattern != `\.\*` {
+		t.Fatalf("expected the Mongo regex to be escaped, got %v", pattern)
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// normalizeTags trims tags, drops empty ones and returns the rest sorted and
+// de-duplicated so stored tag lists compare cleanly.
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]struct{}, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		out = append(out, tag)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// applyTagChanges returns tags with add merged in and remove taken out.
+func applyTagChanges(tags, add, remove []string) []string {
+	removed := make(map[string]struct{}, len(remove))
+	for _, tag := range remove {
+		removed[tag] = struct{}{}
+	}
+	out := make([]string, 0, len(tags)+len(add))
+	for _, tag := range normalizeTags(append(append([]string(nil), tags...), add...)) {
+		if _, ok := removed[tag]; !ok {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// hasAllTags reports whether want is a subset of tags.
+func hasAllTags(tags, want []string) bool {
+	for _, tag := range want {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeBulkTagRequest(req models.BulkTagManifestsRequest) ([]string, []string, []string, error) {
+	ids := normalizeTags(req.IDs)
+	add, remove := normalizeTags(req.Add), normalizeTags(req.Remove)
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, nil, nil, errors.New("at least one tag to add or remove is required")
+	}
+	if len(ids) == 0 && strings.TrimSpace(req.Query) == "" {
+		return nil, nil, nil, errors.New("ids or query is required to select manifests")
+	}
+	return ids, add, remove, nil
+}
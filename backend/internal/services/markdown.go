@@ -0,0 +1,19 @@
+package services
+
+import "strings"
+
+// YAMLToMarkdown wraps generated YAML in a GitHub-flavored ```yaml fence
+// under a short heading, ready to paste into a PR or issue.
+func YAMLToMarkdown(title, manifest string) string {
+	var b strings.Builder
+	if title = strings.TrimSpace(title); title != "" {
+		b.WriteString("### " + title + "\n\n")
+	}
+	b.WriteString("```yaml\n")
+	b.WriteString(manifest)
+	if !strings.HasSuffix(manifest, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString("```\n")
+	return b.String()
+}
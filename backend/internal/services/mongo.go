@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// ErrMongoUnavailable is returned by Ping when a service is running on its
+// in-memory fallback because MongoDB could not be reached at startup.
+var ErrMongoUnavailable = errors.New("mongo unavailable; serving from in-memory storage")
+
+// Pinger is implemented by stores that can report whether their backing
+// database is reachable.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// mongoClientOptions builds the client options shared by every Mongo-backed
+// service. Tuning knobs left at zero keep the driver defaults.
+func mongoClientOptions(cfg config.Config) *options.ClientOptions {
+	opts := options.Client().ApplyURI(cfg.MongoURI)
+	if cfg.MongoMaxPoolSize > 0 {
+		opts.SetMaxPoolSize(cfg.MongoMaxPoolSize)
+	}
+	if cfg.MongoConnectTimeout > 0 {
+		opts.SetConnectTimeout(cfg.MongoConnectTimeout)
+	}
+	if cfg.MongoServerSelectionTimeout > 0 {
+		opts.SetServerSelectionTimeout(cfg.MongoServerSelectionTimeout)
+	}
+	return opts
+}
+
+// opLimiter bounds how many database operations run at once so bursts queue
+// in-process instead of exhausting the driver's connection pool. A nil
+// limiter never blocks.
+type opLimiter chan struct{}
+
+func newOpLimiter(size int) opLimiter {
+	if size <= 0 {
+		return nil
+	}
+	return make(opLimiter, size)
+}
+
+// acquire waits for a free slot or for ctx to end. The returned release must
+// be called exactly once when the operation, including any cursor reads, is
+// finished.
+func (l opLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	select {
+	case l <- struct{}{}:
+		return func() { <-l }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait for database slot: %w", ctx.Err())
+	}
+}
+
+func pingMongo(ctx context.Context, client *mongo.Client, limiter opLimiter) error {
+	if client == nil {
+		return ErrMongoUnavailable
+	}
+	release, err := limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		return fmt.Errorf("ping mongodb: %w", err)
+	}
+	return nil
+}
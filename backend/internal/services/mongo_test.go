@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+)
+
+func TestMongoClientOptionsAppliesTuning(t *testing.T) {
+	opts := mongoClientOptions(config.Config{
+		MongoURI:                    "mongodb://localhost:27017",
+		MongoMaxPoolSize:            25,
+		MongoConnectTimeout:         3 * time.Second,
+		MongoServerSelectionTimeout: 7 * time.Second,
+	})
+
+	if opts.MaxPoolSize == nil || *opts.MaxPoolSize != 25 {
+		t.Fatalf("expected max pool size 25, got %v", opts.MaxPoolSize)
+	}
+	if opts.ConnectTimeout == nil || *opts.ConnectTimeout != 3*time.Second {
+		t.Fatalf("expected connect timeout 3s, got %v", opts.ConnectTimeout)
+	}
+	if opts.ServerSelectionTimeout == nil || *opts.ServerSelectionTimeout != 7*time.Second {
+		t.Fatalf("expected server selection timeout 7s, got %v", opts.ServerSelectionTimeout)
+	}
+}
+
+func TestMongoClientOptionsKeepsDriverDefaults(t *testing.T) {
+	opts := mongoClientOptions(config.Config{MongoURI: "mongodb://localhost:27017"})
+
+	if opts.MaxPoolSize != nil || opts.ConnectTimeout != nil || opts.ServerSelectionTimeout != nil {
+		t.Fatalf("expected unset tuning options, got pool=%v connect=%v selection=%v",
+			opts.MaxPoolSize, opts.ConnectTimeout, opts.ServerSelectionTimeout)
+	}
+}
+
+func TestOpLimiterBoundsConcurrency(t *testing.T) {
+	const limit = 3
+	limiter := newOpLimiter(limit)
+
+	var inFlight, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := limiter.acquire(context.Background())
+			if err != nil {
+				t.Errorf("acquire: %v", err)
+				return
+			}
+			defer release()
+
+			current := inFlight.Add(1)
+			for {
+				seen := peak.Load()
+				if current <= seen || peak.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > limit || got == 0 {
+		t.Fatalf("expected peak concurrency between 1 and %d, got %d", limit, got)
+	}
+}
+
+func TestOpLimiterReleasesOnContextCancel(t *testing.T) {
+	limiter := newOpLimiter(1)
+	release, err := limiter.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a queued caller to give up when its context ends, got %v", err)
+	}
+
+	release()
+	next, err := limiter.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("expected the slot to be free after release, got %v", err)
+	}
+	next()
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// podSpecPaths maps kinds whose pod spec is not at spec.template.spec.
+var podSpecPaths = map[string]string{
+	"Pod":     "spec",
+	"CronJob": "spec.jobTemplate.spec.template.spec",
+}
+
+// podSpecAPIVersions maps kinds that are not served from apps/v1 to their
+// default apiVersion.
+var podSpecAPIVersions = map[string]string{
+	"Pod":     "v1",
+	"Job":     "batch/v1",
+	"CronJob": "batch/v1",
+}
+
+// BuildPodSpec expands a container list into indexed field definitions for
+// the kind's pod spec and renders them, so callers don't assemble
+// containers[i].ports[j] paths by hand.
+func (s *YAMLService) BuildPodSpec(req models.PodSpecRequest) (models.PodSpecResponse, error) {
+	kind := fallback(req.Kind, "Deployment")
+	apiVersion, ok := podSpecAPIVersions[kind]
+	if !ok {
+		apiVersion = "apps/v1"
+	}
+	apiVersion = fallback(req.APIVersion, apiVersion)
+	if len(req.Containers) == 0 {
+		return models.PodSpecResponse{}, fmt.Errorf("at least one container is required")
+	}
+
+	name := fallback(req.Name, strings.ToLower(kind)+"-sample")
+	fields := []models.FieldDefinition{
+		{Path: "metadata.name", Value: name, Description: "Name for this resource."},
+	}
+	if kind == "Deployment" || kind == "StatefulSet" || kind == "DaemonSet" || kind == "ReplicaSet" {
+		fields = append(fields,
+			models.FieldDefinition{Path: "spec.selector.matchLabels.app", Value: name, Description: "Pod label selector."},
+			models.FieldDefinition{Path: "spec.template.metadata.labels.app", Value: name, Description: "Pod template labels; must match the selector."},
+		)
+	}
+
+	podSpec, ok := podSpecPaths[kind]
+	if !ok {
+		podSpec = "spec.template.spec"
+	}
+	seen := make(map[string]bool, len(req.Containers))
+	for i, container := range req.Containers {
+		containerName := strings.TrimSpace(container.Name)
+		image := strings.TrimSpace(container.Image)
+		if containerName == "" || image == "" {
+			return models.PodSpecResponse{}, fmt.Errorf("container %d: name and image are required", i)
+		}
+		if seen[containerName] {
+			return models.PodSpecResponse{}, fmt.Errorf("container %d: duplicate name %q", i, containerName)
+		}
+		seen[containerName] = true
+
+		prefix := podSpec + ".containers[" + strconv.Itoa(i) + "]"
+		fields = append(fields,
+			models.FieldDefinition{Path: prefix + ".name", Value: containerName, Type: "string", Description: "Container name."},
+			models.FieldDefinition{Path: prefix + ".image", Value: image, Type: "string", Description: "Container image."},
+		)
+		for j, port := range container.Ports {
+			if port < 1 || port > 65535 {
+				return models.PodSpecResponse{}, fmt.Errorf("container %q: port %d is out of range", containerName, port)
+			}
+			fields = append(fields, models.FieldDefinition{
+				Path:        prefix + ".ports[" + strconv.Itoa(j) + "].containerPort",
+				Value:       strconv.Itoa(port),
+				Type:        "number",
+				Description: "Exposed container port.",
+			})
+		}
+		for j, env := range container.Env {
+			envName := strings.TrimSpace(env.Name)
+			if envName == "" {
+				return models.PodSpecResponse{}, fmt.Errorf("container %q: env %d: name is required", containerName, j)
+			}
+			envPrefix := prefix + ".env[" + strconv.Itoa(j) + "]"
+			fields = append(fields,
+				models.FieldDefinition{Path: envPrefix + ".name", Value: envName, Type: "string", Description: "Environment variable name."},
+				models.FieldDefinition{Path: envPrefix + ".value", Value: env.Value, Type: "string", Description: "Environment variable value."},
+			)
+		}
+	}
+
+	output, err := s.GenerateYAML(apiVersion, kind, fields)
+	if err != nil {
+		return models.PodSpecResponse{}, err
+	}
+	return models.PodSpecResponse{Fields: fields, YAML: output}, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+func TestBuildPodSpecIndexesEachContainer(t *testing.T) {
+	result, err := NewYAMLService().BuildPodSpec(models.PodSpecRequest{
+		Name: "web",
+		Containers: []models.PodSpecContainer{
+			{Name: "app", Image: "nginx:1.27", Ports: []int{80, 443}, Env: []models.PodSpecEnvVar{{Name: "PORT", Value: "80"}}},
+			{Name: "proxy", Image: "envoy:1.30", Ports: []int{9901}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	values := map[string]string{}
+	for _, field := range result.Fields {
+		values[field.Path] = field.Value
+	}
+	for path, expected := range map[string]string{
+		"spec.template.spec.containers[0].name":                   "app",
+		"spec.template.spec.containers[0].ports[1].containerPort": "443",
+		"spec.template.spec.containers[0].env[0].value":           "80",
+		"spec.template.spec.containers[1].name":                   "proxy",
+		"spec.template.spec.containers[1].image":                  "envoy:1.30",
+		"spec.selector.matchLabels.app":                           "web",
+	} {
+		if values[path] != expected {
+			t.Fatalf("expected %s=%q, got %q", path, expected, values[path])
+		}
+	}
+
+	for _, expected := range []string{"name: app", "name: proxy", "containerPort: 443", `value: "80"`} {
+		if !strings.Contains(result.YAML, expected) {
+			t.Fatalf("expected YAML to contain %q, got:\n%s", expected, result.YAML)
+		}
+	}
+	if strings.Index(result.YAML, "name: app") > strings.Index(result.YAML, "name: proxy") {
+		t.Fatalf("expected containers in request order, got:\n%s", result.YAML)
+	}
+}
+
+func TestBuildPodSpecRejectsDuplicateContainerNames(t *testing.T) {
+	_, err := NewYAMLService().BuildPodSpec(models.PodSpecRequest{
+		Kind: "Pod",
+		Containers: []models.PodSpecContainer{
+			{Name: "app", Image: "nginx:1.27"},
+			{Name: "app", Image: "nginx:1.28"},
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "duplicate name") {
+		t.Fatalf("expected duplicate container names to be rejected, got %v", err)
+	}
+}
+
+func TestBuildPodSpecDefaultsAPIVersionFromKind(t *testing.T) {
+	containers := []models.PodSpecContainer{{Name: "app", Image: "nginx:1.27"}}
+	for kind, expected := range map[string]string{
+		"Pod":        "apiVersion: v1\n",
+		"Job":        "apiVersion: batch/v1\n",
+		"CronJob":    "apiVersion: batch/v1\n",
+		"Deployment": "apiVersion: apps/v1\n",
+	} {
+		result, err := NewYAMLService().BuildPodSpec(models.PodSpecRequest{Kind: kind, Containers: containers})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", kind, err)
+		}
+		if !strings.HasPrefix(result.YAML, expected) {
+			t.Fatalf("%s: expected YAML to start with %q, got:\n%s", kind, expected, result.YAML)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// PreviewYAML renders whatever the request describes so far for a live
+// editor. Unlike GenerateYAMLFromRequest it never rejects incomplete input:
+// a missing apiVersion or kind, an invalid name affix, or a required field
+// still left empty is reported in Missing and the rest is rendered anyway.
+func (s *YAMLService) PreviewYAML(req models.GenerateYAMLRequest) (models.GeneratePreviewResponse, error) {
+	missing := make([]string, 0)
+	resource := map[string]any{}
+	if apiVersion := strings.TrimSpace(req.APIVersion); apiVersion != "" {
+		resource["apiVersion"] = apiVersion
+	} else {
+		missing = append(missing, "apiVersion is required")
+	}
+	if kind := strings.TrimSpace(req.Kind); kind != "" {
+		resource["kind"] = kind
+	} else {
+		missing = append(missing, "kind is required")
+	}
+
+	for _, field := range req.Fields {
+		path := strings.TrimSpace(field.Path)
+		if path == "" {
+			continue
+		}
+		setValue(resource, parsePath(path), parseValue(field.Value, field.Type))
+	}
+	if req.ApplyKnownDefaults {
+		applyKnownDefaults(resource, req.APIVersion, req.Kind)
+	}
+	if err := applyNameAffixes(resource, req.NamePrefix, req.NameSuffix); err != nil {
+		missing = append(missing, err.Error())
+	}
+
+	node, err := resourceNode(resource)
+	if err != nil {
+		return models.GeneratePreviewResponse{}, err
+	}
+	if req.IncludeComments {
+		annotateFieldComments(node, req.Fields)
+	}
+	annotateEnumComments(node, req.Fields)
+	output, err := marshalNode(node)
+	if err != nil {
+		return models.GeneratePreviewResponse{}, err
+	}
+
+	if required, err := s.MissingRequiredFields(output, req.Fields); err == nil {
+		for _, path := range required {
+			missing = append(missing, path+" is required")
+		}
+	}
+	return models.GeneratePreviewResponse{YAML: output, Missing: missing}, nil
+}
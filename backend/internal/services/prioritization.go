@@ -0,0 +1,177 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PrioritizationStrategy controls how parsed CRD fields are ranked, which in
+// turn decides their order and which fields fill the default-field cap.
+type PrioritizationStrategy string
+
+const (
+	// PrioritizeRequiredFirst ranks required > defaulted > shallow > alphabetical.
+	PrioritizeRequiredFirst PrioritizationStrategy = "required-first"
+	// PrioritizeAlphabetical ranks fields by path.
+	PrioritizeAlphabetical PrioritizationStrategy = "alphabetical"
+	// PrioritizeSchemaOrder keeps fields in the order the schema declares them.
+	PrioritizeSchemaOrder PrioritizationStrategy = "schema-order"
+)
+
+// ParseOptions tunes ParseCRDWithOptions. The zero value matches ParseCRD.
+type ParseOptions struct {
+	Prioritization PrioritizationStrategy
+	// OmitInferredDescriptions leaves a field's description empty when the
+	// schema has none, instead of the "Inferred from ..." placeholder.
+	OmitInferredDescriptions bool
+	// ServiceHeuristics overrides the service's configured heuristics for
+	// seeding service-like components; empty lists keep the configured ones.
+	ServiceHeuristics ServiceHeuristics
+	// StripPaths overrides the service's configured strip policy for pasted
+	// resources; empty keeps the configured one.
+	StripPaths []string
+
+	// limits is filled in from the CRDService doing the parse.
+	limits SchemaLimits
+}
+
+// ParsePrioritizationStrategy validates a strategy name, defaulting empty
+// input to PrioritizeRequiredFirst.
+func ParsePrioritizationStrategy(value string) (PrioritizationStrategy, error) {
+	switch strategy := PrioritizationStrategy(strings.ToLower(strings.TrimSpace(value))); strategy {
+	case "":
+		return PrioritizeRequiredFirst, nil
+	case PrioritizeRequiredFirst, PrioritizeAlphabetical, PrioritizeSchemaOrder:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown prioritization strategy %q: use required-first, alphabetical or schema-order", value)
+	}
+}
+
+// sortCandidates ranks candidates by strategy. schemaLines, used by
+// PrioritizeSchemaOrder, maps a field path to the line declaring it.
+func sortCandidates(items []schemaFieldCandidate, strategy PrioritizationStrategy, schemaLines map[string]int) []schemaFieldCandidate {
+	switch strategy {
+	case PrioritizeAlphabetical:
+		out := append([]schemaFieldCandidate(nil), items...)
+		sort.SliceStable(out, func(i, j int) bool { return out[i].Field.Path < out[j].Field.Path })
+		return out
+	case PrioritizeSchemaOrder:
+		line := func(path string) int {
+			if value, ok := schemaLines[path]; ok {
+				return value
+			}
+			return math.MaxInt
+		}
+		out := append([]schemaFieldCandidate(nil), items...)
+		sort.SliceStable(out, func(i, j int) bool {
+			left, right := line(out[i].Field.Path), line(out[j].Field.Path)
+			if left != right {
+				return left < right
+			}
+			return out[i].Field.Path < out[j].Field.Path
+		})
+		return out
+	default:
+		return sortCandidatesByPriority(items)
+	}
+}
+
+// schemaFieldLines walks the spec schema of the given CRD version in the YAML
+// node tree and records the line of every property key under its field path
+// ("spec.ports[0].port"), so fields can be ordered as the author wrote them.
+func schemaFieldLines(raw string, version string) map[string]int {
+	lines := make(map[string]int)
+	decoder := yaml.NewDecoder(strings.NewReader(raw))
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			return lines
+		}
+		root := resolveNode(&doc)
+		if !strings.EqualFold(nodeScalar(mappingValue(root, "kind")), "CustomResourceDefinition") {
+			continue
+		}
+		spec := mappingValue(root, "spec")
+		if specSchema := specSchemaNode(spec, version); specSchema != nil {
+			collectSchemaLines("spec", specSchema, lines)
+			return lines
+		}
+	}
+}
+
+func specSchemaNode(spec *yaml.Node, version string) *yaml.Node {
+	if versions := mappingValue(spec, "versions"); versions != nil && versions.Kind == yaml.SequenceNode {
+		for _, item := range versions.Content {
+			item = resolveNode(item)
+			if nodeScalar(mappingValue(item, "name")) != version {
+				continue
+			}
+			schema := mappingValue(mappingValue(mappingValue(item, "schema"), "openAPIV3Schema"), "properties")
+			if node := mappingValue(schema, "spec"); node != nil {
+				return node
+			}
+		}
+	}
+	legacy := mappingValue(mappingValue(mappingValue(spec, "validation"), "openAPIV3Schema"), "properties")
+	return mappingValue(legacy, "spec")
+}
+
+func collectSchemaLines(prefix string, schema *yaml.Node, lines map[string]int) {
+	properties := mappingValue(schema, "properties")
+	if properties == nil || properties.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(properties.Content); i += 2 {
+		key, child := properties.Content[i], resolveNode(properties.Content[i+1])
+		path := prefix + "." + key.Value
+		if _, seen := lines[path]; seen {
+			continue
+		}
+		lines[path] = key.Line
+		if items := mappingValue(child, "items"); items != nil {
+			lines[path+"[0]"] = key.Line
+			collectSchemaLines(path+"[0]", items, lines)
+			continue
+		}
+		collectSchemaLines(path, child, lines)
+	}
+}
+
+func resolveNode(node *yaml.Node) *yaml.Node {
+	for node != nil && (node.Kind == yaml.DocumentNode || node.Kind == yaml.AliasNode) {
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+			continue
+		}
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+	return node
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	node = resolveNode(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return resolveNode(node.Content[i+1])
+		}
+	}
+	return nil
+}
+
+func nodeScalar(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
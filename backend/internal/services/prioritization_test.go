@@ -0,0 +1,76 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+)
+
+const prioritizationCRD = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required: [zeta]
+              properties:
+                mid:
+                  type: object
+                  properties:
+                    inner:
+                      type: string
+                      default: x
+                zeta:
+                  type: string
+                alpha:
+                  type: string
+`
+
+func TestParseCRDWithOptionsOrdersFieldsByStrategy(t *testing.T) {
+	cases := map[PrioritizationStrategy]string{
+		"":                      "spec.zeta,spec.mid.inner,spec.alpha",
+		PrioritizeRequiredFirst: "spec.zeta,spec.mid.inner,spec.alpha",
+		PrioritizeAlphabetical:  "spec.alpha,spec.mid.inner,spec.zeta",
+		PrioritizeSchemaOrder:   "spec.mid.inner,spec.zeta,spec.alpha",
+	}
+	service := NewCRDService(config.Config{})
+	for strategy, expected := range cases {
+		template, _, err := service.ParseCRDWithOptions(prioritizationCRD, ParseOptions{Prioritization: strategy})
+		if err != nil {
+			t.Fatalf("%q: parse: %v", strategy, err)
+		}
+		paths := make([]string, 0, len(template.DefaultFields))
+		for _, field := range template.DefaultFields {
+			if strings.HasPrefix(field.Path, "spec.") {
+				paths = append(paths, field.Path)
+			}
+		}
+		if got := strings.Join(paths, ","); got != expected {
+			t.Fatalf("%q: expected %s, got %s", strategy, expected, got)
+		}
+	}
+}
+
+func TestParsePrioritizationStrategy(t *testing.T) {
+	if strategy, err := ParsePrioritizationStrategy(""); err != nil || strategy != PrioritizeRequiredFirst {
+		t.Fatalf("expected empty input to default to required-first, got %q (%v)", strategy, err)
+	}
+	if strategy, err := ParsePrioritizationStrategy(" Schema-Order "); err != nil || strategy != PrioritizeSchemaOrder {
+		t.Fatalf("expected schema-order, got %q (%v)", strategy, err)
+	}
+	if _, err := ParsePrioritizationStrategy("random"); err == nil {
+		t.Fatalf("expected unknown strategy to be rejected")
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+var rbacEditVerbs = []any{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+// GenerateRBAC renders the role and binding an operator needs to manage a
+// CRD's resources. Namespaced CRDs get a Role and RoleBinding; cluster-scoped
+// CRDs, or requests with ClusterScoped set, get a ClusterRole and
+// ClusterRoleBinding.
+func (s *CRDService) GenerateRBAC(req models.GenerateRBACRequest) (models.GenerateRBACResponse, error) {
+	docs, err := decodeYAMLDocuments(strings.TrimSpace(normalizeLineEndings(req.Raw)))
+	if err != nil {
+		return models.GenerateRBACResponse{}, err
+	}
+	root, ok := selectPrimaryResourceDoc(docs)
+	if !ok || !strings.EqualFold(asString(root["kind"]), "CustomResourceDefinition") {
+		return models.GenerateRBACResponse{}, errors.New("payload does not contain a CustomResourceDefinition")
+	}
+
+	group := asString(nested(root, "spec", "group"))
+	kind := asString(nested(root, "spec", "names", "kind"))
+	if group == "" || kind == "" {
+		return models.GenerateRBACResponse{}, errors.New("CRD must declare spec.group and spec.names.kind")
+	}
+	plural := asString(nested(root, "spec", "names", "plural"))
+	if plural == "" {
+		plural = strings.ToLower(kind) + "s"
+	}
+	clusterScoped := req.ClusterScoped || strings.EqualFold(asString(nested(root, "spec", "scope")), "Cluster")
+	namespace := fallback(req.Namespace, "default")
+	serviceAccount := fallback(req.ServiceAccount, "default")
+
+	rules := []any{map[string]any{
+		"apiGroups": []any{group},
+		"resources": []any{plural},
+		"verbs":     rbacEditVerbs,
+	}}
+	if crdHasStatusSubresource(root) {
+		rules = append(rules, map[string]any{
+			"apiGroups": []any{group},
+			"resources": []any{plural + "/status"},
+			"verbs":     []any{"get", "update", "patch"},
+		})
+	}
+
+	roleKind, bindingKind := "Role", "RoleBinding"
+	if clusterScoped {
+		roleKind, bindingKind = "ClusterRole", "ClusterRoleBinding"
+	}
+	name := plural + "." + group + "-editor"
+	roleMetadata := map[string]any{"name": name}
+	bindingMetadata := map[string]any{"name": name}
+	if !clusterScoped {
+		roleMetadata["namespace"] = namespace
+		bindingMetadata["namespace"] = namespace
+	}
+
+	role := map[string]any{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       roleKind,
+		"metadata":   roleMetadata,
+		"rules":      rules,
+	}
+	binding := map[string]any{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       bindingKind,
+		"metadata":   bindingMetadata,
+		"roleRef": map[string]any{
+			"apiGroup": "rbac.authorization.k8s.io",
+			"kind":     roleKind,
+			"name":     name,
+		},
+		"subjects": []any{map[string]any{
+			"kind":      "ServiceAccount",
+			"name":      serviceAccount,
+			"namespace": namespace,
+		}},
+	}
+
+	parts := make([]string, 0, 2)
+	for _, doc := range []map[string]any{role, binding} {
+		node, err := resourceNode(doc)
+		if err != nil {
+			return models.GenerateRBACResponse{}, err
+		}
+		output, err := marshalNode(node)
+		if err != nil {
+			return models.GenerateRBACResponse{}, err
+		}
+		parts = append(parts, output)
+	}
+
+	return models.GenerateRBACResponse{
+		YAML:   strings.Join(parts, "---\n"),
+		Group:  group,
+		Plural: plural,
+		Kind:   roleKind,
+	}, nil
+}
+
+func crdHasStatusSubresource(root map[string]any) bool {
+	if nested(root, "spec", "subresources", "status") != nil {
+		return true
+	}
+	versions, _ := nested(root, "spec", "versions").([]any)
+	for _, entry := range versions {
+		versionMap, _ := entry.(map[string]any)
+		if nested(versionMap, "subresources", "status") != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateRBACGrantsAccessToCRDResource(t *testing.T) {
+	crd := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.io
+spec:
+  group: example.io
+  scope: Namespaced
+  names:
+    kind: Widget
+    plural: widgets
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      subresources:
+        status: {}
+`
+	result, err := NewCRDService(config.Config{}).GenerateRBAC(models.GenerateRBACRequest{Raw: crd, Namespace: "operators", ServiceAccount: "widget-controller"})
+	if err != nil {
+		t.Fatalf("generate rbac: %v", err)
+	}
+	if result.Kind != "Role" || result.Group != "example.io" || result.Plural != "widgets" {
+		t.Fatalf("expected a namespaced Role for example.io/widgets, got %+v", result)
+	}
+
+	docs := strings.Split(result.YAML, "---\n")
+	if len(docs) != 2 {
+		t.Fatalf("expected role and binding documents, got %q", result.YAML)
+	}
+	var role struct {
+		Kind     string
+		Metadata struct{ Namespace string }
+		Rules    []struct {
+			APIGroups []string `yaml:"apiGroups"`
+			Resources []string
+			Verbs     []string
+		}
+	}
+	if err := yaml.Unmarshal([]byte(docs[0]), &role); err != nil {
+		t.Fatalf("decode role: %v", err)
+	}
+	if role.Kind != "Role" || role.Metadata.Namespace != "operators" || len(role.Rules) != 2 {
+		t.Fatalf("expected a Role in operators with resource and status rules, got %+v", role)
+	}
+	rule := role.Rules[0]
+	if len(rule.APIGroups) != 1 || rule.APIGroups[0] != "example.io" || len(rule.Resources) != 1 || rule.Resources[0] != "widgets" {
+		t.Fatalf("expected rule on example.io/widgets, got %+v", rule)
+	}
+	if len(rule.Verbs) != 7 {
+		t.Fatalf("expected CRUD and watch verbs, got %v", rule.Verbs)
+	}
+	if role.Rules[1].Resources[0] != "widgets/status" {
+		t.Fatalf("expected a status subresource rule, got %+v", role.Rules[1])
+	}
+	if !strings.Contains(docs[1], "kind: RoleBinding") || !strings.Contains(docs[1], "name: widget-controller") {
+		t.Fatalf("expected a RoleBinding for the service account, got %s", docs[1])
+	}
+
+	cluster, err := NewCRDService(config.Config{}).GenerateRBAC(models.GenerateRBACRequest{Raw: strings.Replace(crd, "Namespaced", "Cluster", 1)})
+	if err != nil {
+		t.Fatalf("generate cluster rbac: %v", err)
+	}
+	if cluster.Kind != "ClusterRole" || !strings.Contains(cluster.YAML, "kind: ClusterRoleBinding") {
+		t.Fatalf("expected cluster-scoped CRDs to get a ClusterRole, got %s", cluster.YAML)
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// ErrSchemaPathNotFound is returned by SchemaAt when the path leads outside
+// the CRD's schema.
+var ErrSchemaPathNotFound = errors.New("schema path not found")
+
+// SchemaAt returns the raw openAPIV3Schema node at a dotted field path such
+// as "spec.applicationConfig" or "spec.containers[0].ports", using the same
+// version schema ValidateInstance does. Object keys step through properties
+// (or additionalProperties for maps) and indexes step into items; a key
+// applied to an array schema steps into its items first. An empty path
+// returns the whole schema.
+func (s *CRDService) SchemaAt(raw, path string) (models.SchemaAtResponse, error) {
+	docs, err := decodeYAMLDocuments(strings.TrimSpace(normalizeLineEndings(raw)))
+	if err != nil {
+		return models.SchemaAtResponse{}, fmt.Errorf("YAML parse error: %w", err)
+	}
+	crd, ok := selectPrimaryResourceDoc(docs)
+	if !ok || !strings.EqualFold(asString(crd["kind"]), "CustomResourceDefinition") {
+		return models.SchemaAtResponse{}, errors.New("payload does not contain a CustomResourceDefinition")
+	}
+	node, version := selectRootSchema(crd)
+	if node == nil {
+		return models.SchemaAtResponse{}, errors.New("CRD has no openAPIV3Schema")
+	}
+
+	path = strings.TrimSpace(path)
+	walked := ""
+	for _, segment := range parsePath(path) {
+		switch typed := segment.(type) {
+		case int:
+			walked = fmt.Sprintf("%s[%d]", walked, typed)
+			node, _ = node["items"].(map[string]any)
+		case string:
+			walked = joinDiffPath(walked, typed)
+			if items, ok := node["items"].(map[string]any); ok && node["properties"] == nil {
+				node = items
+			}
+			if child, ok := nested(node, "properties", typed).(map[string]any); ok {
+				node = child
+			} else {
+				node, _ = node["additionalProperties"].(map[string]any)
+			}
+		}
+		if node == nil {
+			return models.SchemaAtResponse{}, fmt.Errorf("%w: %s", ErrSchemaPathNotFound, walked)
+		}
+	}
+
+	return models.SchemaAtResponse{Path: path, Version: version, Schema: node}, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+)
+
+const schemaAtCRD = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: demo.io
+  names:
+    kind: App
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                applicationConfig:
+                  type: object
+                  required: [mode]
+                  properties:
+                    mode:
+                      type: string
+                      enum: [fast, safe]
+                    retries:
+                      type: integer
+                      minimum: 0
+                containers:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      ports:
+                        type: array
+                        items:
+                          type: integer
+                labels:
+                  type: object
+                  additionalProperties:
+                    type: string
+                    maxLength: 63
+`
+
+func TestSchemaAtReturnsNestedSubtree(t *testing.T) {
+	service := NewCRDService(config.Config{})
+
+	result, err := service.SchemaAt(schemaAtCRD, "spec.applicationConfig")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Version != "v1" || result.Schema["type"] != "object" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	properties, _ := result.Schema["properties"].(map[string]any)
+	retries, _ := properties["retries"].(map[string]any)
+	if retries["minimum"] != 0 {
+		t.Fatalf("expected retries constraints in subtree, got %v", result.Schema)
+	}
+	if required, _ := result.Schema["required"].([]any); len(required) != 1 || required[0] != "mode" {
+		t.Fatalf("expected required list in subtree, got %v", result.Schema["required"])
+	}
+
+	for path, wantType := range map[string]string{
+		"spec.containers[0].ports": "array",
+		"spec.containers.ports[0]": "integer",
+		"spec.labels.team":         "string",
+	} {
+		result, err := service.SchemaAt(schemaAtCRD, path)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", path, err)
+		}
+		if result.Schema["type"] != wantType {
+			t.Fatalf("%s: expected type %s, got %v", path, wantType, result.Schema)
+		}
+	}
+}
+
+func TestSchemaAtReportsUnknownPath(t *testing.T) {
+	service := NewCRDService(config.Config{})
+	_, err := service.SchemaAt(schemaAtCRD, "spec.applicationConfig.missing.deeper")
+	if !errors.Is(err, ErrSchemaPathNotFound) {
+		t.Fatalf("expected ErrSchemaPathNotFound, got %v", err)
+	}
+	if err.Error() != "schema path not found: spec.applicationConfig.missing" {
+		t.Fatalf("expected error to name the first unknown segment, got %q", err)
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var secretKeyRegex = regexp.MustCompile(`(?i)(password|passwd|secret|token|api[-_]?key|access[-_]?key|private[-_]?key|credentials?)`)
+
+// minSecretEntropy is the Shannon entropy, in bits per character, above which
+// a value is treated as random enough to be a real credential rather than a
+// placeholder such as "changeme" or a reference such as "my-db-password".
+const (
+	minSecretLength  = 16
+	minSecretEntropy = 3.5
+)
+
+// ScanManifestSecrets decodes raw YAML and returns warnings for values that
+// look like plaintext credentials. It is a heuristic and never fails: YAML
+// that doesn't parse simply yields no warnings.
+func ScanManifestSecrets(raw string) []string {
+	docs, err := decodeYAMLDocuments(raw)
+	if err != nil {
+		return nil
+	}
+	warnings := make([]string, 0)
+	for _, doc := range docs {
+		warnings = append(warnings, scanDocumentSecrets(doc)...)
+	}
+	return warnings
+}
+
+func scanDocumentSecrets(doc map[string]any) []string {
+	if strings.EqualFold(asString(doc["kind"]), "CustomResourceDefinition") {
+		return nil
+	}
+
+	findings := make([]string, 0)
+	if strings.EqualFold(asString(doc["kind"]), "Secret") {
+		for _, section := range []string{"data", "stringData"} {
+			entries, _ := doc[section].(map[string]any)
+			for _, key := range sortedKeys(entries) {
+				value := asString(entries[key])
+				if section == "data" {
+					if decoded, err := base64.StdEncoding.DecodeString(value); err == nil {
+						value = string(decoded)
+					}
+				}
+				if looksLikeSecret(value) {
+					findings = append(findings, fmt.Sprintf(
+						"Secret %s.%s holds what looks like a real credential in plaintext; commit a SealedSecret or reference an external secret store instead.",
+						section, key))
+				}
+			}
+		}
+		return findings
+	}
+
+	walkSecretCandidates("", doc, &findings)
+	return findings
+}
+
+func walkSecretCandidates(prefix string, value any, findings *[]string) {
+	switch typed := value.(type) {
+	case map[string]any:
+		// env-style entries carry the sensitive name in a sibling "name" key.
+		if name := asString(typed["name"]); secretKeyRegex.MatchString(name) && looksLikeSecret(asString(typed["value"])) {
+			*findings = append(*findings, secretFieldWarning(joinDiffPath(prefix, "value"), name))
+		}
+		for _, key := range sortedKeys(typed) {
+			path := joinDiffPath(prefix, key)
+			if text, ok := typed[key].(string); ok {
+				if secretKeyRegex.MatchString(key) && looksLikeSecret(text) {
+					*findings = append(*findings, secretFieldWarning(path, key))
+				}
+				continue
+			}
+			walkSecretCandidates(path, typed[key], findings)
+		}
+	case []any:
+		for i, item := range typed {
+			walkSecretCandidates(fmt.Sprintf("%s[%d]", prefix, i), item, findings)
+		}
+	}
+}
+
+func secretFieldWarning(path, name string) string {
+	return fmt.Sprintf("Possible plaintext secret at %s (%s); move it into a Secret or SealedSecret and reference it instead.", path, name)
+}
+
+// looksLikeSecret reports whether value is long, unbroken and high-entropy.
+func looksLikeSecret(value string) bool {
+	value = strings.TrimSpace(value)
+	if len(value) < minSecretLength || strings.ContainsAny(value, " \t\n") {
+		return false
+	}
+	if strings.HasPrefix(value, "$") || strings.HasPrefix(value, "{{") {
+		return false
+	}
+	return shannonEntropy(value) >= minSecretEntropy
+}
+
+func shannonEntropy(value string) float64 {
+	counts := make(map[rune]int, len(value))
+	total := 0
+	for _, char := range value {
+		counts[char]++
+		total++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// FieldsFromSet turns kubectl-style "path=value" arguments into field
+// definitions. The value is everything after the first "=", and its type is
+// inferred the way parseValue infers untyped values, so "3" becomes a
+// number and "true" a boolean.
+func FieldsFromSet(args []string) ([]models.FieldDefinition, error) {
+	fields := make([]models.FieldDefinition, 0, len(args))
+	for i, arg := range args {
+		path, value, found := strings.Cut(arg, "=")
+		path = strings.TrimSpace(path)
+		if !found || path == "" {
+			return nil, fmt.Errorf("set[%d]: expected key=value, got %q", i, arg)
+		}
+		fields = append(fields, models.FieldDefinition{
+			Path:  path,
+			Value: value,
+			Type:  inferredValueType(parseValue(value, "")),
+		})
+	}
+	return fields, nil
+}
+
+func inferredValueType(value any) string {
+	switch value.(type) {
+	case int64, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
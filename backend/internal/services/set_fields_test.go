@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFieldsFromSetInfersValueTypes(t *testing.T) {
+	fields, err := FieldsFromSet([]string{"spec.replicas=3", "metadata.name=web", "spec.paused=false", "metadata.annotations.note=a=b"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	types := map[string]string{}
+	for _, field := range fields {
+		types[field.Path] = field.Type
+	}
+	if types["spec.replicas"] != "number" || types["metadata.name"] != "string" || types["spec.paused"] != "boolean" {
+		t.Fatalf("unexpected inferred types: %v", types)
+	}
+	if fields[3].Value != "a=b" {
+		t.Fatalf("expected the value to keep everything after the first '=', got %q", fields[3].Value)
+	}
+
+	output, err := NewYAMLService().GenerateYAML("apps/v1", "Deployment", fields)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var resource struct {
+		Metadata struct {
+			Name string `yaml:"name"`
+		} `yaml:"metadata"`
+		Spec map[string]any `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal([]byte(output), &resource); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if resource.Metadata.Name != "web" || resource.Spec["replicas"] != 3 || resource.Spec["paused"] != false {
+		t.Fatalf("expected typed values in the generated YAML, got:\n%s", output)
+	}
+}
+
+func TestFieldsFromSetRejectsArgumentsWithoutKey(t *testing.T) {
+	for _, arg := range []string{"spec.replicas", "=3"} {
+		if _, err := FieldsFromSet([]string{arg}); err == nil {
+			t.Fatalf("expected an error for %q", arg)
+		}
+	}
+}
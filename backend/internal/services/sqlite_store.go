@@ -0,0 +1,659 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	_ "modernc.org/sqlite"
+)
+
+const sqliteManifestSchema = `
+CREATE TABLE IF NOT EXISTS manifests (
+	id          TEXT PRIMARY KEY,
+	title       TEXT NOT NULL,
+	resource    TEXT NOT NULL DEFAULT '',
+	api_version TEXT NOT NULL DEFAULT '',
+	kind        TEXT NOT NULL DEFAULT '',
+	yaml        TEXT NOT NULL,
+	note        TEXT NOT NULL DEFAULT '',
+	tags        TEXT NOT NULL DEFAULT '[]',
+	created_at  INTEGER NOT NULL,
+	updated_at  INTEGER NOT NULL,
+	source_raw  TEXT NOT NULL DEFAULT '',
+	overrides   TEXT NOT NULL DEFAULT '[]'
+);
+CREATE INDEX IF NOT EXISTS manifests_created_at ON manifests (created_at DESC);
+`
+
+const sqliteTemplateSchema = `
+CREATE TABLE IF NOT EXISTS templates (
+	id       TEXT PRIMARY KEY,
+	title    TEXT NOT NULL,
+	document TEXT NOT NULL
+);
+`
+
+const sqliteManifestColumns = "id, title, resource, api_version, kind, yaml, note, tags, created_at, updated_at, source_raw, overrides"
+
+// SQLiteManifestStore persists manifests in a local SQLite file for
+// single-node deployments that don't run MongoDB.
+type SQLiteManifestStore struct {
+	db *sql.DB
+	// now is the clock used for timestamps; nil means time.Now.
+	now func() time.Time
+}
+
+// SQLiteTemplateStore persists templates in a local SQLite file. Each template
+// is stored as a JSON document keyed by its ID.
+type SQLiteTemplateStore struct {
+	db    *sql.DB
+	seeds []models.TemplateDefinition
+}
+
+var (
+	_ ManifestStore = (*SQLiteManifestStore)(nil)
+	_ TemplateStore = (*SQLiteTemplateStore)(nil)
+)
+
+func openSQLite(ctx context.Context, path string, schema string) (*sql.DB, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("sqlite path is required")
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite %q: %w", path, err)
+	}
+	// SQLite allows a single writer; serializing through one connection avoids
+	// SQLITE_BUSY errors between concurrent requests.
+	db.SetMaxOpenConns(1)
+
+	for _, statement := range []string{"PRAGMA journal_mode=WAL", "PRAGMA busy_timeout=5000", schema} {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("initialize sqlite %q: %w", path, err)
+		}
+	}
+	return db, nil
+}
+
+func NewSQLiteManifestStore(ctx context.Context, path string) (*SQLiteManifestStore, error) {
+	db, err := openSQLite(ctx, path, sqliteManifestSchema)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrateSQLiteManifestColumns(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &SQLiteManifestStore{db: db}, nil
+}
+
+// sqliteManifestMigrations lists columns added after the manifests table was
+// first released, with the statement that adds each one.
+var sqliteManifestMigrations = []struct {
+	column    string
+	statement string
+}{
+	{"tags", "ALTER TABLE manifests ADD COLUMN tags TEXT NOT NULL DEFAULT '[]'"},
+	{"source_raw", "ALTER TABLE manifests ADD COLUMN source_raw TEXT NOT NULL DEFAULT ''"},
+	{"overrides", "ALTER TABLE manifests ADD COLUMN overrides TEXT NOT NULL DEFAULT '[]'"},
+}
+
+// migrateSQLiteManifestColumns adds the columns databases created by older
+// releases are missing, such as tags from before manifests could be tagged.
+func migrateSQLiteManifestColumns(ctx context.Context, db *sql.DB) error {
+	existing, err := sqliteManifestColumnNames(ctx, db)
+	if err != nil {
+		return err
+	}
+	for _, migration := range sqliteManifestMigrations {
+		if _, ok := existing[migration.column]; ok {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, migration.statement); err != nil {
+			return fmt.Errorf("add manifests %s column: %w", migration.column, err)
+		}
+	}
+	return nil
+}
+
+func sqliteManifestColumnNames(ctx context.Context, db *sql.DB) (map[string]struct{}, error) {
+	rows, err := db.QueryContext(ctx, "PRAGMA table_info(manifests)")
+	if err != nil {
+		return nil, fmt.Errorf("inspect manifests table: %w", err)
+	}
+	defer rows.Close()
+	names := make(map[string]struct{}, 16)
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, kind       string
+			defaultValue     sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &kind, &notNull, &defaultValue, &pk); err != nil {
+			return nil, fmt.Errorf("inspect manifests table: %w", err)
+		}
+		names[name] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("inspect manifests table: %w", err)
+	}
+	return names, nil
+}
+
+// clock returns the current UTC time from s.now, or time.Now when unset.
+func (s *SQLiteManifestStore) clock() time.Time {
+	if s.now != nil {
+		return s.now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (s *SQLiteManifestStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *SQLiteManifestStore) Close(ctx context.Context) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+func (s *SQLiteManifestStore) SaveManifest(ctx context.Context, req models.SaveManifestRequest) (models.ManifestRecord, error) {
+	if strings.TrimSpace(req.YAML) == "" {
+		return models.ManifestRecord{}, fmt.Errorf("yaml is required")
+	}
+
+	now := s.clock()
+	record := models.ManifestRecord{
+		ID:         primitive.NewObjectID().Hex(),
+		Title:      fallback(req.Title, "Manifest"),
+		Resource:   strings.TrimSpace(req.Resource),
+		APIVersion: strings.TrimSpace(req.APIVersion),
+		Kind:       strings.TrimSpace(req.Kind),
+		YAML:       req.YAML,
+		Note:       strings.TrimSpace(req.Note),
+		Tags:       normalizeTags(req.Tags),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		SourceRaw:  req.SourceRaw,
+		Overrides:  req.Overrides,
+	}
+
+	tags, err := json.Marshal(record.Tags)
+	if err != nil {
+		return models.ManifestRecord{}, fmt.Errorf("encode manifest tags: %w", err)
+	}
+	overrides, err := encodeSQLiteOverrides(record.Overrides)
+	if err != nil {
+		return models.ManifestRecord{}, err
+	}
+	_, err = s.db.ExecContext(ctx,
+		"INSERT INTO manifests ("+sqliteManifestColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		record.ID, record.Title, record.Resource, record.APIVersion, record.Kind, record.YAML, record.Note,
+		string(tags), record.CreatedAt.UnixNano(), record.UpdatedAt.UnixNano(), record.SourceRaw, overrides,
+	)
+	if err != nil {
+		return models.ManifestRecord{}, fmt.Errorf("insert manifest: %w", err)
+	}
+	return record, nil
+}
+
+// ListManifests matches the query as a case-insensitive substring of the same
+// fields the Mongo regex search covers.
+func (s *SQLiteManifestStore) ListManifests(ctx context.Context, query string, tags []string, limit, offset int64) (models.ManifestPage, error) {
+	limit = normalizeManifestLimit(limit)
+	offset = max(offset, 0)
+	page := models.ManifestPage{Items: make([]models.ManifestRecord, 0), Limit: limit, Offset: offset}
+
+	where, args := sqliteManifestQueryClause(query)
+	if tags = normalizeTags(tags); len(tags) > 0 {
+		clauses := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			clauses = append(clauses, "EXISTS (SELECT 1 FROM json_each(manifests.tags) WHERE json_each.value = ?)")
+			args = append(args, tag)
+		}
+		if where == "" {
+			where = " WHERE " + strings.Join(clauses, " AND ")
+		} else {
+			where = " WHERE (" + strings.TrimPrefix(where, " WHERE ") + ") AND " + strings.Join(clauses, " AND ")
+		}
+	}
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM manifests"+where, args...).Scan(&page.Total); err != nil {
+		return models.ManifestPage{}, fmt.Errorf("count manifests: %w", err)
+	}
+
+	statement := "SELECT " + sqliteManifestColumns + " FROM manifests" + where + " ORDER BY created_at DESC LIMIT ? OFFSET ?"
+	rows, err := s.db.QueryContext(ctx, statement, append(args, limit, offset)...)
+	if err != nil {
+		return models.ManifestPage{}, fmt.Errorf("list manifests: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record, err := scanManifestRecord(rows)
+		if err != nil {
+			return models.ManifestPage{}, err
+		}
+		page.Items = append(page.Items, record)
+	}
+	if err := rows.Err(); err != nil {
+		return models.ManifestPage{}, fmt.Errorf("manifest rows: %w", err)
+	}
+	return page, nil
+}
+
+// BulkTag rewrites the tags of every selected manifest inside a single
+// transaction.
+func (s *SQLiteManifestStore) BulkTag(ctx context.Context, req models.BulkTagManifestsRequest) (int64, error) {
+	ids, add, remove, err := normalizeBulkTagRequest(req)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := sqliteManifestQueryClause(req.Query)
+	if len(ids) > 0 {
+		where = " WHERE id IN (?" + strings.Repeat(", ?", len(ids)-1) + ")"
+		args = make([]any, 0, len(ids))
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("bulk tag manifests: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT id, tags FROM manifests"+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("bulk tag manifests: %w", err)
+	}
+	type taggedRow struct {
+		id   string
+		tags []string
+	}
+	selected := make([]taggedRow, 0)
+	for rows.Next() {
+		var id, encoded string
+		if err := rows.Scan(&id, &encoded); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("bulk tag manifests: %w", err)
+		}
+		tags, err := decodeSQLiteTags(encoded)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		selected = append(selected, taggedRow{id: id, tags: tags})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("bulk tag manifests: %w", err)
+	}
+
+	now := s.clock().UnixNano()
+	for _, row := range selected {
+		encoded, err := json.Marshal(applyTagChanges(row.tags, add, remove))
+		if err != nil {
+			return 0, fmt.Errorf("encode manifest tags: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE manifests SET tags = ?, updated_at = ? WHERE id = ?", string(encoded), now, row.id); err != nil {
+			return 0, fmt.Errorf("bulk tag manifests: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("bulk tag manifests: %w", err)
+	}
+	return int64(len(selected)), nil
+}
+
+func (s *SQLiteManifestStore) GetManifest(ctx context.Context, id string) (models.ManifestRecord, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+sqliteManifestColumns+" FROM manifests WHERE id = ?", strings.TrimSpace(id))
+	record, err := scanManifestRecord(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ManifestRecord{}, ErrManifestNotFound
+	}
+	if err != nil {
+		return models.ManifestRecord{}, err
+	}
+	return record, nil
+}
+
+func (s *SQLiteManifestStore) DeleteManifest(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM manifests WHERE id = ?", strings.TrimSpace(id))
+	if err != nil {
+		return fmt.Errorf("delete manifest: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrManifestNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteManifestStore) UpdateManifest(ctx context.Context, id string, req models.SaveManifestRequest) (models.ManifestRecord, error) {
+	if strings.TrimSpace(req.YAML) == "" {
+		return models.ManifestRecord{}, fmt.Errorf("yaml is required")
+	}
+	id = strings.TrimSpace(id)
+	overrides := ""
+	if len(req.Overrides) > 0 {
+		encoded, err := encodeSQLiteOverrides(req.Overrides)
+		if err != nil {
+			return models.ManifestRecord{}, err
+		}
+		overrides = encoded
+	}
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE manifests SET title = ?, resource = ?, api_version = ?, kind = ?, yaml = ?, updated_at = ?, "+
+			"source_raw = CASE WHEN ? = '' THEN source_raw ELSE ? END, "+
+			"overrides = CASE WHEN ? = '' THEN overrides ELSE ? END WHERE id = ?",
+		fallback(req.Title, "Manifest"), strings.TrimSpace(req.Resource), strings.TrimSpace(req.APIVersion),
+		strings.TrimSpace(req.Kind), req.YAML, s.clock().UnixNano(),
+		strings.TrimSpace(req.SourceRaw), req.SourceRaw, overrides, overrides, id,
+	)
+	if err != nil {
+		return models.ManifestRecord{}, fmt.Errorf("update manifest: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return models.ManifestRecord{}, ErrManifestNotFound
+	}
+	return s.GetManifest(ctx, id)
+}
+
+func (s *SQLiteManifestStore) UpdateNote(ctx context.Context, id string, note string) (models.ManifestRecord, error) {
+	id = strings.TrimSpace(id)
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE manifests SET note = ?, updated_at = ? WHERE id = ?",
+		strings.TrimSpace(note), s.clock().UnixNano(), id,
+	)
+	if err != nil {
+		return models.ManifestRecord{}, fmt.Errorf("update manifest note: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return models.ManifestRecord{}, ErrManifestNotFound
+	}
+	return s.GetManifest(ctx, id)
+}
+
+func (s *SQLiteManifestStore) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM manifests").Scan(&count); err != nil {
+		return 0, fmt.Errorf("count manifests: %w", err)
+	}
+	return count, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanManifestRecord(row rowScanner) (models.ManifestRecord, error) {
+	var (
+		record             models.ManifestRecord
+		tags, overrides    string
+		createdAt, updated int64
+	)
+	err := row.Scan(
+		&record.ID, &record.Title, &record.Resource, &record.APIVersion, &record.Kind,
+		&record.YAML, &record.Note, &tags, &createdAt, &updated, &record.SourceRaw, &overrides,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ManifestRecord{}, err
+	}
+	if err != nil {
+		return models.ManifestRecord{}, fmt.Errorf("decode manifest: %w", err)
+	}
+	if record.Tags, err = decodeSQLiteTags(tags); err != nil {
+		return models.ManifestRecord{}, err
+	}
+	if err := json.Unmarshal([]byte(overrides), &record.Overrides); err != nil {
+		return models.ManifestRecord{}, fmt.Errorf("decode manifest overrides: %w", err)
+	}
+	record.CreatedAt = time.Unix(0, createdAt).UTC()
+	record.UpdatedAt = time.Unix(0, updated).UTC()
+	return record, nil
+}
+
+func encodeSQLiteOverrides(overrides []models.FieldDefinition) (string, error) {
+	if overrides == nil {
+		overrides = []models.FieldDefinition{}
+	}
+	encoded, err := json.Marshal(overrides)
+	if err != nil {
+		return "", fmt.Errorf("encode manifest overrides: %w", err)
+	}
+	return string(encoded), nil
+}
+
+func decodeSQLiteTags(encoded string) ([]string, error) {
+	var tags []string
+	if err := json.Unmarshal([]byte(encoded), &tags); err != nil {
+		return nil, fmt.Errorf("decode manifest tags: %w", err)
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	return tags, nil
+}
+
+// sqliteManifestQueryClause builds the WHERE clause shared by searches and
+// query-selected bulk operations.
+func sqliteManifestQueryClause(query string) (string, []any) {
+	trimmed := strings.TrimSpace(query)
+	if trimmed == "" {
+		return "", nil
+	}
+	pattern := "%" + escapeLikePattern(strings.ToLower(trimmed)) + "%"
+	columns := []string{"title", "resource", "kind", "api_version", "yaml", "note"}
+	clauses := make([]string, 0, len(columns))
+	args := make([]any, 0, len(columns)+1)
+	for _, column := range columns {
+		clauses = append(clauses, "lower("+column+") LIKE ? ESCAPE '\\'")
+		args = append(args, pattern)
+	}
+	return " WHERE " + strings.Join(clauses, " OR "), args
+}
+
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func NewSQLiteTemplateStore(ctx context.Context, path string, seedFile string) (*SQLiteTemplateStore, error) {
+	db, err := openSQLite(ctx, path, sqliteTemplateSchema)
+	if err != nil {
+		return nil, err
+	}
+	store := &SQLiteTemplateStore{db: db, seeds: seedTemplates(seedFile)}
+	if err := store.seedDefaultsIfEmpty(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("seed templates: %w", err)
+	}
+	return store, nil
+}
+
+func (s *SQLiteTemplateStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *SQLiteTemplateStore) Close(ctx context.Context) error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+func (s *SQLiteTemplateStore) List(ctx context.Context) ([]models.TemplateDefinition, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT document FROM templates ORDER BY title")
+	if err != nil {
+		return nil, fmt.Errorf("list templates: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]models.TemplateDefinition, 0, 16)
+	for rows.Next() {
+		var document string
+		if err := rows.Scan(&document); err != nil {
+			return nil, fmt.Errorf("decode template: %w", err)
+		}
+		var item models.TemplateDefinition
+		if err := json.Unmarshal([]byte(document), &item); err != nil {
+			return nil, fmt.Errorf("decode template: %w", err)
+		}
+		out = append(out, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("template rows: %w", err)
+	}
+
+	if len(out) == 0 {
+		return cloneTemplateList(s.seeds), nil
+	}
+	return out, nil
+}
+
+func (s *SQLiteTemplateStore) Count(ctx context.Context) (int64, error) {
+	count, err := s.storedCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return int64(len(s.seeds)), nil
+	}
+	return count, nil
+}
+
+func (s *SQLiteTemplateStore) Get(ctx context.Context, id string) (models.TemplateDefinition, error) {
+	id = strings.TrimSpace(id)
+	var document string
+	err := s.db.QueryRowContext(ctx, "SELECT document FROM templates WHERE id = ?", id).Scan(&document)
+	if errors.Is(err, sql.ErrNoRows) {
+		if count, countErr := s.storedCount(ctx); countErr == nil && count == 0 {
+			return findTemplate(s.seeds, id)
+		}
+		return models.TemplateDefinition{}, ErrTemplateNotFound
+	}
+	if err != nil {
+		return models.TemplateDefinition{}, fmt.Errorf("get template: %w", err)
+	}
+	var template models.TemplateDefinition
+	if err := json.Unmarshal([]byte(document), &template); err != nil {
+		return models.TemplateDefinition{}, fmt.Errorf("decode template: %w", err)
+	}
+	return template, nil
+}
+
+func (s *SQLiteTemplateStore) Delete(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM templates WHERE id = ?", strings.TrimSpace(id))
+	if err != nil {
+		return fmt.Errorf("delete template: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteTemplateStore) Upsert(ctx context.Context, template models.TemplateDefinition) error {
+	template.ID = strings.TrimSpace(template.ID)
+	if template.ID == "" {
+		return fmt.Errorf("template id is required")
+	}
+	if isBuiltinTemplateID(template.ID) {
+		return ErrBuiltinTemplate
+	}
+	if err := s.put(ctx, template); err != nil {
+		return fmt.Errorf("upsert template: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteTemplateStore) Patch(ctx context.Context, id string, patch models.PatchTemplateRequest) (models.TemplateDefinition, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return models.TemplateDefinition{}, fmt.Errorf("template id is required")
+	}
+	if isBuiltinTemplateID(id) {
+		return models.TemplateDefinition{}, ErrBuiltinTemplate
+	}
+
+	// Like TemplateService.Patch, only write back over the document as read
+	// and retry when another patch got there first.
+	for attempt := 0; attempt < maxTemplatePatchAttempts; attempt++ {
+		var document string
+		err := s.db.QueryRowContext(ctx, "SELECT document FROM templates WHERE id = ?", id).Scan(&document)
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TemplateDefinition{}, ErrTemplateNotFound
+		}
+		if err != nil {
+			return models.TemplateDefinition{}, fmt.Errorf("find template: %w", err)
+		}
+		var current models.TemplateDefinition
+		if err := json.Unmarshal([]byte(document), &current); err != nil {
+			return models.TemplateDefinition{}, fmt.Errorf("decode template: %w", err)
+		}
+
+		updated := applyTemplatePatch(current, patch)
+		encoded, err := json.Marshal(updated)
+		if err != nil {
+			return models.TemplateDefinition{}, fmt.Errorf("patch template: %w", err)
+		}
+		result, err := s.db.ExecContext(ctx,
+			"UPDATE templates SET title = ?, document = ? WHERE id = ? AND document = ?",
+			updated.Title, string(encoded), id, document,
+		)
+		if err != nil {
+			return models.TemplateDefinition{}, fmt.Errorf("patch template: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			return updated, nil
+		}
+	}
+	return models.TemplateDefinition{}, ErrTemplateConflict
+}
+
+func (s *SQLiteTemplateStore) put(ctx context.Context, template models.TemplateDefinition) error {
+	document, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		"INSERT INTO templates (id, title, document) VALUES (?, ?, ?) "+
+			"ON CONFLICT(id) DO UPDATE SET title = excluded.title, document = excluded.document",
+		template.ID, template.Title, string(document),
+	)
+	return err
+}
+
+func (s *SQLiteTemplateStore) storedCount(ctx context.Context) (int64, error) {
+	var count int64
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM templates").Scan(&count); err != nil {
+		return 0, fmt.Errorf("count templates: %w", err)
+	}
+	return count, nil
+}
+
+func (s *SQLiteTemplateStore) seedDefaultsIfEmpty(ctx context.Context) error {
+	count, err := s.storedCount(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	for _, template := range s.seeds {
+		if err := s.put(ctx, template); err != nil {
+			return fmt.Errorf("insert default templates: %w", err)
+		}
+	}
+	return nil
+}
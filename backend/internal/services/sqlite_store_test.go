@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+func TestSQLiteManifestStoreCRUD(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "kubetools.db")
+	store, err := NewSQLiteManifestStore(ctx, path)
+	if err != nil {
+		t.Fatalf("open sqlite store: %v", err)
+	}
+	defer store.Close(ctx)
+
+	web, err := store.SaveManifest(ctx, models.SaveManifestRequest{
+		Title: "web",
+		Kind:  "Deployment",
+		YAML:  "kind: Deployment\n",
+		Note:  "Canary rollout for checkout",
+	})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	if _, err := store.SaveManifest(ctx, models.SaveManifestRequest{Title: "db", Kind: "StatefulSet", YAML: "kind: StatefulSet\n"}); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	if _, err := store.SaveManifest(ctx, models.SaveManifestRequest{YAML: "  "}); err == nil {
+		t.Fatalf("expected empty yaml to be rejected")
+	}
+
+	got, err := store.GetManifest(ctx, web.ID)
+	if err != nil {
+		t.Fatalf("get manifest: %v", err)
+	}
+	if got.Title != "web" || got.Note != web.Note || !got.CreatedAt.Equal(web.CreatedAt) {
+		t.Fatalf("expected stored record to round-trip, got %+v", got)
+	}
+
+	all, err := store.ListManifests(ctx, "", nil, 0, 0)
+	if err != nil {
+		t.Fatalf("list manifests: %v", err)
+	}
+	if len(all.Items) != 2 || all.Total != 2 || all.Items[0].Title != "db" {
+		t.Fatalf("expected newest-first listing of both manifests, got %+v", all)
+	}
+	if second, _ := store.ListManifests(ctx, "", nil, 1, 1); len(second.Items) != 1 || second.Total != 2 || second.Items[0].ID != web.ID {
+		t.Fatalf("expected the second page to hold the older manifest, got %+v", second)
+	}
+
+	for _, query := range []string{"CHECKOUT", "deploy"} {
+		matches, err := store.ListManifests(ctx, query, nil, 10, 0)
+		if err != nil {
+			t.Fatalf("list manifests: %v", err)
+		}
+		if len(matches.Items) != 1 || matches.Total != 1 || matches.Items[0].ID != web.ID {
+			t.Fatalf("expected %q to match only the web manifest, got %+v", query, matches)
+		}
+	}
+	if matches, _ := store.ListManifests(ctx, "100%", nil, 10, 0); len(matches.Items) != 0 {
+		t.Fatalf("expected LIKE wildcards in the query to be matched literally, got %+v", matches)
+	}
+
+	updated, err := store.UpdateNote(ctx, web.ID, "Promoted to prod")
+	if err != nil {
+		t.Fatalf("update note: %v", err)
+	}
+	if updated.Note != "Promoted to prod" {
+		t.Fatalf("expected updated note, got %q", updated.Note)
+	}
+
+	overwritten, err := store.UpdateManifest(ctx, web.ID, models.SaveManifestRequest{Title: "web", Kind: "Deployment", YAML: "kind: Deployment\nreplicas: 3\n"})
+	if err != nil {
+		t.Fatalf("update manifest: %v", err)
+	}
+	if overwritten.YAML != "kind: Deployment\nreplicas: 3\n" || overwritten.Note != "Promoted to prod" || !overwritten.CreatedAt.Equal(web.CreatedAt) {
+		t.Fatalf("expected yaml to be overwritten with note and createdAt preserved, got %+v", overwritten)
+	}
+
+	tagged, err := store.BulkTag(ctx, models.BulkTagManifestsRequest{Query: "promoted", Add: []string{"prod"}})
+	if err != nil || tagged != 1 {
+		t.Fatalf("expected query to tag one manifest, got %d (%v)", tagged, err)
+	}
+	if got, _ := store.GetManifest(ctx, web.ID); len(got.Tags) != 1 || got.Tags[0] != "prod" {
+		t.Fatalf("expected tags to round-trip, got %v", got.Tags)
+	}
+
+	if _, err := store.SaveManifest(ctx, models.SaveManifestRequest{Title: "cache", YAML: "kind: Deployment\n", Tags: []string{"prod", "team-a"}}); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	both, err := store.ListManifests(ctx, "", []string{"prod", "team-a"}, 10, 0)
+	if err != nil {
+		t.Fatalf("list manifests by tag: %v", err)
+	}
+	if both.Total != 1 || both.Items[0].Title != "cache" {
+		t.Fatalf("expected only the manifest carrying both tags, got %+v", both.Items)
+	}
+	if prod, _ := store.ListManifests(ctx, "deploy", []string{"prod"}, 10, 0); prod.Total != 2 {
+		t.Fatalf("expected the query and tag filters to combine, got %+v", prod.Items)
+	}
+
+	if err := store.DeleteManifest(ctx, web.ID); err != nil {
+		t.Fatalf("delete manifest: %v", err)
+	}
+	if _, err := store.GetManifest(ctx, web.ID); !errors.Is(err, ErrManifestNotFound) {
+		t.Fatalf("expected ErrManifestNotFound after delete, got %v", err)
+	}
+	if err := store.DeleteManifest(ctx, web.ID); !errors.Is(err, ErrManifestNotFound) {
+		t.Fatalf("expected ErrManifestNotFound deleting twice, got %v", err)
+	}
+	if count, _ := store.Count(ctx); count != 2 {
+		t.Fatalf("expected two remaining manifests, got %d", count)
+	}
+}
+
+func TestSQLiteManifestStoreMigratesLegacyTableAndKeepsSource(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "kubetools.db")
+
+	legacy, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open legacy db: %v", err)
+	}
+	_, err = legacy.ExecContext(ctx, `CREATE TABLE manifests (
+	id TEXT PRIMARY KEY, title TEXT NOT NULL, resource TEXT NOT NULL DEFAULT '',
+	api_version TEXT NOT NULL DEFAULT '', kind TEXT NOT NULL DEFAULT '', yaml TEXT NOT NULL,
+	note TEXT NOT NULL DEFAULT '', created_at INTEGER NOT NULL, updated_at INTEGER NOT NULL);
+INSERT INTO manifests (id, title, yaml, created_at, updated_at) VALUES ('old', 'old', 'kind: Widget', 1, 1);`)
+	legacy.Close()
+	if err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+
+	store, err := NewSQLiteManifestStore(ctx, path)
+	if err != nil {
+		t.Fatalf("open sqlite store: %v", err)
+	}
+	defer store.Close(ctx)
+
+	old, err := store.GetManifest(ctx, "old")
+	if err != nil || old.SourceRaw != "" || old.Tags != nil {
+		t.Fatalf("expected the legacy row to read back without source or tags, got %+v (%v)", old, err)
+	}
+
+	saved, err := store.SaveManifest(ctx, models.SaveManifestRequest{
+		Title:     "web",
+		YAML:      "kind: Widget\n",
+		SourceRaw: "kind: CustomResourceDefinition\n",
+		Overrides: []models.FieldDefinition{{Path: "spec.size", Value: "small"}},
+	})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	updated, err := store.UpdateManifest(ctx, saved.ID, models.SaveManifestRequest{Title: "web", YAML: "kind: Widget\nspec: {}\n"})
+	if err != nil {
+		t.Fatalf("update manifest: %v", err)
+	}
+	if updated.SourceRaw != saved.SourceRaw || len(updated.Overrides) != 1 || updated.Overrides[0].Value != "small" {
+		t.Fatalf("expected an update without source or overrides to keep the stored ones, got %+v", updated)
+	}
+}
+
+func TestSQLiteTemplateStoreSeedsUpsertsAndPatches(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "kubetools.db")
+	store, err := NewSQLiteTemplateStore(ctx, path, "")
+	if err != nil {
+		t.Fatalf("open sqlite store: %v", err)
+	}
+
+	seeded, err := store.Count(ctx)
+	if err != nil || seeded != int64(len(defaultTemplates())) {
+		t.Fatalf("expected built-in templates to be seeded, got %d (%v)", seeded, err)
+	}
+
+	widget := models.TemplateDefinition{ID: "parsed-widget", Title: "Widget", APIVersion: "example.io/v1", Kind: "Widget"}
+	if err := store.Upsert(ctx, widget); err != nil {
+		t.Fatalf("upsert template: %v", err)
+	}
+	note := "Reviewed"
+	patched, err := store.Patch(ctx, "parsed-widget", models.PatchTemplateRequest{Note: &note})
+	if err != nil {
+		t.Fatalf("patch template: %v", err)
+	}
+	if patched.Note != note || patched.Kind != "Widget" {
+		t.Fatalf("expected only the note to change, got %+v", patched)
+	}
+	if _, err := store.Patch(ctx, "deployment", models.PatchTemplateRequest{Note: &note}); !errors.Is(err, ErrBuiltinTemplate) {
+		t.Fatalf("expected ErrBuiltinTemplate, got %v", err)
+	}
+	if _, err := store.Patch(ctx, "parsed-missing", models.PatchTemplateRequest{Note: &note}); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("expected ErrTemplateNotFound, got %v", err)
+	}
+	if got, err := store.Get(ctx, "parsed-widget"); err != nil || got.Note != note {
+		t.Fatalf("expected patched template from Get, got %+v (%v)", got, err)
+	}
+	if err := store.Delete(ctx, "statefulset"); err != nil {
+		t.Fatalf("delete template: %v", err)
+	}
+	if _, err := store.Get(ctx, "statefulset"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("expected ErrTemplateNotFound after delete, got %v", err)
+	}
+	store.Close(ctx)
+
+	reopened, err := NewSQLiteTemplateStore(ctx, path, "")
+	if err != nil {
+		t.Fatalf("reopen sqlite store: %v", err)
+	}
+	defer reopened.Close(ctx)
+	templates, err := reopened.List(ctx)
+	if err != nil {
+		t.Fatalf("list templates: %v", err)
+	}
+	if len(templates) != len(defaultTemplates()) {
+		t.Fatalf("expected the upsert and delete to persist across reopen without reseeding, got %d", len(templates))
+	}
+}
+
+func TestSQLiteManifestStoreUsesInjectedClock(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewSQLiteManifestStore(ctx, filepath.Join(t.TempDir(), "kubetools.db"))
+	if err != nil {
+		t.Fatalf("open sqlite store: %v", err)
+	}
+	defer store.Close(ctx)
+
+	frozen := time.Date(2024, time.March, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	store.now = func() time.Time { return frozen }
+	saved, err := store.SaveManifest(ctx, models.SaveManifestRequest{Title: "web", YAML: "kind: ConfigMap\n"})
+	if err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	if !saved.CreatedAt.Equal(frozen) || !saved.UpdatedAt.Equal(frozen) {
+		t.Fatalf("expected timestamps %v, got %+v", frozen.UTC(), saved)
+	}
+
+	later := frozen.Add(time.Hour)
+	store.now = func() time.Time { return later }
+	updated, err := store.UpdateNote(ctx, saved.ID, "checked")
+	if err != nil {
+		t.Fatalf("update note: %v", err)
+	}
+	if !updated.CreatedAt.Equal(frozen) || !updated.UpdatedAt.Equal(later) {
+		t.Fatalf("expected CreatedAt %v and UpdatedAt %v, got %+v", frozen.UTC(), later.UTC(), updated)
+	}
+
+	latest := later.Add(time.Hour)
+	store.now = func() time.Time { return latest }
+	updated, err = store.UpdateManifest(ctx, saved.ID, models.SaveManifestRequest{Title: "web", YAML: "kind: Secret\n"})
+	if err != nil {
+		t.Fatalf("update manifest: %v", err)
+	}
+	if !updated.UpdatedAt.Equal(latest) {
+		t.Fatalf("expected UpdatedAt %v, got %v", latest.UTC(), updated.UpdatedAt)
+	}
+}
+
+func TestSQLiteTemplateStoreConcurrentPatchesKeepEveryChange(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewSQLiteTemplateStore(ctx, filepath.Join(t.TempDir(), "kubetools.db"), "")
+	if err != nil {
+		t.Fatalf("open sqlite store: %v", err)
+	}
+	defer store.Close(ctx)
+	if err := store.Upsert(ctx, models.TemplateDefinition{ID: "parsed-widget", Title: "Widget", Kind: "Widget"}); err != nil {
+		t.Fatalf("upsert template: %v", err)
+	}
+
+	paths := []string{"spec.a", "spec.b", "spec.c", "spec.d"}
+	var wg sync.WaitGroup
+	errs := make(chan error, len(paths))
+	for _, path := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.Patch(ctx, "parsed-widget", models.PatchTemplateRequest{AddFields: []models.FieldDefinition{{Path: path}}})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("patch template: %v", err)
+		}
+	}
+
+	got, err := store.Get(ctx, "parsed-widget")
+	if err != nil {
+		t.Fatalf("get template: %v", err)
+	}
+	if len(got.DefaultFields) != len(paths) {
+		t.Fatalf("expected every concurrent patch to land, got %+v", got.DefaultFields)
+	}
+}
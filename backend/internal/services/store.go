@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// ManifestStore is the manifest persistence API the HTTP layer depends on.
+// ManifestService implements it on top of MongoDB with an in-memory fallback.
+type ManifestStore interface {
+	SaveManifest(ctx context.Context, req models.SaveManifestRequest) (models.ManifestRecord, error)
+	ListManifests(ctx context.Context, query string, tags []string, limit, offset int64) (models.ManifestPage, error)
+	GetManifest(ctx context.Context, id string) (models.ManifestRecord, error)
+	DeleteManifest(ctx context.Context, id string) error
+	UpdateManifest(ctx context.Context, id string, req models.SaveManifestRequest) (models.ManifestRecord, error)
+	UpdateNote(ctx context.Context, id string, note string) (models.ManifestRecord, error)
+	BulkTag(ctx context.Context, req models.BulkTagManifestsRequest) (int64, error)
+	Count(ctx context.Context) (int64, error)
+	Close(ctx context.Context) error
+}
+
+// ManifestCompactor is implemented by stores that support the admin compact
+// operation.
+type ManifestCompactor interface {
+	Compact(ctx context.Context, req models.CompactManifestsRequest) (models.CompactManifestsResponse, error)
+}
+
+// TemplateStore is the template persistence API the HTTP layer depends on.
+// TemplateService implements it on top of MongoDB with an in-memory fallback.
+type TemplateStore interface {
+	List(ctx context.Context) ([]models.TemplateDefinition, error)
+	Get(ctx context.Context, id string) (models.TemplateDefinition, error)
+	Delete(ctx context.Context, id string) error
+	Upsert(ctx context.Context, template models.TemplateDefinition) error
+	Patch(ctx context.Context, id string, patch models.PatchTemplateRequest) (models.TemplateDefinition, error)
+	Count(ctx context.Context) (int64, error)
+	Close(ctx context.Context) error
+}
+
+var (
+	_ ManifestStore     = (*ManifestService)(nil)
+	_ ManifestCompactor = (*ManifestService)(nil)
+	_ TemplateStore     = (*TemplateService)(nil)
+	_ Pinger            = (*ManifestService)(nil)
+	_ Pinger            = (*TemplateService)(nil)
+)
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultStripPaths lists the server-populated fields removed when cleaning an
+// exported manifest. Each entry is a dotted path whose segments may use
+// path.Match globs, e.g. "metadata.annotations.*"; in these "*" also spans
+// the "/" in keys such as "kubectl.kubernetes.io/last-applied-configuration".
+var DefaultStripPaths = []string{
+	"status",
+	"metadata.managedFields",
+	"metadata.resourceVersion",
+	"metadata.uid",
+	"metadata.generation",
+	"metadata.creationTimestamp",
+	"metadata.selfLink",
+	"metadata.annotations.kubectl.kubernetes.io/last-applied-configuration",
+}
+
+// StripFields removes every path matching the policy from each document in
+// raw, preserving key order and comments in what remains.
+func (s *YAMLService) StripFields(raw string, policy []string) (string, []string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", nil, errors.New("yaml payload is empty")
+	}
+	patterns := compileStripPolicy(stripPathsOrDefault(policy))
+
+	decoder := yaml.NewDecoder(strings.NewReader(raw))
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+
+	removed := make([]string, 0)
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("YAML parse error: %w", err)
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		stripNode(doc.Content[0], nil, patterns, &removed)
+		if err := encoder.Encode(&doc); err != nil {
+			return "", nil, fmt.Errorf("marshal YAML: %w", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return "", nil, fmt.Errorf("marshal YAML: %w", err)
+	}
+	return out.String(), removed, nil
+}
+
+// stripPathsOrDefault returns policy, or DefaultStripPaths when it is empty.
+func stripPathsOrDefault(policy []string) []string {
+	if len(policy) == 0 {
+		return DefaultStripPaths
+	}
+	return policy
+}
+
+func stripNode(node *yaml.Node, prefix []string, patterns [][]string, removed *[]string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		kept := make([]*yaml.Node, 0, len(node.Content))
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			current := append(append([]string(nil), prefix...), key.Value)
+			if matchesStripPolicy(current, patterns) {
+				*removed = append(*removed, strings.Join(current, "."))
+				continue
+			}
+			stripNode(value, current, patterns, removed)
+			kept = append(kept, key, value)
+		}
+		node.Content = kept
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			stripNode(item, prefix, patterns, removed)
+		}
+	}
+}
+
+// stripMap is the decoded-map counterpart of stripNode, used on parse paths
+// that already work with map[string]any documents.
+func stripMap(root map[string]any, prefix []string, patterns [][]string) {
+	for key, value := range root {
+		current := append(append([]string(nil), prefix...), key)
+		if matchesStripPolicy(current, patterns) {
+			delete(root, key)
+			continue
+		}
+		switch typed := value.(type) {
+		case map[string]any:
+			stripMap(typed, current, patterns)
+		case []any:
+			for _, item := range typed {
+				if child, ok := item.(map[string]any); ok {
+					stripMap(child, current, patterns)
+				}
+			}
+		}
+	}
+}
+
+// compileStripPolicy splits each policy path into segments. Annotation and
+// label keys contain dots themselves, so everything after a
+// "metadata.annotations." or "metadata.labels." prefix, at any depth, is kept
+// as a single key segment.
+func compileStripPolicy(policy []string) [][]string {
+	patterns := make([][]string, 0, len(policy))
+	for _, entry := range policy {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		segments := strings.Split(entry, ".")
+		for i := 0; i+2 < len(segments); i++ {
+			if segments[i] == "metadata" && (segments[i+1] == "annotations" || segments[i+1] == "labels") {
+				segments = append(segments[:i+2], strings.Join(segments[i+2:], "."))
+				break
+			}
+		}
+		patterns = append(patterns, segments)
+	}
+	return patterns
+}
+
+func matchesStripPolicy(segments []string, patterns [][]string) bool {
+	for _, pattern := range patterns {
+		if len(pattern) != len(segments) {
+			continue
+		}
+		matched := true
+		for i := range pattern {
+			if !matchStripSegment(pattern[i], segments[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchStripSegment is path.Match except that "*" and "?" also match "/",
+// which annotation and label keys such as "app.kubernetes.io/name" contain.
+func matchStripSegment(pattern, segment string) bool {
+	ok, err := path.Match(strings.ReplaceAll(pattern, "/", "\x00"), strings.ReplaceAll(segment, "/", "\x00"))
+	return err == nil && ok
+}
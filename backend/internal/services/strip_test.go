@@ -0,0 +1,140 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+func TestStripFieldsRemovesStatusAndUID(t *testing.T) {
+	service := NewYAMLService()
+	raw := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  uid: 4f3c2a10-1111-2222-3333-444455556666
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: '{}'
+    team: payments
+spec:
+  replicas: 2
+status:
+  readyReplicas: 2
+`
+
+	output, removed, err := service.StripFields(raw, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, gone := range []string{"status:", "readyReplicas", "uid:", "last-applied-configuration"} {
+		if strings.Contains(output, gone) {
+			t.Fatalf("expected %q to be stripped, got %s", gone, output)
+		}
+	}
+	for _, kept := range []string{"name: web", "team: payments", "replicas: 2"} {
+		if !strings.Contains(output, kept) {
+			t.Fatalf("expected %q to be kept, got %s", kept, output)
+		}
+	}
+	if strings.Index(output, "metadata:") > strings.Index(output, "spec:") {
+		t.Fatalf("expected original key order to be preserved, got %s", output)
+	}
+	if len(removed) != 3 {
+		t.Fatalf("expected 3 removed paths, got %v", removed)
+	}
+
+	output, _, err = service.StripFields(raw, []string{"metadata.uid"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Contains(output, "uid:") || !strings.Contains(output, "readyReplicas") {
+		t.Fatalf("expected custom policy to strip only metadata.uid, got %s", output)
+	}
+}
+
+func TestParseResourceAppliesConfiguredStripPolicy(t *testing.T) {
+	raw := `apiVersion: example.io/v1
+kind: Widget
+metadata:
+  name: web
+spec:
+  replicas: 2
+  paused: false
+`
+	fieldPaths := func(template models.TemplateDefinition) map[string]bool {
+		paths := make(map[string]bool)
+		for _, field := range append(template.DefaultFields, template.OptionalFields...) {
+			paths[field.Path] = true
+		}
+		return paths
+	}
+
+	template, _, err := NewCRDService(config.Config{}).ParseCRDWithOptions(raw, ParseOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if paths := fieldPaths(template); !paths["spec.replicas"] || !paths["spec.paused"] {
+		t.Fatalf("expected the default policy to keep spec fields, got %v", paths)
+	}
+
+	configured := NewCRDService(config.Config{StripPaths: []string{"spec.paused"}})
+	template, _, err = configured.ParseCRDWithOptions(raw, ParseOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if paths := fieldPaths(template); paths["spec.paused"] || !paths["spec.replicas"] {
+		t.Fatalf("expected the configured policy to replace the default, got %v", paths)
+	}
+
+	template, _, err = configured.ParseCRDWithOptions(raw, ParseOptions{StripPaths: []string{"spec.replicas"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if paths := fieldPaths(template); paths["spec.replicas"] || !paths["spec.paused"] {
+		t.Fatalf("expected the request policy to override the configured one, got %v", paths)
+	}
+}
+
+func TestStripFieldsGlobsSpanSlashesInMetadataKeys(t *testing.T) {
+	service := NewYAMLService()
+	raw := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: '{}'
+    deployment.kubernetes.io/revision: "3"
+  labels:
+    app.kubernetes.io/name: web
+    team: payments
+spec:
+  template:
+    metadata:
+      annotations:
+        checksum/config: abc123
+      labels:
+        app.kubernetes.io/name: web
+`
+
+	output, removed, err := service.StripFields(raw, []string{
+		"metadata.annotations.*",
+		"metadata.labels.app.kubernetes.io/*",
+		"spec.template.metadata.annotations.checksum/config",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, gone := range []string{"last-applied-configuration", "revision", "checksum/config"} {
+		if strings.Contains(output, gone) {
+			t.Fatalf("expected %q to be stripped, got %s", gone, output)
+		}
+	}
+	if !strings.Contains(output, "team: payments") || strings.Count(output, "app.kubernetes.io/name") != 1 {
+		t.Fatalf("expected only the top-level app.kubernetes.io label to be stripped, got %s", output)
+	}
+	if len(removed) != 4 {
+		t.Fatalf("expected 4 removed paths, got %v", removed)
+	}
+}
@@ -2,7 +2,13 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -13,6 +19,13 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrBuiltinTemplate  = errors.New("built-in templates are read-only; clone the template under a new id first")
+	ErrTemplateConflict = errors.New("template kept changing during the patch; retry the request")
 )
 
 type TemplateService struct {
@@ -20,12 +33,19 @@ type TemplateService struct {
 	collection *mongo.Collection
 	mu         sync.RWMutex
 	templates  []models.TemplateDefinition
+	seeds      []models.TemplateDefinition
+	limiter    opLimiter
 }
 
 func NewTemplateService(ctx context.Context, cfg config.Config) (*TemplateService, error) {
-	service := &TemplateService{templates: defaultTemplates()}
+	seeds := seedTemplates(cfg.TemplateSeedFile)
+	service := &TemplateService{
+		templates: cloneTemplateList(seeds),
+		seeds:     seeds,
+		limiter:   newOpLimiter(cfg.MongoMaxConcurrentOps),
+	}
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+	client, err := mongo.Connect(ctx, mongoClientOptions(cfg))
 	if err != nil {
 		return service, fmt.Errorf("connect mongodb: %w", err)
 	}
@@ -69,6 +89,12 @@ func (s *TemplateService) List(ctx context.Context) ([]models.TemplateDefinition
 		return cloneTemplateList(s.templates), nil
 	}
 
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	cursor, err := s.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "title", Value: 1}}))
 	if err != nil {
 		return nil, fmt.Errorf("list templates: %w", err)
@@ -88,17 +114,113 @@ func (s *TemplateService) List(ctx context.Context) ([]models.TemplateDefinition
 	}
 
 	if len(out) == 0 {
-		return cloneTemplateList(defaultTemplates()), nil
+		return cloneTemplateList(s.seedTemplates()), nil
 	}
 
 	return out, nil
 }
 
+// Ping checks that MongoDB is reachable. It returns ErrMongoUnavailable when
+// the service is running on its in-memory fallback.
+func (s *TemplateService) Ping(ctx context.Context) error {
+	return pingMongo(ctx, s.client, s.limiter)
+}
+
+// Count returns the number of templates List would return.
+func (s *TemplateService) Count(ctx context.Context) (int64, error) {
+	if s.collection == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return int64(len(s.templates)), nil
+	}
+
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	count, err := s.collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("count templates: %w", err)
+	}
+	if count == 0 {
+		return int64(len(s.seedTemplates())), nil
+	}
+	return count, nil
+}
+
+// Get returns one template by id. Until anything is stored, the seed
+// templates List falls back to are served as well.
+func (s *TemplateService) Get(ctx context.Context, id string) (models.TemplateDefinition, error) {
+	id = strings.TrimSpace(id)
+	if s.collection == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return findTemplate(s.templates, id)
+	}
+
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return models.TemplateDefinition{}, err
+	}
+	defer release()
+
+	var template models.TemplateDefinition
+	err = s.collection.FindOne(ctx, bson.M{"id": id}).Decode(&template)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		count, countErr := s.collection.CountDocuments(ctx, bson.M{})
+		if countErr == nil && count == 0 {
+			return findTemplate(s.seedTemplates(), id)
+		}
+		return models.TemplateDefinition{}, ErrTemplateNotFound
+	}
+	if err != nil {
+		return models.TemplateDefinition{}, fmt.Errorf("get template: %w", err)
+	}
+	return template, nil
+}
+
+// Delete removes a template. Built-in templates may be deleted too; this only
+// drops them from the collection.
+func (s *TemplateService) Delete(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if s.collection == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i := range s.templates {
+			if s.templates[i].ID == id {
+				s.templates = append(s.templates[:i], s.templates[i+1:]...)
+				return nil
+			}
+		}
+		return ErrTemplateNotFound
+	}
+
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	result, err := s.collection.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return fmt.Errorf("delete template: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
 func (s *TemplateService) Upsert(ctx context.Context, template models.TemplateDefinition) error {
 	template.ID = strings.TrimSpace(template.ID)
 	if template.ID == "" {
 		return fmt.Errorf("template id is required")
 	}
+	if isBuiltinTemplateID(template.ID) {
+		return ErrBuiltinTemplate
+	}
 
 	if s.collection == nil {
 		s.mu.Lock()
@@ -107,7 +229,13 @@ func (s *TemplateService) Upsert(ctx context.Context, template models.TemplateDe
 		return nil
 	}
 
-	_, err := s.collection.UpdateOne(
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	_, err = s.collection.UpdateOne(
 		ctx,
 		bson.M{"id": template.ID},
 		bson.M{"$set": template},
@@ -119,6 +247,100 @@ func (s *TemplateService) Upsert(ctx context.Context, template models.TemplateDe
 	return nil
 }
 
+// maxTemplatePatchAttempts bounds how often Patch retries after another
+// writer changed the template between its read and write.
+const maxTemplatePatchAttempts = 5
+
+// Patch applies a sparse update to a stored template, leaving every part the
+// request does not mention untouched.
+func (s *TemplateService) Patch(ctx context.Context, id string, patch models.PatchTemplateRequest) (models.TemplateDefinition, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return models.TemplateDefinition{}, fmt.Errorf("template id is required")
+	}
+	if isBuiltinTemplateID(id) {
+		return models.TemplateDefinition{}, ErrBuiltinTemplate
+	}
+
+	if s.collection == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i := range s.templates {
+			if s.templates[i].ID != id {
+				continue
+			}
+			s.templates[i] = applyTemplatePatch(s.templates[i], patch)
+			return s.templates[i], nil
+		}
+		return models.TemplateDefinition{}, ErrTemplateNotFound
+	}
+
+	release, err := s.limiter.acquire(ctx)
+	if err != nil {
+		return models.TemplateDefinition{}, err
+	}
+	defer release()
+
+	// Apply the patch to the document as read and only write it back if the
+	// stored document is still exactly that one, so two concurrent patches
+	// can't overwrite each other's changes. A lost race re-reads and retries.
+	for attempt := 0; attempt < maxTemplatePatchAttempts; attempt++ {
+		var stored bson.Raw
+		err = s.collection.FindOne(ctx, bson.M{"id": id}).Decode(&stored)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.TemplateDefinition{}, ErrTemplateNotFound
+		}
+		if err != nil {
+			return models.TemplateDefinition{}, fmt.Errorf("find template: %w", err)
+		}
+		var current models.TemplateDefinition
+		if err := bson.Unmarshal(stored, &current); err != nil {
+			return models.TemplateDefinition{}, fmt.Errorf("decode template: %w", err)
+		}
+
+		updated := applyTemplatePatch(current, patch)
+		unchanged := bson.M{
+			"id":    id,
+			"$expr": bson.M{"$eq": bson.A{"$$ROOT", bson.M{"$literal": stored}}},
+		}
+		result, err := s.collection.UpdateOne(ctx, unchanged, bson.M{"$set": updated})
+		if err != nil {
+			return models.TemplateDefinition{}, fmt.Errorf("patch template: %w", err)
+		}
+		if result.MatchedCount > 0 {
+			return updated, nil
+		}
+	}
+	return models.TemplateDefinition{}, ErrTemplateConflict
+}
+
+// VisibleTemplates returns the templates a team may see: every global
+// template plus those scoped to the team. An empty team sees only global
+// templates.
+func VisibleTemplates(templates []models.TemplateDefinition, team string) []models.TemplateDefinition {
+	out := make([]models.TemplateDefinition, 0, len(templates))
+	for _, template := range templates {
+		if TemplateVisible(template, team) {
+			out = append(out, template)
+		}
+	}
+	return out
+}
+
+// TemplateVisible applies the VisibleTemplates rule to a single template.
+func TemplateVisible(template models.TemplateDefinition, team string) bool {
+	return template.Scope == "" || template.Scope == strings.TrimSpace(team)
+}
+
+func findTemplate(templates []models.TemplateDefinition, id string) (models.TemplateDefinition, error) {
+	for _, template := range templates {
+		if template.ID == id {
+			return cloneTemplateList([]models.TemplateDefinition{template})[0], nil
+		}
+	}
+	return models.TemplateDefinition{}, ErrTemplateNotFound
+}
+
 func (s *TemplateService) seedDefaultsIfEmpty(ctx context.Context) error {
 	if s.collection == nil {
 		return nil
@@ -132,7 +354,7 @@ func (s *TemplateService) seedDefaultsIfEmpty(ctx context.Context) error {
 		return nil
 	}
 
-	defaults := defaultTemplates()
+	defaults := s.seedTemplates()
 	docs := make([]any, 0, len(defaults))
 	for _, template := range defaults {
 		docs = append(docs, template)
@@ -148,6 +370,178 @@ func (s *TemplateService) seedDefaultsIfEmpty(ctx context.Context) error {
 	return nil
 }
 
+func (s *TemplateService) seedTemplates() []models.TemplateDefinition {
+	if len(s.seeds) == 0 {
+		return defaultTemplates()
+	}
+	return s.seeds
+}
+
+// seedTemplates returns the baseline template set: the templates in the
+// configured seed file when it loads and validates, the built-ins otherwise.
+func seedTemplates(seedFile string) []models.TemplateDefinition {
+	if seedFile == "" {
+		return defaultTemplates()
+	}
+
+	file, err := os.Open(seedFile)
+	if err != nil {
+		log.Printf("template seed file %q: %v (using built-in templates)", seedFile, err)
+		return defaultTemplates()
+	}
+	defer file.Close()
+
+	templates, err := loadSeedTemplates(file)
+	if err != nil {
+		log.Printf("template seed file %q: %v (using built-in templates)", seedFile, err)
+		return defaultTemplates()
+	}
+	return templates
+}
+
+// loadSeedTemplates decodes a JSON array of templates and rejects the whole
+// set if any entry is unusable, so a typo never ships half a baseline.
+func loadSeedTemplates(r io.Reader) ([]models.TemplateDefinition, error) {
+	var templates []models.TemplateDefinition
+	if err := json.NewDecoder(r).Decode(&templates); err != nil {
+		return nil, fmt.Errorf("decode seed templates: %w", err)
+	}
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("seed file contains no templates")
+	}
+
+	seen := make(map[string]struct{}, len(templates))
+	for i := range templates {
+		if err := validateTemplateDefinition(&templates[i]); err != nil {
+			return nil, fmt.Errorf("template %d: %w", i, err)
+		}
+		if _, exists := seen[templates[i].ID]; exists {
+			return nil, fmt.Errorf("template %d: duplicate id %q", i, templates[i].ID)
+		}
+		seen[templates[i].ID] = struct{}{}
+	}
+	return templates, nil
+}
+
+// DecodeTemplateBundle reads a JSON or YAML array of templates. YAML is
+// round-tripped through JSON so both formats use the templates' JSON keys.
+func DecodeTemplateBundle(raw []byte) ([]models.TemplateDefinition, error) {
+	var generic any
+	if err := yaml.Unmarshal(raw, &generic); err != nil {
+		return nil, fmt.Errorf("decode template bundle: %w", err)
+	}
+	if _, ok := generic.([]any); !ok {
+		return nil, fmt.Errorf("template bundle must be an array of templates")
+	}
+	encoded, err := json.Marshal(generic)
+	if err != nil {
+		return nil, fmt.Errorf("decode template bundle: %w", err)
+	}
+	var templates []models.TemplateDefinition
+	if err := json.Unmarshal(encoded, &templates); err != nil {
+		return nil, fmt.Errorf("decode template bundle: %w", err)
+	}
+	return templates, nil
+}
+
+// ExportTemplateBundle encodes templates sorted by id as a JSON or YAML
+// array that DecodeTemplateBundle reads back. Built-ins are left out unless
+// includeBuiltins is set, since importing them would be rejected.
+func ExportTemplateBundle(templates []models.TemplateDefinition, includeBuiltins bool, format string) ([]byte, error) {
+	bundle := make([]models.TemplateDefinition, 0, len(templates))
+	for _, template := range templates {
+		if includeBuiltins || !isBuiltinTemplateID(template.ID) {
+			bundle = append(bundle, template)
+		}
+	}
+	sort.Slice(bundle, func(i, j int) bool { return bundle[i].ID < bundle[j].ID })
+
+	encoded, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode template bundle: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "json":
+		return append(encoded, '\n'), nil
+	case "yaml", "yml":
+		// Round-trip through JSON so YAML keys match the JSON field names.
+		var generic any
+		if err := json.Unmarshal(encoded, &generic); err != nil {
+			return nil, fmt.Errorf("encode template bundle: %w", err)
+		}
+		return yaml.Marshal(generic)
+	default:
+		return nil, fmt.Errorf("unsupported bundle format %q: use json or yaml", format)
+	}
+}
+
+// ImportTemplateBundle validates and upserts each template independently, so
+// one bad entry is reported without blocking the rest of the bundle. Every
+// template is scoped to team, whatever the bundle says, and IDs already held
+// by another scope are rejected rather than taken over.
+func ImportTemplateBundle(ctx context.Context, store TemplateStore, templates []models.TemplateDefinition, team string) models.ImportTemplateBundleResponse {
+	result := models.ImportTemplateBundleResponse{Errors: make([]string, 0)}
+	seen := make(map[string]struct{}, len(templates))
+	reject := func(i int, err error) {
+		result.Rejected++
+		result.Errors = append(result.Errors, fmt.Sprintf("template %d: %v", i, err))
+	}
+
+	for i := range templates {
+		template := templates[i]
+		template.Scope = strings.TrimSpace(team)
+		if err := validateTemplateDefinition(&template); err != nil {
+			reject(i, err)
+			continue
+		}
+		if _, duplicate := seen[template.ID]; duplicate {
+			reject(i, fmt.Errorf("duplicate id %q", template.ID))
+			continue
+		}
+		seen[template.ID] = struct{}{}
+
+		existing, getErr := store.Get(ctx, template.ID)
+		exists := getErr == nil
+		if getErr != nil && !errors.Is(getErr, ErrTemplateNotFound) {
+			reject(i, getErr)
+			continue
+		}
+		if exists && existing.Scope != template.Scope {
+			reject(i, fmt.Errorf("id %q belongs to another scope", template.ID))
+			continue
+		}
+		if err := store.Upsert(ctx, template); err != nil {
+			reject(i, err)
+			continue
+		}
+		if exists {
+			result.Updated++
+		} else {
+			result.Created++
+		}
+	}
+	return result
+}
+
+func validateTemplateDefinition(template *models.TemplateDefinition) error {
+	template.ID = strings.TrimSpace(template.ID)
+	if template.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if strings.TrimSpace(template.APIVersion) == "" {
+		return fmt.Errorf("template %q: apiVersion is required", template.ID)
+	}
+	if strings.TrimSpace(template.Kind) == "" {
+		return fmt.Errorf("template %q: kind is required", template.ID)
+	}
+	for _, field := range append(append([]models.FieldDefinition(nil), template.DefaultFields...), template.OptionalFields...) {
+		if strings.TrimSpace(field.Path) == "" {
+			return fmt.Errorf("template %q: field path is required", template.ID)
+		}
+	}
+	return nil
+}
+
 func cloneTemplateList(in []models.TemplateDefinition) []models.TemplateDefinition {
 	out := make([]models.TemplateDefinition, len(in))
 	copy(out, in)
@@ -164,6 +558,99 @@ func upsertTemplateInMemory(list []models.TemplateDefinition, template models.Te
 	return append(list, template)
 }
 
+func applyTemplatePatch(template models.TemplateDefinition, patch models.PatchTemplateRequest) models.TemplateDefinition {
+	if patch.Title != nil {
+		template.Title = strings.TrimSpace(*patch.Title)
+	}
+	if patch.Note != nil {
+		template.Note = strings.TrimSpace(*patch.Note)
+	}
+	if patch.Category != nil {
+		template.Category = strings.TrimSpace(*patch.Category)
+	}
+
+	if len(patch.RemoveFields) > 0 || len(patch.AddFields) > 0 || len(patch.AddOptionalFields) > 0 {
+		drop := make(map[string]struct{}, len(patch.RemoveFields)+len(patch.AddFields)+len(patch.AddOptionalFields))
+		for _, path := range patch.RemoveFields {
+			drop[strings.TrimSpace(path)] = struct{}{}
+		}
+		for _, field := range patch.AddFields {
+			drop[strings.TrimSpace(field.Path)] = struct{}{}
+		}
+		for _, field := range patch.AddOptionalFields {
+			drop[strings.TrimSpace(field.Path)] = struct{}{}
+		}
+
+		template.DefaultFields = dedupeFields(append(withoutFieldPaths(template.DefaultFields, drop), patch.AddFields...))
+		template.OptionalFields = dedupeFields(append(withoutFieldPaths(template.OptionalFields, drop), patch.AddOptionalFields...))
+	}
+	return template
+}
+
+func withoutFieldPaths(fields []models.FieldDefinition, drop map[string]struct{}) []models.FieldDefinition {
+	out := make([]models.FieldDefinition, 0, len(fields))
+	for _, field := range fields {
+		if _, ok := drop[field.Path]; ok {
+			continue
+		}
+		out = append(out, field)
+	}
+	return out
+}
+
+// maxTemplateIDSuffix bounds the search for a free ID in UniqueTemplateID.
+const maxTemplateIDSuffix = 100
+
+// UniqueTemplateID returns the ID to persist template under without
+// overwriting a different resource type. normalizeID folds case, so CRDs such
+// as FooBar and Foobar would otherwise share one ID; when the stored template
+// at that ID has another group or kind, a "-2", "-3", ... suffix is tried
+// instead. Re-importing the same group and kind keeps its existing ID, but
+// only within the same scope: another team's template counts as taken, so
+// template.Scope must be set before calling.
+func UniqueTemplateID(ctx context.Context, store TemplateStore, template models.TemplateDefinition) (string, error) {
+	base := strings.TrimSpace(template.ID)
+	for n := 1; n <= maxTemplateIDSuffix; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, n)
+		}
+		existing, err := store.Get(ctx, candidate)
+		if errors.Is(err, ErrTemplateNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if existing.Scope == template.Scope && sameResourceType(existing, template) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no free template id for %q after %d attempts", base, maxTemplateIDSuffix)
+}
+
+// sameResourceType compares group and kind, ignoring the version so a CRD
+// re-imported at a newer version replaces its earlier template.
+func sameResourceType(a, b models.TemplateDefinition) bool {
+	groupOf := func(apiVersion string) string {
+		group, _, found := strings.Cut(apiVersion, "/")
+		if !found {
+			return ""
+		}
+		return group
+	}
+	return a.Kind == b.Kind && groupOf(a.APIVersion) == groupOf(b.APIVersion)
+}
+
+func isBuiltinTemplateID(id string) bool {
+	for _, template := range defaultTemplates() {
+		if template.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 func defaultTemplates() []models.TemplateDefinition {
 	return []models.TemplateDefinition{
 		{
@@ -184,6 +671,23 @@ func defaultTemplates() []models.TemplateDefinition {
 				{Path: "spec.strategy.type", Description: "Deployment strategy type."},
 				{Path: "spec.template.spec.containers[0].ports[0].containerPort", Type: "number", Description: "Exposed container port."},
 				{Path: "spec.template.spec.imagePullSecrets[0].name", Description: "Image pull secret."},
+				{Path: "spec.template.spec.initContainers[0].name", Description: "Init container name; runs to completion before app containers start."},
+				{Path: "spec.template.spec.initContainers[0].image", Description: "Init container image."},
+				{Path: "spec.template.spec.initContainers[0].command[0]", Description: "Init container entrypoint."},
+				{Path: "spec.template.spec.containers[1].name", Description: "Sidecar container name (e.g. log shipper or proxy)."},
+				{Path: "spec.template.spec.containers[1].image", Description: "Sidecar container image."},
+				{Path: "spec.template.spec.containers[1].ports[0].containerPort", Type: "number", Description: "Sidecar container port."},
+				{Path: "spec.template.spec.containers[0].readinessProbe.httpGet.path", Description: "HTTP path polled to decide when the container can receive traffic."},
+				{Path: "spec.template.spec.containers[0].readinessProbe.httpGet.port", Type: "number", Description: "Port for the readiness probe."},
+				{Path: "spec.template.spec.containers[0].readinessProbe.initialDelaySeconds", Type: "number", Description: "Seconds to wait after start before the first readiness check."},
+				{Path: "spec.template.spec.containers[0].readinessProbe.periodSeconds", Type: "number", Description: "Seconds between readiness checks."},
+				{Path: "spec.template.spec.containers[0].livenessProbe.httpGet.path", Description: "HTTP path polled to decide when the container should be restarted."},
+				{Path: "spec.template.spec.containers[0].livenessProbe.httpGet.port", Type: "number", Description: "Port for the liveness probe."},
+				{Path: "spec.template.spec.containers[0].livenessProbe.initialDelaySeconds", Type: "number", Description: "Seconds to wait after start before the first liveness check."},
+				{Path: "spec.template.spec.containers[0].resources.requests.cpu", Description: "CPU reserved for scheduling (e.g. 250m)."},
+				{Path: "spec.template.spec.containers[0].resources.requests.memory", Description: "Memory reserved for scheduling (e.g. 256Mi)."},
+				{Path: "spec.template.spec.containers[0].resources.limits.cpu", Description: "CPU ceiling; usage above it is throttled."},
+				{Path: "spec.template.spec.containers[0].resources.limits.memory", Description: "Memory ceiling; exceeding it gets the container OOM-killed."},
 			},
 		},
 		{
@@ -207,6 +711,17 @@ func defaultTemplates() []models.TemplateDefinition {
 				{Path: "spec.volumeClaimTemplates[0].spec.resources.requests.storage", Description: "Per-pod requested storage."},
 				{Path: "spec.persistentVolumeClaimRetentionPolicy.whenDeleted", Description: "PVC retention policy."},
 				{Path: "spec.persistentVolumeClaimRetentionPolicy.whenScaled", Description: "PVC retention when scaling down."},
+				{Path: "spec.template.spec.containers[0].readinessProbe.httpGet.path", Description: "HTTP path polled to decide when the container can receive traffic."},
+				{Path: "spec.template.spec.containers[0].readinessProbe.httpGet.port", Type: "number", Description: "Port for the readiness probe."},
+				{Path: "spec.template.spec.containers[0].readinessProbe.initialDelaySeconds", Type: "number", Description: "Seconds to wait after start before the first readiness check."},
+				{Path: "spec.template.spec.containers[0].readinessProbe.periodSeconds", Type: "number", Description: "Seconds between readiness checks."},
+				{Path: "spec.template.spec.containers[0].livenessProbe.httpGet.path", Description: "HTTP path polled to decide when the container should be restarted."},
+				{Path: "spec.template.spec.containers[0].livenessProbe.httpGet.port", Type: "number", Description: "Port for the liveness probe."},
+				{Path: "spec.template.spec.containers[0].livenessProbe.initialDelaySeconds", Type: "number", Description: "Seconds to wait after start before the first liveness check."},
+				{Path: "spec.template.spec.containers[0].resources.requests.cpu", Description: "CPU reserved for scheduling (e.g. 250m)."},
+				{Path: "spec.template.spec.containers[0].resources.requests.memory", Description: "Memory reserved for scheduling (e.g. 256Mi)."},
+				{Path: "spec.template.spec.containers[0].resources.limits.cpu", Description: "CPU ceiling; usage above it is throttled."},
+				{Path: "spec.template.spec.containers[0].resources.limits.memory", Description: "Memory ceiling; exceeding it gets the container OOM-killed."},
 			},
 		},
 		{
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+func TestPatchTemplateUpdatesOnlyNote(t *testing.T) {
+	service := &TemplateService{templates: defaultTemplates()}
+	original := models.TemplateDefinition{
+		ID:         "parsed-widget",
+		Title:      "Widget (Parsed)",
+		APIVersion: "example.io/v1",
+		Kind:       "Widget",
+		Note:       "Generated from CRD schema.",
+		DefaultFields: []models.FieldDefinition{
+			{Path: "metadata.name", Value: "widget-sample"},
+			{Path: "spec.size", Value: "small"},
+		},
+		OptionalFields: []models.FieldDefinition{
+			{Path: "metadata.labels.app"},
+		},
+	}
+	if err := service.Upsert(context.Background(), original); err != nil {
+		t.Fatalf("upsert template: %v", err)
+	}
+
+	note := "Reviewed by platform team."
+	patched, err := service.Patch(context.Background(), "parsed-widget", models.PatchTemplateRequest{Note: &note})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if patched.Note != note {
+		t.Fatalf("expected note %q, got %q", note, patched.Note)
+	}
+	if patched.Title != original.Title {
+		t.Fatalf("expected title to be untouched, got %q", patched.Title)
+	}
+	if !reflect.DeepEqual(patched.DefaultFields, original.DefaultFields) {
+		t.Fatalf("expected default fields to be untouched, got %+v", patched.DefaultFields)
+	}
+	if !reflect.DeepEqual(patched.OptionalFields, original.OptionalFields) {
+		t.Fatalf("expected optional fields to be untouched, got %+v", patched.OptionalFields)
+	}
+}
+
+func TestPatchTemplateRejectsBuiltins(t *testing.T) {
+	service := &TemplateService{templates: defaultTemplates()}
+	note := "edited"
+	_, err := service.Patch(context.Background(), "deployment", models.PatchTemplateRequest{Note: &note})
+	if !errors.Is(err, ErrBuiltinTemplate) {
+		t.Fatalf("expected ErrBuiltinTemplate, got %v", err)
+	}
+}
+
+func TestGetAndDeleteTemplate(t *testing.T) {
+	service := &TemplateService{templates: defaultTemplates()}
+	ctx := context.Background()
+
+	template, err := service.Get(ctx, "deployment")
+	if err != nil || template.Kind != "Deployment" {
+		t.Fatalf("expected built-in deployment template, got %+v (%v)", template, err)
+	}
+	if _, err := service.Get(ctx, "missing"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("expected ErrTemplateNotFound, got %v", err)
+	}
+
+	if err := service.Delete(ctx, "deployment"); err != nil {
+		t.Fatalf("expected seeded default to be deletable, got %v", err)
+	}
+	if _, err := service.Get(ctx, "deployment"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("expected deleted template to be gone, got %v", err)
+	}
+	if err := service.Delete(ctx, "deployment"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("expected ErrTemplateNotFound deleting twice, got %v", err)
+	}
+	if count, _ := service.Count(ctx); count != int64(len(defaultTemplates())-1) {
+		t.Fatalf("expected one fewer template, got %d", count)
+	}
+}
+
+func TestImportTemplateBundleStoresEachTemplate(t *testing.T) {
+	service := &TemplateService{templates: defaultTemplates()}
+	ctx := context.Background()
+	bundle := `
+- id: team-widget
+  title: Widget
+  apiVersion: example.io/v1
+  kind: Widget
+  note: Team standard widget.
+  defaultFields:
+    - path: metadata.name
+      value: widget-sample
+    - path: spec.size
+      value: small
+  optionalFields: []
+- id: team-gadget
+  title: Gadget
+  apiVersion: example.io/v1
+  kind: Gadget
+  note: Team standard gadget.
+  defaultFields:
+    - path: metadata.name
+      value: gadget-sample
+  optionalFields: []
+- id: ""
+  kind: Broken
+`
+	templates, err := DecodeTemplateBundle([]byte(bundle))
+	if err != nil {
+		t.Fatalf("decode bundle: %v", err)
+	}
+
+	result := ImportTemplateBundle(ctx, service, templates, "")
+	if result.Created != 2 || result.Updated != 0 || result.Rejected != 1 || len(result.Errors) != 1 {
+		t.Fatalf("expected two created and one rejected, got %+v", result)
+	}
+	for _, id := range []string{"team-widget", "team-gadget"} {
+		stored, err := service.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("expected %s to be stored: %v", id, err)
+		}
+		if len(stored.DefaultFields) == 0 || stored.DefaultFields[0].Path != "metadata.name" {
+			t.Fatalf("expected fields to decode from YAML keys, got %+v", stored.DefaultFields)
+		}
+	}
+
+	again := ImportTemplateBundle(ctx, service, templates[:1], "")
+	if again.Created != 0 || again.Updated != 1 {
+		t.Fatalf("expected re-import to count as an update, got %+v", again)
+	}
+	if _, err := DecodeTemplateBundle([]byte(`{"id": "single"}`)); err == nil {
+		t.Fatal("expected a non-array bundle to be rejected")
+	}
+}
+
+func TestImportTemplateBundleScopesToTheImportingTeam(t *testing.T) {
+	ctx := context.Background()
+	service := &TemplateService{templates: defaultTemplates()}
+	bundle := []models.TemplateDefinition{{
+		ID:            "team-widget",
+		Title:         "Widget",
+		APIVersion:    "example.io/v1",
+		Kind:          "Widget",
+		Scope:         "team-b",
+		DefaultFields: []models.FieldDefinition{{Path: "metadata.name", Value: "widget-sample"}},
+	}}
+
+	if result := ImportTemplateBundle(ctx, service, bundle, "team-a"); result.Created != 1 {
+		t.Fatalf("expected the template to be created, got %+v", result)
+	}
+	stored, err := service.Get(ctx, "team-widget")
+	if err != nil || stored.Scope != "team-a" {
+		t.Fatalf("expected the importing team's scope to win over the bundle's, got %+v (%v)", stored, err)
+	}
+
+	if result := ImportTemplateBundle(ctx, service, bundle, "team-b"); result.Rejected != 1 {
+		t.Fatalf("expected another team's import of the same id to be rejected, got %+v", result)
+	}
+	if stored, _ := service.Get(ctx, "team-widget"); stored.Scope != "team-a" {
+		t.Fatalf("expected team-a to keep its template, got scope %q", stored.Scope)
+	}
+}
+
+func TestExportTemplateBundleRoundTripsThroughImport(t *testing.T) {
+	ctx := context.Background()
+	source := &TemplateService{templates: defaultTemplates()}
+	for _, id := range []string{"team-zeta", "team-alpha"} {
+		if err := source.Upsert(ctx, models.TemplateDefinition{
+			ID:            id,
+			Title:         id,
+			APIVersion:    "example.io/v1",
+			Kind:          "Widget",
+			DefaultFields: []models.FieldDefinition{{Path: "spec.size", Value: "small", Enum: []string{"small", "large"}}},
+		}); err != nil {
+			t.Fatalf("upsert template: %v", err)
+		}
+	}
+	templates, err := source.List(ctx)
+	if err != nil {
+		t.Fatalf("list templates: %v", err)
+	}
+
+	for _, format := range []string{"json", "yaml"} {
+		bundle, err := ExportTemplateBundle(templates, false, format)
+		if err != nil {
+			t.Fatalf("export %s bundle: %v", format, err)
+		}
+		decoded, err := DecodeTemplateBundle(bundle)
+		if err != nil {
+			t.Fatalf("decode %s bundle: %v", format, err)
+		}
+		if len(decoded) != 2 || decoded[0].ID != "team-alpha" || decoded[1].ID != "team-zeta" {
+			t.Fatalf("expected only custom templates sorted by id in the %s bundle, got %+v", format, decoded)
+		}
+
+		target := &TemplateService{templates: defaultTemplates()}
+		result := ImportTemplateBundle(ctx, target, decoded, "")
+		if result.Created != 2 || result.Rejected != 0 {
+			t.Fatalf("expected the %s export to import cleanly, got %+v", format, result)
+		}
+		imported, err := target.Get(ctx, "team-alpha")
+		if err != nil {
+			t.Fatalf("get imported template: %v", err)
+		}
+		original, _ := source.Get(ctx, "team-alpha")
+		if !reflect.DeepEqual(imported, original) {
+			t.Fatalf("expected %s round trip to preserve the template, got %+v want %+v", format, imported, original)
+		}
+	}
+
+	withBuiltins, err := ExportTemplateBundle(templates, true, "json")
+	if err != nil {
+		t.Fatalf("export with built-ins: %v", err)
+	}
+	if !strings.Contains(string(withBuiltins), `"id": "deployment"`) {
+		t.Fatalf("expected built-ins when requested")
+	}
+}
+
+func TestLoadSeedTemplatesFromJSON(t *testing.T) {
+	source := `[
+		{
+			"id": "team-web",
+			"title": "Team Web",
+			"apiVersion": "apps/v1",
+			"kind": "Deployment",
+			"defaultFields": [{"path": "metadata.name", "value": "web"}]
+		}
+	]`
+
+	templates, err := loadSeedTemplates(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(templates) != 1 || templates[0].ID != "team-web" {
+		t.Fatalf("expected seeded team-web template, got %+v", templates)
+	}
+
+	service := &TemplateService{templates: templates, seeds: templates}
+	listed, err := service.List(context.Background())
+	if err != nil {
+		t.Fatalf("list templates: %v", err)
+	}
+	if len(listed) != 1 || listed[0].Kind != "Deployment" {
+		t.Fatalf("expected seeded templates to be listed, got %+v", listed)
+	}
+}
+
+func TestLoadSeedTemplatesRejectsInvalidEntries(t *testing.T) {
+	invalid := []string{
+		`[]`,
+		`[{"id": "", "apiVersion": "v1", "kind": "Service"}]`,
+		`[{"id": "svc", "apiVersion": "v1", "kind": "Service", "defaultFields": [{"path": ""}]}]`,
+		`[{"id": "svc", "apiVersion": "v1", "kind": "Service"}, {"id": "svc", "apiVersion": "v1", "kind": "Service"}]`,
+		`not json`,
+	}
+	for _, source := range invalid {
+		if _, err := loadSeedTemplates(strings.NewReader(source)); err == nil {
+			t.Fatalf("expected error for seed source %s", source)
+		}
+	}
+
+	if got := seedTemplates(""); len(got) != len(defaultTemplates()) {
+		t.Fatalf("expected built-in templates when no seed file is configured")
+	}
+	if got := seedTemplates("testdata/does-not-exist.json"); len(got) != len(defaultTemplates()) {
+		t.Fatalf("expected built-in templates when the seed file is missing")
+	}
+}
+
+func TestImportedCRDDoesNotClobberBuiltinTemplate(t *testing.T) {
+	service := &TemplateService{templates: defaultTemplates()}
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Deployment
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                size:
+                  type: string
+`
+	template, err := NewCRDService(config.Config{}).ParseCRD(raw)
+	if err != nil {
+		t.Fatalf("parse CRD: %v", err)
+	}
+	template.ID, err = UniqueTemplateID(context.Background(), service, template)
+	if err != nil {
+		t.Fatalf("allocate id: %v", err)
+	}
+	if template.ID != "parsed-deployment" {
+		t.Fatalf("expected parsed id parsed-deployment, got %q", template.ID)
+	}
+	if err := service.Upsert(context.Background(), template); err != nil {
+		t.Fatalf("upsert template: %v", err)
+	}
+
+	templates, err := service.List(context.Background())
+	if err != nil {
+		t.Fatalf("list templates: %v", err)
+	}
+	found := map[string]models.TemplateDefinition{}
+	for _, item := range templates {
+		found[item.ID] = item
+	}
+	if builtin := found["deployment"]; builtin.APIVersion != "apps/v1" {
+		t.Fatalf("expected built-in deployment to be untouched, got %+v", builtin)
+	}
+	if imported := found["parsed-deployment"]; imported.APIVersion != "example.io/v1" {
+		t.Fatalf("expected imported CRD under parsed-deployment, got %+v", imported)
+	}
+}
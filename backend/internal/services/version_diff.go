@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+// DiffCRDVersions compares the spec schemas of two versions declared by a
+// CRD. Fields removed in the target version and fields whose type changed
+// are breaking: manifests written against the old version stop validating.
+// An empty to defaults to the storage version and an empty from to the
+// first other declared version. Versions without their own schema use the
+// CRD-wide spec.validation schema of v1beta1 CRDs.
+func (s *CRDService) DiffCRDVersions(raw, from, to string) (models.VersionDiffResponse, error) {
+	docs, err := decodeYAMLDocuments(strings.TrimSpace(normalizeLineEndings(raw)))
+	if err != nil {
+		return models.VersionDiffResponse{}, err
+	}
+	root, ok := selectPrimaryResourceDoc(docs)
+	if !ok || !strings.EqualFold(asString(root["kind"]), "CustomResourceDefinition") {
+		return models.VersionDiffResponse{}, errors.New("payload does not contain a CustomResourceDefinition")
+	}
+	versions, _ := nested(root, "spec", "versions").([]any)
+	if len(versions) < 2 {
+		return models.VersionDiffResponse{}, errors.New("CRD must declare at least two versions to compare")
+	}
+
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if to == "" {
+		to = preferredVersionName(versions)
+	}
+	if from == "" {
+		for _, entry := range versions {
+			versionMap, _ := entry.(map[string]any)
+			if name := asString(versionMap["name"]); name != "" && name != to {
+				from = name
+				break
+			}
+		}
+	}
+	fromSchema, err := versionSpecSchema(root, versions, from)
+	if err != nil {
+		return models.VersionDiffResponse{}, err
+	}
+	toSchema, err := versionSpecSchema(root, versions, to)
+	if err != nil {
+		return models.VersionDiffResponse{}, err
+	}
+
+	fromTypes := s.schemaFieldTypes(fromSchema)
+	toTypes := s.schemaFieldTypes(toSchema)
+
+	result := models.VersionDiffResponse{
+		From:        from,
+		To:          to,
+		Added:       make([]string, 0),
+		Removed:     make([]string, 0),
+		TypeChanges: make([]models.FieldTypeChange, 0),
+	}
+	for _, path := range sortedKeys(fromTypes) {
+		toType, ok := toTypes[path]
+		switch {
+		case !ok:
+			result.Removed = append(result.Removed, path)
+		case fromTypes[path] != "" && toType != "" && fromTypes[path] != toType:
+			result.TypeChanges = append(result.TypeChanges, models.FieldTypeChange{Path: path, From: fromTypes[path], To: toType})
+		}
+	}
+	for _, path := range sortedKeys(toTypes) {
+		if _, ok := fromTypes[path]; !ok {
+			result.Added = append(result.Added, path)
+		}
+	}
+	result.Breaking = len(result.Removed) > 0 || len(result.TypeChanges) > 0
+	return result, nil
+}
+
+// versionSpecSchema returns the spec schema of the named version, falling
+// back to the shared spec.validation schema when the version has none.
+func versionSpecSchema(root map[string]any, versions []any, name string) (map[string]any, error) {
+	for _, entry := range versions {
+		versionMap, _ := entry.(map[string]any)
+		if asString(versionMap["name"]) != name {
+			continue
+		}
+		spec, _ := nested(versionMap, "schema", "openAPIV3Schema", "properties", "spec").(map[string]any)
+		if spec == nil {
+			spec, _ = nested(root, "spec", "validation", "openAPIV3Schema", "properties", "spec").(map[string]any)
+		}
+		if spec == nil {
+			return nil, fmt.Errorf("version %q has no spec schema", name)
+		}
+		return spec, nil
+	}
+	return nil, fmt.Errorf("version %q is not declared by the CRD", name)
+}
+
+// schemaFieldTypes maps every field the parser would collect from a spec
+// schema to its type, walking to the configured depth without the curated
+// field limit.
+func (s *CRDService) schemaFieldTypes(specSchema map[string]any) map[string]string {
+	properties, _ := specSchema["properties"].(map[string]any)
+	collected := make([]schemaFieldCandidate, 0, len(properties))
+	collectSchemaFields("spec", properties, parseRequiredSet(specSchema["required"]), 0, s.limits.withDefaults().MaxDepth, allFieldsLimit, make(schemaAncestors), &collected)
+
+	types := make(map[string]string, len(collected))
+	for _, candidate := range collected {
+		types[candidate.Field.Path] = candidate.Field.Type
+	}
+	return types
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+func TestDiffCRDVersionsReportsTypeChangesAsBreaking(t *testing.T) {
+	crd := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1alpha1
+      served: true
+      storage: false
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                replicas:
+                  type: string
+                legacyMode:
+                  type: boolean
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                replicas:
+                  type: integer
+                paused:
+                  type: boolean
+`
+	result, err := NewCRDService(config.Config{}).DiffCRDVersions(crd, "", "")
+	if err != nil {
+		t.Fatalf("diff versions: %v", err)
+	}
+	if result.From != "v1alpha1" || result.To != "v1" {
+		t.Fatalf("expected to compare v1alpha1 against the storage version, got %s -> %s", result.From, result.To)
+	}
+	if !result.Breaking {
+		t.Fatalf("expected type change to be breaking, got %+v", result)
+	}
+	expected := models.FieldTypeChange{Path: "spec.replicas", From: "string", To: "number"}
+	if !slices.Equal(result.TypeChanges, []models.FieldTypeChange{expected}) {
+		t.Fatalf("expected %+v, got %+v", expected, result.TypeChanges)
+	}
+	if !slices.Equal(result.Removed, []string{"spec.legacyMode"}) || !slices.Equal(result.Added, []string{"spec.paused"}) {
+		t.Fatalf("expected legacyMode removed and paused added, got %+v", result)
+	}
+
+	if _, err := NewCRDService(config.Config{}).DiffCRDVersions(crd, "v1alpha1", "v2"); err == nil {
+		t.Fatal("expected an error for an undeclared version")
+	}
+}
+
+func TestDiffCRDVersionsDefaultsToANonStorageVersionAndLegacyValidation(t *testing.T) {
+	crd := `apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+spec:
+  group: example.io
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                size:
+                  type: string
+    - name: v1beta1
+      served: true
+      storage: false
+  validation:
+    openAPIV3Schema:
+      type: object
+      properties:
+        spec:
+          type: object
+          properties:
+            size:
+              type: string
+            legacyMode:
+              type: boolean
+`
+	result, err := NewCRDService(config.Config{}).DiffCRDVersions(crd, "", "")
+	if err != nil {
+		t.Fatalf("diff versions: %v", err)
+	}
+	if result.From != "v1beta1" || result.To != "v1" {
+		t.Fatalf("expected the non-storage version to be compared with the storage one, got %s -> %s", result.From, result.To)
+	}
+	if !slices.Equal(result.Removed, []string{"spec.legacyMode"}) || !result.Breaking {
+		t.Fatalf("expected legacyMode from spec.validation to be reported removed, got %+v", result)
+	}
+}
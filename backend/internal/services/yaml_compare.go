@@ -0,0 +1,290 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// CompareYAML reports whether two YAML inputs describe the same structure,
+// ignoring key order and formatting differences between equivalent scalars.
+func (s *YAMLService) CompareYAML(left, right string) (models.CompareYAMLResponse, error) {
+	leftValue, err := decodeNormalizedYAML(left)
+	if err != nil {
+		return models.CompareYAMLResponse{}, fmt.Errorf("left: %w", err)
+	}
+	rightValue, err := decodeNormalizedYAML(right)
+	if err != nil {
+		return models.CompareYAMLResponse{}, fmt.Errorf("right: %w", err)
+	}
+
+	differences := make([]string, 0)
+	collectDifferences("", leftValue, rightValue, &differences)
+	return models.CompareYAMLResponse{
+		Equal:       len(differences) == 0,
+		Differences: differences,
+	}, nil
+}
+
+// decodeNormalizedYAML decodes every document in raw into plain Go values with
+// normalized scalars. A single document is returned as-is; several documents
+// are returned as a slice in input order.
+func decodeNormalizedYAML(raw string) (any, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, errors.New("yaml payload is empty")
+	}
+
+	decoder := yaml.NewDecoder(strings.NewReader(raw))
+	normalizer := &yamlNormalizer{expanding: make(map[*yaml.Node]bool)}
+	docs := make([]any, 0, 1)
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("YAML parse error: %w", err)
+		}
+		if len(node.Content) == 0 {
+			continue
+		}
+		value, err := normalizer.normalize(node.Content[0])
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, value)
+	}
+
+	switch len(docs) {
+	case 0:
+		return nil, errors.New("no YAML documents found")
+	case 1:
+		return docs[0], nil
+	default:
+		return docs, nil
+	}
+}
+
+// maxNormalizedYAMLNodes caps how many nodes one payload may expand to once
+// aliases are followed, so a small billion-laughs document can't pin a CPU.
+const maxNormalizedYAMLNodes = 1_000_000
+
+// yamlNormalizer converts a yaml.Node tree into plain Go values. It follows
+// aliases itself, so it tracks the anchors currently being expanded to reject
+// self-referencing ones and counts emitted nodes against
+// maxNormalizedYAMLNodes.
+type yamlNormalizer struct {
+	expanding map[*yaml.Node]bool
+	visited   int
+}
+
+func (n *yamlNormalizer) normalize(node *yaml.Node) (any, error) {
+	n.visited++
+	if n.visited > maxNormalizedYAMLNodes {
+		return nil, fmt.Errorf("YAML document expands to more than %d nodes", maxNormalizedYAMLNodes)
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return n.normalize(node.Content[0])
+	case yaml.AliasNode:
+		if n.expanding[node.Alias] {
+			return nil, fmt.Errorf("anchor %q value contains itself", node.Value)
+		}
+		n.expanding[node.Alias] = true
+		defer delete(n.expanding, node.Alias)
+		return n.normalize(node.Alias)
+	case yaml.MappingNode:
+		out := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := n.normalize(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			out[node.Content[i].Value] = value
+		}
+		return out, nil
+	case yaml.SequenceNode:
+		out := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := n.normalize(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, value)
+		}
+		return out, nil
+	default:
+		return normalizeScalar(node), nil
+	}
+}
+
+// normalizeScalar folds scalar spellings that Kubernetes treats alike: YAML
+// 1.1 booleans written plain (yes/no/on/off) become bools, and all numbers
+// become float64 so 1 and 1.0 compare equal. Quoted strings stay strings.
+func normalizeScalar(node *yaml.Node) any {
+	plain := node.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0
+	switch node.ShortTag() {
+	case "!!null":
+		return nil
+	case "!!bool":
+		return strings.EqualFold(node.Value, "true")
+	case "!!int", "!!float":
+		var value float64
+		if err := node.Decode(&value); err == nil {
+			return value
+		}
+		return node.Value
+	}
+	if plain {
+		switch strings.ToLower(node.Value) {
+		case "yes", "on", "y":
+			return true
+		case "no", "off", "n":
+			return false
+		}
+	}
+	return node.Value
+}
+
+// DiffYAML compares two YAML inputs and lists the paths that
+// were added, removed or changed from left to right with their values. Values
+// are normalized the same way CompareYAML does, so formatting-only edits do
+// not show up.
+func (s *YAMLService) DiffYAML(left, right string) (models.YAMLDiff, error) {
+	leftValue, err := decodeNormalizedYAML(left)
+	if err != nil {
+		return models.YAMLDiff{}, fmt.Errorf("left: %w", err)
+	}
+	rightValue, err := decodeNormalizedYAML(right)
+	if err != nil {
+		return models.YAMLDiff{}, fmt.Errorf("right: %w", err)
+	}
+
+	diff := models.YAMLDiff{
+		Added:   make([]models.YAMLDiffEntry, 0),
+		Removed: make([]models.YAMLDiffEntry, 0),
+		Changed: make([]models.YAMLDiffEntry, 0),
+	}
+	walkDifferences("", leftValue, rightValue, func(kind diffKind, path string, before, after any) {
+		entry := models.YAMLDiffEntry{Path: displayPath(path), Old: before, New: after}
+		switch kind {
+		case diffAdded:
+			diff.Added = append(diff.Added, entry)
+		case diffRemoved:
+			diff.Removed = append(diff.Removed, entry)
+		default:
+			diff.Changed = append(diff.Changed, entry)
+		}
+	})
+	diff.Equal = len(diff.Added)+len(diff.Removed)+len(diff.Changed) == 0
+	return diff, nil
+}
+
+type diffKind int
+
+const (
+	diffChanged diffKind = iota
+	diffAdded
+	diffRemoved
+)
+
+func collectDifferences(path string, left, right any, out *[]string) {
+	walkDifferences(path, left, right, func(_ diffKind, path string, _, _ any) {
+		*out = append(*out, displayPath(path))
+	})
+}
+
+// walkDifferences recurses through matching maps and lists and calls visit
+// for each path present on only one side or whose values differ. A change of
+// shape, such as a map replaced by a scalar, is reported once at that path.
+func walkDifferences(path string, left, right any, visit func(kind diffKind, path string, before, after any)) {
+	switch leftTyped := left.(type) {
+	case map[string]any:
+		rightTyped, ok := right.(map[string]any)
+		if !ok {
+			visit(diffChanged, path, left, right)
+			return
+		}
+		keys := make([]string, 0, len(leftTyped)+len(rightTyped))
+		for key := range leftTyped {
+			keys = append(keys, key)
+		}
+		for key := range rightTyped {
+			if _, exists := leftTyped[key]; !exists {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			leftValue, leftOK := leftTyped[key]
+			rightValue, rightOK := rightTyped[key]
+			childPath := joinDiffPath(path, key)
+			switch {
+			case !leftOK:
+				visit(diffAdded, childPath, nil, rightValue)
+			case !rightOK:
+				visit(diffRemoved, childPath, leftValue, nil)
+			default:
+				walkDifferences(childPath, leftValue, rightValue, visit)
+			}
+		}
+	case []any:
+		rightTyped, ok := right.([]any)
+		if !ok {
+			visit(diffChanged, path, left, right)
+			return
+		}
+		longest := max(len(leftTyped), len(rightTyped))
+		for i := 0; i < longest; i++ {
+			childPath := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(leftTyped):
+				visit(diffAdded, childPath, nil, rightTyped[i])
+			case i >= len(rightTyped):
+				visit(diffRemoved, childPath, leftTyped[i], nil)
+			default:
+				walkDifferences(childPath, leftTyped[i], rightTyped[i], visit)
+			}
+		}
+	default:
+		if !scalarsEqual(left, right) {
+			visit(diffChanged, path, left, right)
+		}
+	}
+}
+
+func scalarsEqual(left, right any) bool {
+	leftFloat, leftIsFloat := left.(float64)
+	rightFloat, rightIsFloat := right.(float64)
+	if leftIsFloat && rightIsFloat {
+		return leftFloat == rightFloat || (math.IsNaN(leftFloat) && math.IsNaN(rightFloat))
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+func joinDiffPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aneeshchawla/kubetools/backend/internal/models"
+)
+
+func TestCompareYAMLIgnoresKeyOrderAndScalarSpelling(t *testing.T) {
+	service := NewYAMLService()
+	left := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+    tier: frontend
+spec:
+  replicas: 3
+  paused: yes
+`
+	right := `kind: Deployment
+spec:
+  paused: true
+  replicas: 3.0
+metadata:
+  labels:
+    tier: frontend
+    app: web
+  name: web
+apiVersion: apps/v1
+`
+
+	result, err := service.CompareYAML(left, right)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Equal || len(result.Differences) != 0 {
+		t.Fatalf("expected documents to be equal, got %+v", result)
+	}
+}
+
+func TestCompareYAMLReportsDifferingPaths(t *testing.T) {
+	service := NewYAMLService()
+	left := `kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+        - name: app
+          image: nginx:1.27
+`
+	right := `kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  replicas: 5
+  template:
+    spec:
+      containers:
+        - name: app
+          image: nginx:1.28
+`
+
+	result, err := service.CompareYAML(left, right)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Equal {
+		t.Fatalf("expected documents to differ")
+	}
+	expected := []string{"metadata.namespace", "spec.replicas", "spec.template.spec.containers[0].image"}
+	if !reflect.DeepEqual(result.Differences, expected) {
+		t.Fatalf("expected differences %v, got %v", expected, result.Differences)
+	}
+
+	quoted, err := service.CompareYAML(`enabled: "yes"`, `enabled: true`)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if quoted.Equal {
+		t.Fatalf("expected a quoted \"yes\" string to differ from boolean true")
+	}
+}
+
+func TestDiffYAMLReportsOldAndNewValuesPerPath(t *testing.T) {
+	service := NewYAMLService()
+	left := `kind: Deployment
+metadata:
+  name: web
+  labels:
+    tier: frontend
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+        - name: app
+          image: nginx:1.27
+`
+	right := `kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  replicas: 5.0
+  template:
+    spec:
+      containers:
+        - name: app
+          image: nginx:1.28
+        - name: sidecar
+          image: envoy:1.30
+`
+
+	diff, err := service.DiffYAML(left, right)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if diff.Equal {
+		t.Fatalf("expected documents to differ")
+	}
+	expectedAdded := []models.YAMLDiffEntry{
+		{Path: "metadata.namespace", New: "prod"},
+		{Path: "spec.template.spec.containers[1]", New: map[string]any{"name": "sidecar", "image": "envoy:1.30"}},
+	}
+	if !reflect.DeepEqual(diff.Added, expectedAdded) {
+		t.Fatalf("expected added %+v, got %+v", expectedAdded, diff.Added)
+	}
+	expectedRemoved := []models.YAMLDiffEntry{{Path: "metadata.labels", Old: map[string]any{"tier": "frontend"}}}
+	if !reflect.DeepEqual(diff.Removed, expectedRemoved) {
+		t.Fatalf("expected removed %+v, got %+v", expectedRemoved, diff.Removed)
+	}
+	expectedChanged := []models.YAMLDiffEntry{
+		{Path: "spec.replicas", Old: float64(3), New: float64(5)},
+		{Path: "spec.template.spec.containers[0].image", Old: "nginx:1.27", New: "nginx:1.28"},
+	}
+	if !reflect.DeepEqual(diff.Changed, expectedChanged) {
+		t.Fatalf("expected changed %+v, got %+v", expectedChanged, diff.Changed)
+	}
+
+	same, err := service.DiffYAML("a: 1\nb: [x]\n", "b: [x]\na: 1.0\n")
+	if err != nil || !same.Equal {
+		t.Fatalf("expected formatting-only edits to be equal, got %+v (%v)", same, err)
+	}
+}
+
+func TestCompareYAMLRejectsSelfReferencingAnchor(t *testing.T) {
+	service := NewYAMLService()
+	_, err := service.CompareYAML("a: &x {b: *x}\n", "a: 1\n")
+	if err == nil || !strings.Contains(err.Error(), "contains itself") {
+		t.Fatalf("expected a recursive anchor error, got %v", err)
+	}
+}
+
+func TestCompareYAMLRejectsAliasExpansionBomb(t *testing.T) {
+	service := NewYAMLService()
+	bomb := `a: &a ["lol","lol","lol","lol","lol","lol","lol","lol","lol"]
+b: &b [*a,*a,*a,*a,*a,*a,*a,*a,*a]
+c: &c [*b,*b,*b,*b,*b,*b,*b,*b,*b]
+d: &d [*c,*c,*c,*c,*c,*c,*c,*c,*c]
+e: &e [*d,*d,*d,*d,*d,*d,*d,*d,*d]
+f: &f [*e,*e,*e,*e,*e,*e,*e,*e,*e]
+g: &g [*f,*f,*f,*f,*f,*f,*f,*f,*f]
+h: &h [*g,*g,*g,*g,*g,*g,*g,*g,*g]
+i: &i [*h,*h,*h,*h,*h,*h,*h,*h,*h]
+`
+	_, err := service.CompareYAML("a: 1\n", bomb)
+	if err == nil || !strings.Contains(err.Error(), "expands to more than") {
+		t.Fatalf("expected an expansion limit error, got %v", err)
+	}
+}
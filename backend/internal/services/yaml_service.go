@@ -3,6 +3,7 @@ package services
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -12,6 +13,7 @@ import (
 
 var pathRegex = regexp.MustCompile(`([^\[]+)|\[(\d+)\]`)
 var numberRegex = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+var dns1123SubdomainRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
 
 type YAMLService struct{}
 
@@ -20,11 +22,195 @@ func NewYAMLService() *YAMLService {
 }
 
 func (s *YAMLService) GenerateYAML(apiVersion, kind string, fields []models.FieldDefinition) (string, error) {
+	return s.GenerateYAMLFromRequest(models.GenerateYAMLRequest{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Fields:     fields,
+	})
+}
+
+// GenerateYAMLFromRequest renders a resource honoring the generation options
+// carried on the request in addition to its fields.
+func (s *YAMLService) GenerateYAMLFromRequest(req models.GenerateYAMLRequest) (string, error) {
+	output, _, err := s.GenerateYAMLWithObject(req)
+	return output, err
+}
+
+// GenerateYAMLWithObject renders the request like GenerateYAMLFromRequest and
+// also returns the assembled object the YAML was marshaled from, with values
+// already coerced to their field types.
+func (s *YAMLService) GenerateYAMLWithObject(req models.GenerateYAMLRequest) (string, map[string]any, error) {
+	if req.Strict {
+		if err := checkDuplicateFieldPaths(req.Fields); err != nil {
+			return "", nil, err
+		}
+	}
+	resource, err := buildResource(req.APIVersion, req.Kind, req.Fields)
+	if err != nil {
+		return "", nil, err
+	}
+	if req.ApplyKnownDefaults {
+		applyKnownDefaults(resource, req.APIVersion, req.Kind)
+	}
+	if err := applyNameAffixes(resource, req.NamePrefix, req.NameSuffix); err != nil {
+		return "", nil, err
+	}
+
+	node, err := resourceNode(resource)
+	if err != nil {
+		return "", nil, fmt.Errorf("marshal YAML: %w", err)
+	}
+	if req.IncludeComments {
+		annotateFieldComments(node, req.Fields)
+	}
+	annotateEnumComments(node, req.Fields)
+	output, err := marshalNode(node)
+	if err != nil {
+		return "", nil, err
+	}
+	return output, resource, nil
+}
+
+// GenerateMultiYAML renders each request as its own document and joins them
+// with "---" separators in order. Requests with no apiVersion, kind or fields
+// are skipped; any other failure is reported with the document's index.
+func (s *YAMLService) GenerateMultiYAML(docs []models.GenerateYAMLRequest) (string, error) {
+	parts := make([]string, 0, len(docs))
+	for i, doc := range docs {
+		if isEmptyGenerateRequest(doc) {
+			continue
+		}
+		output, err := s.GenerateYAMLFromRequest(doc)
+		if err != nil {
+			return "", fmt.Errorf("document %d: %w", i, err)
+		}
+		parts = append(parts, output)
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("at least one document is required")
+	}
+	return strings.Join(parts, "---\n"), nil
+}
+
+// checkDuplicateFieldPaths reports every path that appears more than once
+// with differing values. Exact repeats are harmless and allowed.
+func checkDuplicateFieldPaths(fields []models.FieldDefinition) error {
+	values := make(map[string]string, len(fields))
+	conflicts := make([]string, 0)
+	reported := make(map[string]bool)
+	for _, field := range fields {
+		path := strings.TrimSpace(field.Path)
+		if path == "" {
+			continue
+		}
+		previous, seen := values[path]
+		if !seen {
+			values[path] = field.Value
+			continue
+		}
+		if previous != field.Value && !reported[path] {
+			reported[path] = true
+			conflicts = append(conflicts, path)
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return fmt.Errorf("duplicate field path: %s", strings.Join(conflicts, ", "))
+}
+
+func isEmptyGenerateRequest(req models.GenerateYAMLRequest) bool {
+	return strings.TrimSpace(req.APIVersion) == "" && strings.TrimSpace(req.Kind) == "" && len(req.Fields) == 0
+}
+
+func marshalNode(node *yaml.Node) (string, error) {
+	output, err := yaml.Marshal(node)
+	if err != nil {
+		return "", fmt.Errorf("marshal YAML: %w", err)
+	}
+	return string(output), nil
+}
+
+// canonicalKeyOrder lists, per parent key, the keys Kubernetes manifests
+// conventionally lead with. The empty parent is the document root.
+var canonicalKeyOrder = map[string][]string{
+	"":         {"apiVersion", "kind", "metadata", "spec", "status"},
+	"metadata": {"name", "namespace"},
+}
+
+// resourceNode converts the resource into an explicit yaml.Node tree so
+// mapping order is deterministic: conventional keys such as apiVersion, kind
+// and metadata come first, and everything else (labels, annotations and every
+// other string-keyed map) is sorted, independent of Go map iteration.
+func resourceNode(value any) (*yaml.Node, error) {
+	return orderedNode("", value)
+}
+
+func orderedNode(parentKey string, value any) (*yaml.Node, error) {
+	switch typed := value.(type) {
+	case map[string]any:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range canonicalKeys(parentKey, typed) {
+			child, err := orderedNode(key, typed[key])
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+		}
+		return node, nil
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range typed {
+			child, err := orderedNode(parentKey, item)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		return node, nil
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(typed); err != nil {
+			return nil, err
+		}
+		// Multiline values such as embedded config files read best as block
+		// scalars. The encoder still falls back to quoting when a block
+		// scalar can't represent the value exactly.
+		if text, ok := typed.(string); ok && strings.Contains(text, "\n") {
+			node.Style = yaml.LiteralStyle
+		}
+		return node, nil
+	}
+}
+
+// canonicalKeys returns the map's keys with the conventional keys for
+// parentKey first, in their listed order, followed by the rest sorted.
+func canonicalKeys(parentKey string, values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	leading := make(map[string]struct{})
+	for _, key := range canonicalKeyOrder[parentKey] {
+		if _, ok := values[key]; ok {
+			keys = append(keys, key)
+			leading[key] = struct{}{}
+		}
+	}
+
+	rest := make([]string, 0, len(values)-len(keys))
+	for key := range values {
+		if _, ok := leading[key]; !ok {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+func buildResource(apiVersion, kind string, fields []models.FieldDefinition) (map[string]any, error) {
 	if strings.TrimSpace(apiVersion) == "" {
-		return "", fmt.Errorf("apiVersion is required")
+		return nil, fmt.Errorf("apiVersion is required")
 	}
 	if strings.TrimSpace(kind) == "" {
-		return "", fmt.Errorf("kind is required")
+		return nil, fmt.Errorf("kind is required")
 	}
 
 	resource := map[string]any{
@@ -39,12 +225,184 @@ func (s *YAMLService) GenerateYAML(apiVersion, kind string, fields []models.Fiel
 		}
 		setValue(resource, parsePath(path), parseValue(field.Value, field.Type))
 	}
+	return resource, nil
+}
 
-	output, err := yaml.Marshal(resource)
-	if err != nil {
-		return "", fmt.Errorf("marshal YAML: %w", err)
+func applyNameAffixes(resource map[string]any, prefix, suffix string) error {
+	prefix = strings.TrimSpace(prefix)
+	suffix = strings.TrimSpace(suffix)
+	if prefix == "" && suffix == "" {
+		return nil
+	}
+
+	metadata, _ := resource["metadata"].(map[string]any)
+	if metadata == nil {
+		return nil
+	}
+
+	if name, ok := metadata["name"].(string); ok && strings.TrimSpace(name) != "" {
+		combined := prefix + strings.TrimSpace(name) + suffix
+		if err := validateDNS1123Subdomain(combined); err != nil {
+			return fmt.Errorf("metadata.name %q: %w", combined, err)
+		}
+		metadata["name"] = combined
+	}
+
+	if generateName, ok := metadata["generateName"].(string); ok && strings.TrimSpace(generateName) != "" {
+		combined := prefix + strings.TrimSpace(generateName) + suffix
+		// The API server appends a random suffix, so a trailing dash is allowed here.
+		if err := validateDNS1123Subdomain(strings.TrimRight(combined, "-")); err != nil {
+			return fmt.Errorf("metadata.generateName %q: %w", combined, err)
+		}
+		metadata["generateName"] = combined
+	}
+	return nil
+}
+
+func validateDNS1123Subdomain(value string) error {
+	if len(value) > 253 {
+		return fmt.Errorf("must be no more than 253 characters")
+	}
+	if !dns1123SubdomainRegex.MatchString(value) {
+		return fmt.Errorf("must be a lowercase RFC 1123 subdomain (alphanumerics, '-' or '.', starting and ending with an alphanumeric)")
+	}
+	return nil
+}
+
+// MergeFieldOverrides returns base with each override replacing the field at
+// the same path; overrides for paths not in base are appended in order.
+func MergeFieldOverrides(base, overrides []models.FieldDefinition) []models.FieldDefinition {
+	merged := append([]models.FieldDefinition(nil), base...)
+	index := make(map[string]int, len(merged))
+	for i, field := range merged {
+		index[field.Path] = i
+	}
+	for _, override := range overrides {
+		override.Path = strings.TrimSpace(override.Path)
+		if override.Path == "" {
+			continue
+		}
+		if position, ok := index[override.Path]; ok {
+			if override.Type == "" {
+				override.Type = merged[position].Type
+			}
+			merged[position] = override
+			continue
+		}
+		index[override.Path] = len(merged)
+		merged = append(merged, override)
+	}
+	return merged
+}
+
+// MissingRequiredFields returns the paths of required fields that are absent
+// or empty in a generated manifest, so callers can flag manifests that still
+// need edits before they are applied. Required arrays with fewer non-empty
+// items than their minItems are reported by the array's own path.
+func (s *YAMLService) MissingRequiredFields(manifest string, fields []models.FieldDefinition) ([]string, error) {
+	var resource map[string]any
+	if err := yaml.Unmarshal([]byte(manifest), &resource); err != nil {
+		return nil, fmt.Errorf("decode manifest: %w", err)
+	}
+
+	missing := make([]string, 0)
+	reported := make(map[string]bool)
+	for _, field := range fields {
+		path := strings.TrimSpace(field.Path)
+		if path == "" {
+			continue
+		}
+		if field.Required && isEmptyValue(lookupPath(resource, parsePath(path))) {
+			missing = append(missing, path)
+			reported[path] = true
+		}
+		if field.Constraints == nil || field.Constraints.MinItems == 0 {
+			continue
+		}
+		bracket := strings.LastIndex(path, "[")
+		if bracket < 0 {
+			continue
+		}
+		arrayPath := path[:bracket]
+		if reported[arrayPath] || reported[arrayPath+"[0]"] {
+			continue
+		}
+		items, _ := lookupPath(resource, parsePath(arrayPath)).([]any)
+		if countFilledItems(items) < field.Constraints.MinItems {
+			missing = append(missing, arrayPath)
+			reported[arrayPath] = true
+		}
+	}
+	return missing, nil
+}
+
+func countFilledItems(items []any) int {
+	filled := 0
+	for _, item := range items {
+		if !isBlankValue(item) {
+			filled++
+		}
+	}
+	return filled
+}
+
+// isBlankValue reports whether value holds nothing but empty strings, maps
+// and lists, such as an array element whose fields were all left blank.
+func isBlankValue(value any) bool {
+	switch typed := value.(type) {
+	case map[string]any:
+		for _, child := range typed {
+			if !isBlankValue(child) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		for _, child := range typed {
+			if !isBlankValue(child) {
+				return false
+			}
+		}
+		return true
+	default:
+		return isEmptyValue(value)
+	}
+}
+
+func lookupPath(root map[string]any, segments []any) any {
+	var current any = root
+	for _, segment := range segments {
+		switch key := segment.(type) {
+		case string:
+			obj, ok := current.(map[string]any)
+			if !ok {
+				return nil
+			}
+			current = obj[key]
+		case int:
+			arr, ok := current.([]any)
+			if !ok || key >= len(arr) {
+				return nil
+			}
+			current = arr[key]
+		}
+	}
+	return current
+}
+
+func isEmptyValue(value any) bool {
+	switch typed := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(typed) == ""
+	case map[string]any:
+		return len(typed) == 0
+	case []any:
+		return len(typed) == 0
+	default:
+		return false
 	}
-	return string(output), nil
 }
 
 func parsePath(path string) []any {
@@ -67,6 +425,11 @@ func parsePath(path string) []any {
 }
 
 func parseValue(value string, valueType string) any {
+	// An explicit string type keeps values such as "123" or "true" as
+	// strings; the encoder quotes them so they survive a round trip.
+	if valueType == "string" {
+		return value
+	}
 	trimmed := strings.TrimSpace(value)
 	if valueType == "number" || numberRegex.MatchString(trimmed) {
 		floatValue, err := strconv.ParseFloat(trimmed, 64)
@@ -77,7 +440,9 @@ func parseValue(value string, valueType string) any {
 			return floatValue
 		}
 	}
-	if valueType == "boolean" || trimmed == "true" || trimmed == "false" {
+	// Booleans are inferred only for untyped fields; a field typed as a
+	// number keeps a stray "true" as text rather than changing its kind.
+	if valueType == "boolean" || (valueType == "" && (trimmed == "true" || trimmed == "false")) {
 		return trimmed == "true"
 	}
 	return value
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/aneeshchawla/kubetools/backend/internal/config"
 	"github.com/aneeshchawla/kubetools/backend/internal/models"
+	"gopkg.in/yaml.v3"
 )
 
 func TestGenerateYAML(t *testing.T) {
@@ -28,3 +31,654 @@ func TestGenerateYAML(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateYAMLAppliesNameAffixes(t *testing.T) {
+	service := NewYAMLService()
+	output, err := service.GenerateYAMLFromRequest(models.GenerateYAMLRequest{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		NamePrefix: "team-",
+		NameSuffix: "-prod",
+		Fields: []models.FieldDefinition{
+			{Path: "metadata.name", Value: "web-app"},
+			{Path: "metadata.generateName", Value: "job-"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "name: team-web-app-prod") {
+		t.Fatalf("expected prefixed and suffixed name, got %s", output)
+	}
+	if !strings.Contains(output, "generateName: team-job--prod") {
+		t.Fatalf("expected prefixed and suffixed generateName, got %s", output)
+	}
+
+	_, err = service.GenerateYAMLFromRequest(models.GenerateYAMLRequest{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		NamePrefix: "Team_",
+		Fields:     []models.FieldDefinition{{Path: "metadata.name", Value: "web-app"}},
+	})
+	if err == nil {
+		t.Fatalf("expected RFC 1123 validation error for invalid prefix")
+	}
+}
+
+func TestGenerateYAMLWithInitAndMultipleContainers(t *testing.T) {
+	service := NewYAMLService()
+	fields := []models.FieldDefinition{
+		{Path: "metadata.name", Value: "web"},
+		{Path: "spec.template.spec.containers[1].name", Value: "log-shipper"},
+		{Path: "spec.template.spec.containers[1].image", Value: "fluent/fluent-bit:3.1"},
+		{Path: "spec.template.spec.containers[0].name", Value: "app"},
+		{Path: "spec.template.spec.containers[0].image", Value: "nginx:1.27"},
+		{Path: "spec.template.spec.initContainers[0].name", Value: "migrate"},
+		{Path: "spec.template.spec.initContainers[0].image", Value: "migrate/migrate:v4"},
+		{Path: "spec.template.spec.initContainers[0].command[0]", Value: "migrate"},
+	}
+
+	output, err := service.GenerateYAML("apps/v1", "Deployment", fields)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var decoded struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					InitContainers []struct {
+						Name    string   `yaml:"name"`
+						Image   string   `yaml:"image"`
+						Command []string `yaml:"command"`
+					} `yaml:"initContainers"`
+					Containers []struct {
+						Name  string `yaml:"name"`
+						Image string `yaml:"image"`
+					} `yaml:"containers"`
+				} `yaml:"spec"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+
+	podSpec := decoded.Spec.Template.Spec
+	if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Name != "migrate" || podSpec.InitContainers[0].Command[0] != "migrate" {
+		t.Fatalf("expected one init container named migrate, got %+v", podSpec.InitContainers)
+	}
+	if len(podSpec.Containers) != 2 {
+		t.Fatalf("expected two app containers, got %+v", podSpec.Containers)
+	}
+	if podSpec.Containers[0].Name != "app" || podSpec.Containers[1].Name != "log-shipper" {
+		t.Fatalf("expected containers in index order app, log-shipper, got %+v", podSpec.Containers)
+	}
+}
+
+func TestGenerateYAMLSortsLabelsAndAnnotations(t *testing.T) {
+	service := NewYAMLService()
+	fields := []models.FieldDefinition{
+		{Path: "metadata.name", Value: "demo"},
+		{Path: "metadata.labels.tier", Value: "backend"},
+		{Path: "metadata.labels.app", Value: "demo"},
+		{Path: "metadata.labels.env", Value: "prod"},
+		{Path: "metadata.annotations.zeta/owner", Value: "team-a"},
+		{Path: "metadata.annotations.alpha/revision", Value: "2"},
+	}
+
+	expected := "" +
+		"apiVersion: v1\n" +
+		"kind: ConfigMap\n" +
+		"metadata:\n" +
+		"    name: demo\n" +
+		"    annotations:\n" +
+		"        alpha/revision: 2\n" +
+		"        zeta/owner: team-a\n" +
+		"    labels:\n" +
+		"        app: demo\n" +
+		"        env: prod\n" +
+		"        tier: backend\n"
+
+	for run := 0; run < 20; run++ {
+		output, err := service.GenerateYAML("v1", "ConfigMap", fields)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if output != expected {
+			t.Fatalf("run %d: expected stable sorted output\n%s\ngot\n%s", run, expected, output)
+		}
+	}
+}
+
+func TestGenerateYAMLAppliesKnownDefaults(t *testing.T) {
+	service := NewYAMLService()
+	request := models.GenerateYAMLRequest{
+		APIVersion: "v1",
+		Kind:       "Service",
+		Fields: []models.FieldDefinition{
+			{Path: "metadata.name", Value: "web"},
+			{Path: "spec.ports[0].port", Value: "80", Type: "number"},
+		},
+	}
+
+	output, err := service.GenerateYAMLFromRequest(request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Contains(output, "type: ClusterIP") {
+		t.Fatalf("expected defaults to be opt-in, got %s", output)
+	}
+
+	request.ApplyKnownDefaults = true
+	output, err = service.GenerateYAMLFromRequest(request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "type: ClusterIP") {
+		t.Fatalf("expected Service to default to ClusterIP, got %s", output)
+	}
+
+	request.Fields = append(request.Fields, models.FieldDefinition{Path: "spec.type", Value: "NodePort"})
+	output, err = service.GenerateYAMLFromRequest(request)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(output, "type: NodePort") || strings.Contains(output, "ClusterIP") {
+		t.Fatalf("expected explicit type to win over the default, got %s", output)
+	}
+}
+
+func TestGenerateYAMLIncludesRichFieldComments(t *testing.T) {
+	service := NewYAMLService()
+	output, err := service.GenerateYAMLFromRequest(models.GenerateYAMLRequest{
+		APIVersion:      "example.io/v1",
+		Kind:            "Widget",
+		IncludeComments: true,
+		Fields: []models.FieldDefinition{
+			{Path: "metadata.name", Value: "demo"},
+			{
+				Path:        "spec.environment",
+				Value:       "dev",
+				Type:        "string",
+				Required:    true,
+				Enum:        []string{"dev", "prod"},
+				Description: "Target environment",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "environment: dev # string, required, one of: dev|prod — Target environment"
+	if !strings.Contains(output, expected) {
+		t.Fatalf("expected output to contain %q, got %s", expected, output)
+	}
+
+	plain, err := service.GenerateYAML("example.io/v1", "Widget", []models.FieldDefinition{
+		{Path: "spec.environment", Value: "dev", Enum: []string{"dev", "prod"}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Contains(plain, "required") || !strings.Contains(plain, "environment: dev # allowed: dev, prod\n") {
+		t.Fatalf("expected only the allowed-values comment unless rich comments are requested, got %s", plain)
+	}
+}
+
+func TestGenerateYAMLListsEnumValuesAsLineComments(t *testing.T) {
+	output, err := NewYAMLService().GenerateYAML("example.io/v1", "Widget", []models.FieldDefinition{
+		{Path: "metadata.name", Value: "demo"},
+		{Path: "spec.tier", Value: "gold", Enum: []string{"gold", "silver", "bronze"}},
+		{Path: "spec.replicas", Value: "2", Type: "integer"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !strings.Contains(output, "tier: gold # allowed: gold, silver, bronze\n") {
+		t.Fatalf("expected enum values as a line comment, got %s", output)
+	}
+	if strings.Count(output, "#") != 1 {
+		t.Fatalf("expected fields without an enum to stay uncommented, got %s", output)
+	}
+}
+
+func TestGenerateYAMLKeepsExplicitStringsQuoted(t *testing.T) {
+	output, err := NewYAMLService().GenerateYAML("v1", "ConfigMap", []models.FieldDefinition{
+		{Path: "metadata.name", Value: "settings"},
+		{Path: "metadata.labels.build", Value: "123", Type: "string"},
+		{Path: "data.enabled", Value: "true", Type: "string"},
+		{Path: "data.retries", Value: "3"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, expected := range []string{`build: "123"`, `enabled: "true"`, "retries: 3\n"} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("expected output to contain %q, got %s", expected, output)
+		}
+	}
+}
+
+func TestGenerateYAMLRendersMultilineValuesAsBlockScalars(t *testing.T) {
+	config := "server:\n  port: 8080\nlogLevel: debug"
+	output, err := NewYAMLService().GenerateYAML("v1", "ConfigMap", []models.FieldDefinition{
+		{Path: "metadata.name", Value: "settings"},
+		{Path: "data.config", Value: config},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "    config: |-\n        server:\n          port: 8080\n        logLevel: debug\n"
+	if !strings.Contains(output, expected) {
+		t.Fatalf("expected a literal block scalar, got %s", output)
+	}
+
+	var decoded struct {
+		Data map[string]string `yaml:"data"`
+	}
+	if err := yaml.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if decoded.Data["config"] != config {
+		t.Fatalf("expected multiline value to round-trip, got %q", decoded.Data["config"])
+	}
+}
+
+func TestGenerateYAMLWithReadinessProbeAndCPURequest(t *testing.T) {
+	var deployment models.TemplateDefinition
+	for _, template := range defaultTemplates() {
+		if template.ID == "deployment" {
+			deployment = template
+		}
+	}
+	values := map[string]string{
+		"spec.template.spec.containers[0].readinessProbe.httpGet.path":        "/healthz",
+		"spec.template.spec.containers[0].readinessProbe.httpGet.port":        "8080",
+		"spec.template.spec.containers[0].readinessProbe.initialDelaySeconds": "5",
+		"spec.template.spec.containers[0].resources.requests.cpu":             "250m",
+	}
+	fields := append([]models.FieldDefinition(nil), deployment.DefaultFields...)
+	for _, field := range deployment.OptionalFields {
+		if value, ok := values[field.Path]; ok {
+			field.Value = value
+			fields = append(fields, field)
+			delete(values, field.Path)
+		}
+	}
+	if len(values) != 0 {
+		t.Fatalf("expected Deployment template to offer %v", values)
+	}
+
+	output, err := NewYAMLService().GenerateYAML(deployment.APIVersion, deployment.Kind, fields)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var decoded struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					Containers []struct {
+						ReadinessProbe struct {
+							HTTPGet struct {
+								Path string `yaml:"path"`
+								Port int    `yaml:"port"`
+							} `yaml:"httpGet"`
+							InitialDelaySeconds int `yaml:"initialDelaySeconds"`
+						} `yaml:"readinessProbe"`
+						Resources struct {
+							Requests map[string]string `yaml:"requests"`
+						} `yaml:"resources"`
+					} `yaml:"containers"`
+				} `yaml:"spec"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+
+	container := decoded.Spec.Template.Spec.Containers[0]
+	probe := container.ReadinessProbe
+	if probe.HTTPGet.Path != "/healthz" || probe.HTTPGet.Port != 8080 || probe.InitialDelaySeconds != 5 {
+		t.Fatalf("expected nested readiness probe, got %+v\n%s", probe, output)
+	}
+	if container.Resources.Requests["cpu"] != "250m" {
+		t.Fatalf("expected cpu request 250m, got %+v\n%s", container.Resources.Requests, output)
+	}
+}
+
+func TestGenerateMultiYAMLJoinsDocumentsInOrder(t *testing.T) {
+	service := NewYAMLService()
+	output, err := service.GenerateMultiYAML([]models.GenerateYAMLRequest{
+		{APIVersion: "example.io/v1", Kind: "Widget", Fields: []models.FieldDefinition{{Path: "metadata.name", Value: "demo"}}},
+		{},
+		{APIVersion: "v1", Kind: "ConfigMap", Fields: []models.FieldDefinition{{Path: "data.mode", Value: "fast"}}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	decoder := yaml.NewDecoder(strings.NewReader(output))
+	kinds := make([]string, 0, 2)
+	for {
+		var doc map[string]any
+		if err := decoder.Decode(&doc); err != nil {
+			break
+		}
+		kinds = append(kinds, doc["kind"].(string))
+	}
+	if strings.Join(kinds, ",") != "Widget,ConfigMap" {
+		t.Fatalf("expected Widget then ConfigMap with the empty request skipped, got %v\n%s", kinds, output)
+	}
+
+	_, err = service.GenerateMultiYAML([]models.GenerateYAMLRequest{
+		{APIVersion: "v1", Kind: "ConfigMap"},
+		{APIVersion: "v1", Fields: []models.FieldDefinition{{Path: "data.mode", Value: "fast"}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "document 1") || !strings.Contains(err.Error(), "kind is required") {
+		t.Fatalf("expected error naming document 1, got %v", err)
+	}
+}
+
+func TestGenerateYAMLUsesCanonicalKeyOrder(t *testing.T) {
+	service := NewYAMLService()
+	fields := []models.FieldDefinition{
+		{Path: "status.replicas", Value: "0", Type: "number"},
+		{Path: "spec.replicas", Value: "2", Type: "number"},
+		{Path: "spec.template.metadata.labels.app", Value: "web"},
+		{Path: "spec.template.spec.containers[0].name", Value: "app"},
+		{Path: "metadata.labels.app", Value: "web"},
+		{Path: "metadata.namespace", Value: "prod"},
+		{Path: "metadata.name", Value: "web"},
+	}
+
+	expected := "" +
+		"apiVersion: apps/v1\n" +
+		"kind: Deployment\n" +
+		"metadata:\n" +
+		"    name: web\n" +
+		"    namespace: prod\n" +
+		"    labels:\n" +
+		"        app: web\n" +
+		"spec:\n" +
+		"    replicas: 2\n" +
+		"    template:\n" +
+		"        metadata:\n" +
+		"            labels:\n" +
+		"                app: web\n" +
+		"        spec:\n" +
+		"            containers:\n" +
+		"                - name: app\n" +
+		"status:\n" +
+		"    replicas: 0\n"
+
+	output, err := service.GenerateYAML("apps/v1", "Deployment", fields)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if output != expected {
+		t.Fatalf("expected canonical key order\n%s\ngot\n%s", expected, output)
+	}
+}
+
+func TestGenerateYAMLStrictRejectsConflictingDuplicatePaths(t *testing.T) {
+	service := NewYAMLService()
+	fields := []models.FieldDefinition{
+		{Path: "metadata.name", Value: "web"},
+		{Path: "metadata.name", Value: "web"},
+		{Path: "spec.replicas", Value: "2"},
+		{Path: "spec.image", Value: "nginx:1.25"},
+		{Path: "spec.replicas", Value: "3"},
+		{Path: "spec.image", Value: "nginx:1.27"},
+	}
+
+	_, err := service.GenerateYAMLFromRequest(models.GenerateYAMLRequest{APIVersion: "v1", Kind: "Demo", Fields: fields, Strict: true})
+	if err == nil || err.Error() != "duplicate field path: spec.replicas, spec.image" {
+		t.Fatalf("expected both conflicting paths to be reported, got %v", err)
+	}
+
+	output, err := service.GenerateYAMLFromRequest(models.GenerateYAMLRequest{APIVersion: "v1", Kind: "Demo", Fields: fields})
+	if err != nil {
+		t.Fatalf("expected last-write-wins without strict, got %v", err)
+	}
+	if !strings.Contains(output, "replicas: 3") {
+		t.Fatalf("expected the last value to win, got:\n%s", output)
+	}
+}
+
+func TestParseValueCoercesBooleansOnlyForBooleanOrUntypedFields(t *testing.T) {
+	cases := []struct {
+		value, valueType string
+		expected         any
+	}{
+		{"true", "string", "true"},
+		{"false", "boolean", false},
+		{"true", "boolean", true},
+		{"true", "", true},
+		{"true", "number", "true"},
+	}
+	for _, tc := range cases {
+		if got := parseValue(tc.value, tc.valueType); got != tc.expected {
+			t.Fatalf("parseValue(%q, %q) = %#v, expected %#v", tc.value, tc.valueType, got, tc.expected)
+		}
+	}
+}
+
+func TestParseCRD_StringDefaultsStayStringsInGeneratedYAML(t *testing.T) {
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: demo.io
+  names:
+    kind: Flag
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                value:
+                  type: string
+                  default: "true"
+                enabled:
+                  type: boolean
+                  default: true
+`
+	template, err := NewCRDService(config.Config{}).ParseCRD(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	output, err := NewYAMLService().GenerateYAML(template.APIVersion, template.Kind, template.DefaultFields)
+	if err != nil {
+		t.Fatalf("generate yaml: %v", err)
+	}
+	if !strings.Contains(output, `value: "true"`) || !strings.Contains(output, "enabled: true\n") {
+		t.Fatalf("expected a quoted string and a plain boolean, got:\n%s", output)
+	}
+}
+
+func TestMissingRequiredFieldsFlagsEmptyRequiredArrays(t *testing.T) {
+	raw := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: demo.io
+  names:
+    kind: Gateway
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required: [listeners]
+              properties:
+                listeners:
+                  type: array
+                  minItems: 1
+                  items:
+                    type: object
+                    properties:
+                      port:
+                        type: integer
+                      hostname:
+                        type: string
+                tags:
+                  type: array
+                  minItems: 1
+                  items:
+                    type: string
+`
+	template, err := NewCRDService(config.Config{}).ParseCRD(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	service := NewYAMLService()
+	fields := []models.FieldDefinition{{Path: "metadata.name", Value: "gw"}}
+	for _, field := range template.DefaultFields {
+		if strings.HasPrefix(field.Path, "spec.listeners[0].") {
+			field.Value = ""
+			fields = append(fields, field)
+		}
+	}
+	manifest, err := service.GenerateYAML(template.APIVersion, template.Kind, fields)
+	if err != nil {
+		t.Fatalf("generate yaml: %v", err)
+	}
+	missing, err := service.MissingRequiredFields(manifest, fields)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(missing) != 1 || missing[0] != "spec.listeners" {
+		t.Fatalf("expected the blank required listeners array to be flagged, got %v", missing)
+	}
+
+	for i := range fields {
+		if fields[i].Path == "spec.listeners[0].port" {
+			fields[i].Value = "443"
+		}
+	}
+	manifest, err = service.GenerateYAML(template.APIVersion, template.Kind, fields)
+	if err != nil {
+		t.Fatalf("generate yaml: %v", err)
+	}
+	missing, err = service.MissingRequiredFields(manifest, fields)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(missing) != 0 {
+		t.Fatalf("expected a filled listener to satisfy minItems, got %v", missing)
+	}
+}
+
+func TestPreviewYAMLRendersPartialInputAndReportsWhatIsMissing(t *testing.T) {
+	service := NewYAMLService()
+	req := models.GenerateYAMLRequest{
+		APIVersion: "apps/v1",
+		Fields: []models.FieldDefinition{
+			{Path: "metadata.name", Value: "web"},
+			{Path: "spec.replicas", Value: "2", Type: "integer"},
+			{Path: "spec.selector", Required: true},
+		},
+	}
+
+	if _, err := service.GenerateYAMLFromRequest(req); err == nil {
+		t.Fatalf("expected strict generation to reject a missing kind")
+	}
+
+	preview, err := service.PreviewYAML(req)
+	if err != nil {
+		t.Fatalf("expected preview to succeed, got %v", err)
+	}
+	for _, want := range []string{"apiVersion: apps/v1", "name: web", "replicas: 2"} {
+		if !strings.Contains(preview.YAML, want) {
+			t.Fatalf("expected preview to contain %q, got:\n%s", want, preview.YAML)
+		}
+	}
+	if strings.Contains(preview.YAML, "kind:") {
+		t.Fatalf("expected no kind in preview, got:\n%s", preview.YAML)
+	}
+	if !slices.Contains(preview.Missing, "kind is required") || !slices.Contains(preview.Missing, "spec.selector is required") {
+		t.Fatalf("expected missing kind and spec.selector, got %v", preview.Missing)
+	}
+	if slices.Contains(preview.Missing, "apiVersion is required") {
+		t.Fatalf("did not expect apiVersion to be reported, got %v", preview.Missing)
+	}
+}
+
+func TestGenerateYAMLWrapsLongFieldComments(t *testing.T) {
+	service := NewYAMLService()
+	fields := []models.FieldDefinition{
+		{Path: "metadata.name", Value: "demo"},
+		{
+			Path:        "spec.schedule",
+			Value:       "*/5 * * * *",
+			Type:        "string",
+			Required:    true,
+			Description: "Cron expression controlling how often the backup job runs; uses the standard five-field syntax evaluated in the controller's time zone.",
+		},
+	}
+	output, err := service.GenerateYAMLFromRequest(models.GenerateYAMLRequest{
+		APIVersion:      "example.io/v1",
+		Kind:            "Backup",
+		IncludeComments: true,
+		Fields:          fields,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	lines := strings.Split(output, "\n")
+	commentLines := 0
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		commentLines++
+		if len(trimmed) > 80 {
+			t.Fatalf("expected comment lines of at most 80 chars, got %d: %q", len(trimmed), trimmed)
+		}
+		if next := strings.TrimSpace(lines[i+1]); !strings.HasPrefix(next, "#") && !strings.HasPrefix(next, "schedule:") {
+			t.Fatalf("expected wrapped comment directly above the key, got:\n%s", output)
+		}
+	}
+	if commentLines < 2 {
+		t.Fatalf("expected the description to wrap onto several lines, got:\n%s", output)
+	}
+	if !strings.Contains(output, "schedule: '*/5 * * * *'\n") {
+		t.Fatalf("expected the key line to carry no trailing comment, got:\n%s", output)
+	}
+	if !strings.Contains(output, "# zone.") {
+		t.Fatalf("expected the full description in the comments, got:\n%s", output)
+	}
+
+	var decoded map[string]any
+	if err := yaml.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("expected commented output to stay valid YAML: %v", err)
+	}
+
+	plain, err := service.GenerateYAML("example.io/v1", "Backup", fields)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Contains(plain, "#") {
+		t.Fatalf("expected no comments unless requested, got:\n%s", plain)
+	}
+}
@@ -15,6 +15,13 @@ export interface ManifestHistoryItem {
   updatedAt: string;
 }
 
+export interface ManifestPage {
+  items: ManifestHistoryItem[];
+  total: number;
+  limit: number;
+  offset: number;
+}
+
 export interface ValidateCrdResponse {
   valid: boolean;
   errors: string[];
@@ -144,5 +151,6 @@ export async function listManifests(query?: string): Promise<ManifestHistoryItem
   params.set("limit", "50");
   const queryString = params.toString();
   const suffix = queryString ? `?${queryString}` : "";
-  return request<ManifestHistoryItem[]>(`/manifests${suffix}`);
+  const page = await request<ManifestPage>(`/manifests${suffix}`);
+  return page.items;
 }